@@ -0,0 +1,159 @@
+package performance
+
+import (
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"math/rand"
+	"testing"
+)
+
+// buildLayeredNetwork is to build fully connected acyclic network with given number of inputs, hidden layers, and
+// outputs. The BIAS sensor is connected to all neurons.
+func buildLayeredNetwork(inputs, layers, layerSize, outputs int) *network.Network {
+	rnd := rand.New(rand.NewSource(42))
+	id := 1
+	newNode := func(neuronType network.NodeNeuronType) *network.NNode {
+		node := network.NewNNode(id, neuronType)
+		id++
+		return node
+	}
+
+	in := make([]*network.NNode, 0, inputs+1)
+	for i := 0; i < inputs; i++ {
+		in = append(in, newNode(network.InputNeuron))
+	}
+	bias := newNode(network.BiasNeuron)
+	in = append(in, bias)
+	allNodes := append([]*network.NNode{}, in...)
+
+	previous := in[:inputs]
+	connectLayer := func(size int, neuronType network.NodeNeuronType) []*network.NNode {
+		layer := make([]*network.NNode, size)
+		for i := range layer {
+			layer[i] = newNode(neuronType)
+			for _, from := range previous {
+				layer[i].ConnectFrom(from, rnd.NormFloat64())
+			}
+			layer[i].ConnectFrom(bias, rnd.NormFloat64())
+		}
+		allNodes = append(allNodes, layer...)
+		return layer
+	}
+	for l := 0; l < layers; l++ {
+		previous = connectLayer(layerSize, network.HiddenNeuron)
+	}
+	out := connectLayer(outputs, network.OutputNeuron)
+
+	return network.NewNetwork(in, out, allNodes, 0)
+}
+
+// buildSparseNetwork is to build sparse acyclic network where each hidden neuron has fanIn incoming connections from
+// randomly selected sensors or preceding hidden neurons, and each output has fanIn incoming connections from randomly
+// selected hidden neurons.
+func buildSparseNetwork(inputs, hidden, outputs, fanIn int) *network.Network {
+	rnd := rand.New(rand.NewSource(42))
+	in := make([]*network.NNode, 0, inputs+1)
+	for i := 0; i < inputs; i++ {
+		in = append(in, network.NewNNode(i+1, network.InputNeuron))
+	}
+	in = append(in, network.NewNNode(inputs+1, network.BiasNeuron))
+	allNodes := append([]*network.NNode{}, in...)
+
+	for i := 0; i < hidden; i++ {
+		node := network.NewNNode(len(allNodes)+1, network.HiddenNeuron)
+		for j := 0; j < fanIn; j++ {
+			node.ConnectFrom(allNodes[rnd.Intn(len(allNodes))], rnd.NormFloat64())
+		}
+		allNodes = append(allNodes, node)
+	}
+	out := make([]*network.NNode, outputs)
+	for i := range out {
+		out[i] = network.NewNNode(len(allNodes)+1, network.OutputNeuron)
+		for j := 0; j < fanIn; j++ {
+			out[i].ConnectFrom(allNodes[len(in)+rnd.Intn(hidden)], rnd.NormFloat64())
+		}
+		allNodes = append(allNodes, out[i])
+	}
+	return network.NewNetwork(in, out, allNodes, 0)
+}
+
+func benchmarkSolver(b *testing.B, solver network.Solver, steps int, inputs []float64) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := solver.Flush(); err != nil {
+			b.Fatal(err)
+		}
+		if err := solver.LoadSensors(inputs); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := solver.ForwardSteps(steps); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkSolvers(b *testing.B, build func() *network.Network, inputs []float64, depth int) {
+	b.Run("Network", func(b *testing.B) {
+		benchmarkSolver(b, build(), depth, inputs)
+	})
+	b.Run("FastModularNetworkSolver", func(b *testing.B) {
+		solver, err := build().FastNetworkSolver()
+		require.NoError(b, err, "failed to create fast network solver")
+		benchmarkSolver(b, solver, depth, inputs)
+	})
+	b.Run("SortedNetworkSolver", func(b *testing.B) {
+		solver, err := network.NewSortedNetworkSolver(build())
+		require.NoError(b, err, "failed to create sorted network solver")
+		benchmarkSolver(b, solver, 1, inputs)
+	})
+}
+
+func BenchmarkSolvers_Simple(b *testing.B) {
+	build := func() *network.Network {
+		net, err := buildNetworkFromGenome(genomeStrSimple)
+		require.NoError(b, err, "failed to build network")
+		return net
+	}
+	depth, err := build().MaxActivationDepth()
+	require.NoError(b, err, "failed to calculate max depth")
+	benchmarkSolvers(b, build, []float64{0.5, 0.1, 0.9, 0.3}, depth)
+}
+
+func BenchmarkSolvers_Layered(b *testing.B) {
+	inputs := make([]float64, 10)
+	for i := range inputs {
+		inputs[i] = float64(i) / 10.0
+	}
+	build := func() *network.Network {
+		return buildLayeredNetwork(len(inputs), 5, 20, 4)
+	}
+	depth, err := build().MaxActivationDepth()
+	require.NoError(b, err, "failed to calculate max depth")
+	benchmarkSolvers(b, build, inputs, depth)
+}
+
+func BenchmarkSolvers_Sparse10k(b *testing.B) {
+	inputs := make([]float64, 100)
+	for i := range inputs {
+		inputs[i] = float64(i) / 100.0
+	}
+	build := func() *network.Network {
+		return buildSparseNetwork(len(inputs), 10000, 10, 4)
+	}
+	// the number of activation steps is fixed because evaluation of the max depth of such network is too expensive
+	benchmarkSolvers(b, build, inputs, 10)
+}
+
+func BenchmarkNewFastModularNetworkSolver_Sparse10k(b *testing.B) {
+	solver, err := buildSparseNetwork(100, 10000, 10, 4).FastNetworkSolver()
+	require.NoError(b, err, "failed to create fast network solver")
+	st := solver.(*network.FastModularNetworkSolver).Structure()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		network.NewFastModularNetworkSolver(st.BiasNeuronCount, st.InputNeuronCount, st.OutputNeuronCount,
+			st.TotalNeuronCount, st.ActivationFunctions, st.Connections, st.BiasList, st.Modules)
+	}
+}
@@ -37,7 +37,11 @@ func (e *cartPoleGenerationEvaluator) GenerationEvaluate(ctx context.Context, po
 	}
 	// Evaluate each organism on a test
 	for _, org := range pop.Organisms {
-		res, err := OrganismEvaluate(org, e.WinBalancingSteps, e.RandomStart)
+		if experiment.IsEvaluationSkipped(ctx, org) {
+			// the fitness is already known to the wrapping evaluator
+			continue
+		}
+		res, err := organismEvaluate(org, e.WinBalancingSteps, e.RandomStart, evaluationRand(ctx))
 		if err != nil {
 			return err
 		}
@@ -7,6 +7,7 @@ import (
 	"github.com/yaricom/goNEAT/v4/experiment/utils"
 	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"github.com/yaricom/goNEAT/v4/neat/network"
 	"sync"
 )
 
@@ -14,6 +15,9 @@ type cartPoleParallelGenerationEvaluator struct {
 	cartPoleGenerationEvaluator
 }
 
+// The number of random start positions to test the winner from
+const winnerRandomStartTrials = 10
+
 type parallelEvaluationResult struct {
 	genomeId int
 	fitness  float64
@@ -50,6 +54,10 @@ func (e *cartPoleParallelGenerationEvaluator) GenerationEvaluate(ctx context.Con
 
 	// Evaluate each organism in generation
 	for _, org := range pop.Organisms {
+		if experiment.IsEvaluationSkipped(ctx, org) {
+			// the fitness is already known to the wrapping evaluator
+			continue
+		}
 		if _, ok = organismMapping[org.Genotype.Id]; ok {
 			return fmt.Errorf("organism with %d already exists in mapping", org.Genotype.Id)
 		}
@@ -61,7 +69,7 @@ func (e *cartPoleParallelGenerationEvaluator) GenerationEvaluate(ctx context.Con
 			defer wg.Done()
 
 			// create simulator and evaluate
-			winner, err := OrganismEvaluate(organism, e.WinBalancingSteps, e.RandomStart)
+			winner, err := organismEvaluate(organism, e.WinBalancingSteps, e.RandomStart, evaluationRand(ctx))
 			if err != nil {
 				resChan <- parallelEvaluationResult{err: err}
 				return
@@ -123,6 +131,18 @@ func (e *cartPoleParallelGenerationEvaluator) GenerationEvaluate(ctx context.Con
 		org := epoch.Champion
 		utils.PrintActivationDepth(org, true)
 
+		if e.RandomStart {
+			// check how well the winner balances the pole starting from other random positions
+			if phenotype, err := org.Phenotype(); err != nil {
+				return err
+			} else if balanced, err := evaluateRandomStartsParallel(phenotype, e.WinBalancingSteps, winnerRandomStartTrials); err != nil {
+				return err
+			} else {
+				neat.InfoLog(fmt.Sprintf("Generation #%d winner balanced the pole from %d of %d random start positions\n",
+					epoch.Id, balanced, winnerRandomStartTrials))
+			}
+		}
+
 		genomeFile := "pole1_winner_genome"
 		// Prints the winner organism to file!
 		if orgPath, err := utils.WriteGenomePlain(genomeFile, e.OutputPath, org, epoch); err != nil {
@@ -142,3 +162,32 @@ func (e *cartPoleParallelGenerationEvaluator) GenerationEvaluate(ctx context.Con
 
 	return nil
 }
+
+// evaluateRandomStartsParallel runs the cart emulation from the given number of random start positions concurrently,
+// each using its own clone of the provided network. Returns the number of runs where pole was balanced for the
+// required number of steps.
+func evaluateRandomStartsParallel(net *network.Network, winnerBalancingSteps, trials int) (int, error) {
+	balanced := make([]bool, trials)
+	errs := make([]error, trials)
+	var wg sync.WaitGroup
+	for i := 0; i < trials; i++ {
+		wg.Add(1)
+		go func(trial int, clone *network.Network) {
+			defer wg.Done()
+			steps, err := runCart(clone, winnerBalancingSteps, true, nil)
+			balanced[trial], errs[trial] = steps >= winnerBalancingSteps, err
+		}(i, net.Clone().(*network.Network))
+	}
+	wg.Wait()
+
+	count := 0
+	for i := 0; i < trials; i++ {
+		if errs[i] != nil {
+			return 0, errs[i]
+		}
+		if balanced[i] {
+			count++
+		}
+	}
+	return count, nil
+}
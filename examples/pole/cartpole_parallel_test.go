@@ -0,0 +1,55 @@
+package pole
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"testing"
+)
+
+const winnerGenomeStr = `genomestart 216
+trait 1 0.1 0 0 0 0 0 0 0
+trait 2 0.2 0 0 0 0 0 0 0
+trait 3 0.3 0 0 0 0 0 0 0
+node 1 1 1 3 SigmoidSteepenedActivation
+node 2 1 1 1 SigmoidSteepenedActivation
+node 3 1 1 1 SigmoidSteepenedActivation
+node 4 1 1 1 SigmoidSteepenedActivation
+node 5 1 1 1 SigmoidSteepenedActivation
+node 6 1 0 2 SigmoidSteepenedActivation
+node 7 1 0 2 SigmoidSteepenedActivation
+node 18 1 0 0 SigmoidSteepenedActivation
+gene 1 1 6 1.6172585248685125 false 1 1.6172585248685125 true
+gene 2 2 6 1.4349135060602212 false 2 1.4349135060602212 false
+gene 3 3 6 -1.437192180208712 false 3 -1.437192180208712 true
+gene 1 4 6 -1.725217214232054 false 4 -1.725217214232054 true
+gene 2 5 6 0.48053933661145054 false 5 0.48053933661145054 true
+gene 3 1 7 -1.1679841809903342 false 6 -1.1679841809903342 true
+gene 1 2 7 1.3472543766518088 false 7 1.3472543766518088 true
+gene 2 3 7 0.017044383106426475 false 8 0.017044383106426475 true
+gene 3 4 7 0.09158993909992386 false 9 0.09158993909992386 true
+gene 1 5 7 2.21882406747052 false 10 2.21882406747052 true
+gene 1 6 7 0.7146850621500764 false 52 0 true
+gene 2 2 18 1 false 86 0 true
+gene 2 18 6 1.4349135060602212 false 87 0 true
+genomeend 216`
+
+func TestEvaluateRandomStartsParallel(t *testing.T) {
+	reader, err := genetics.NewGenomeReader(bytes.NewBufferString(winnerGenomeStr), genetics.PlainGenomeEncoding)
+	require.NoError(t, err)
+	genome, err := reader.Read()
+	require.NoError(t, err)
+	net, err := genome.Genesis(genome.Id)
+	require.NoError(t, err)
+
+	trials := 8
+	balanced, err := evaluateRandomStartsParallel(net, 1000, trials)
+	require.NoError(t, err)
+	assert.True(t, balanced >= 0 && balanced <= trials, "wrong number of balanced trials: %d", balanced)
+
+	// the original network must stay untouched
+	for _, out := range net.ReadOutputs() {
+		assert.Zero(t, out)
+	}
+}
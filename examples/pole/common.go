@@ -1,7 +1,9 @@
 package pole
 
 import (
+	"context"
 	"fmt"
+	"github.com/yaricom/goNEAT/v4/experiment"
 	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/genetics"
 	"github.com/yaricom/goNEAT/v4/neat/network"
@@ -13,13 +15,19 @@ const twelveDegrees = 12.0 * math.Pi / 180.0
 
 // OrganismEvaluate evaluates provided organism for cart pole balancing task
 func OrganismEvaluate(organism *genetics.Organism, winnerBalancingSteps int, randomStart bool) (bool, error) {
+	return organismEvaluate(organism, winnerBalancingSteps, randomStart, nil)
+}
+
+// organismEvaluate evaluates provided organism for cart pole balancing task using provided random number generator
+// to set up the random start state. If rng is nil, the global random number generator is used.
+func organismEvaluate(organism *genetics.Organism, winnerBalancingSteps int, randomStart bool, rng *rand.Rand) (bool, error) {
 	phenotype, err := organism.Phenotype()
 	if err != nil {
 		return false, err
 	}
 
 	// Try to balance a pole now
-	if fitness, err := runCart(phenotype, winnerBalancingSteps, randomStart); err != nil {
+	if fitness, err := runCart(phenotype, winnerBalancingSteps, randomStart, rng); err != nil {
 		return false, nil
 	} else {
 		organism.Fitness = float64(fitness)
@@ -51,18 +59,34 @@ func OrganismEvaluate(organism *genetics.Organism, winnerBalancingSteps int, ran
 	return organism.IsWinner, nil
 }
 
-// runCart runs the cart emulation and return number of emulation steps pole was balanced
-func runCart(net *network.Network, winnerBalancingSteps int, randomStart bool) (steps int, err error) {
+// evaluationRand returns the random number generator to set up the random start state of the organism evaluation.
+// When running under experiment.NoisyFitnessEvaluator, the generator is seeded from the evaluation context, so that
+// all organisms start from the same state within one evaluation and the evaluations are reproducible. Otherwise,
+// nil is returned to use the global random number generator.
+func evaluationRand(ctx context.Context) *rand.Rand {
+	if seed, ok := experiment.EvaluationSeedFromContext(ctx); ok {
+		return rand.New(rand.NewSource(seed))
+	}
+	return nil
+}
+
+// runCart runs the cart emulation and return number of emulation steps pole was balanced. The random start state is
+// drawn from provided random number generator or from the global one if rng is nil.
+func runCart(net *network.Network, winnerBalancingSteps int, randomStart bool, rng *rand.Rand) (steps int, err error) {
 	var x float64        /* cart position, meters */
 	var xDot float64     /* cart velocity */
 	var theta float64    /* pole angle, radians */
 	var thetaDot float64 /* pole angular velocity */
 	if randomStart {
+		int31 := rand.Int31
+		if rng != nil {
+			int31 = rng.Int31
+		}
 		/*set up random start state*/
-		x = float64(rand.Int31()%4800)/1000.0 - 2.4
-		xDot = float64(rand.Int31()%2000)/1000.0 - 1
-		theta = float64(rand.Int31()%400)/1000.0 - .2
-		thetaDot = float64(rand.Int31()%3000)/1000.0 - 1.5
+		x = float64(int31()%4800)/1000.0 - 2.4
+		xDot = float64(int31()%2000)/1000.0 - 1
+		theta = float64(int31()%400)/1000.0 - .2
+		thetaDot = float64(int31()%3000)/1000.0 - 1.5
 	}
 
 	netDepth, err := net.MaxActivationDepthWithCap(0) // The max depth of the network to be activated
@@ -0,0 +1,31 @@
+package pole
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"math/rand"
+	"testing"
+)
+
+func TestRunCart_randomStartReproducible(t *testing.T) {
+	reader, err := genetics.NewGenomeReader(bytes.NewBufferString(winnerGenomeStr), genetics.PlainGenomeEncoding)
+	require.NoError(t, err)
+	genome, err := reader.Read()
+	require.NoError(t, err)
+
+	// the same seed gives the same start state and the same number of balancing steps
+	steps := make([]int, 2)
+	for i := range steps {
+		net, err := genome.Genesis(genome.Id)
+		require.NoError(t, err)
+		steps[i], err = runCart(net, 1000, true, rand.New(rand.NewSource(42)))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, steps[0], steps[1])
+
+	// the global random number generator is used without evaluation seed
+	assert.Nil(t, evaluationRand(context.Background()))
+}
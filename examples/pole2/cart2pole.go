@@ -41,6 +41,10 @@ func (e *cartDoublePoleGenerationEvaluator) GenerationEvaluate(ctx context.Conte
 
 	// Evaluate each organism on a test
 	for _, org := range pop.Organisms {
+		if experiment.IsEvaluationSkipped(ctx, org) {
+			// the fitness is already known to the wrapping evaluator
+			continue
+		}
 		winner, err := OrganismEvaluate(org, cartPole, e.ActionType)
 		if err != nil {
 			return err
@@ -7,6 +7,7 @@ import (
 	"github.com/yaricom/goNEAT/v4/experiment/utils"
 	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"runtime"
 	"sync"
 )
 
@@ -48,6 +49,10 @@ func (e *cartDoublePoleParallelGenerationEvaluator) GenerationEvaluate(ctx conte
 
 	// Evaluate each organism in generation
 	for _, org := range pop.Organisms {
+		if experiment.IsEvaluationSkipped(ctx, org) {
+			// the fitness is already known to the wrapping evaluator
+			continue
+		}
 		if _, ok = organismMapping[org.Genotype.Id]; ok {
 			return fmt.Errorf("organism with %d already exists in mapping", org.Genotype.Id)
 		}
@@ -109,6 +114,22 @@ func (e *cartDoublePoleParallelGenerationEvaluator) GenerationEvaluate(ctx conte
 		}
 	}
 
+	// Check for winner in Non-Markov case
+	if !e.Markov {
+		epoch.Solved = false
+		// evaluate generalization tests concurrently using clones of the champion's phenotype
+		cartPole := NewCartPole(e.Markov)
+		if champion, err := EvaluateOrganismGeneralizationParallel(pop.Species, cartPole, e.ActionType, runtime.NumCPU()); err != nil {
+			return err
+		} else if champion.IsWinner {
+			epoch.Solved = true
+			epoch.WinnerNodes = len(champion.Genotype.Nodes)
+			epoch.WinnerGenes = champion.Genotype.Extrons()
+			epoch.WinnerEvals = options.PopSize*epoch.Id + champion.Genotype.Id
+			epoch.Champion = champion
+		}
+	}
+
 	// Fill statistics about current epoch
 	epoch.FillPopulationStatistics(pop)
 
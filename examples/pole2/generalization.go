@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"github.com/yaricom/goNEAT/v4/neat/network"
 	"sort"
+	"sync"
 )
 
 // EvaluateOrganismGeneralization
@@ -20,6 +22,125 @@ import (
 // from the 625 initial conditions and an individual is defined as a solution if it reaches a generalization
 // score of 200 or more.
 func EvaluateOrganismGeneralization(species []*genetics.Species, cartPole *CartDoublePole, actionType ActionType) (*genetics.Organism, error) {
+	return evaluateOrganismGeneralization(species, cartPole, actionType,
+		func(champion *genetics.Organism, championPhenotype *network.Network) (int, error) {
+			// the champion passed non-Markov long test, start generalization
+			cartPole.nonMarkovLong = false
+			cartPole.generalizationTest = true
+
+			generalizationScore := 0
+			for _, state := range generalizationInitialStates() {
+				cartPole.state = state
+
+				// The champion needs to be flushed here because it may have
+				// leftover activation from its last test run that could affect
+				// its recurrent memory
+				if _, err := championPhenotype.Flush(); err != nil {
+					return 0, err
+				}
+
+				if generalized, err := OrganismEvaluate(champion, cartPole, actionType); generalized {
+					generalizationScore++
+
+					if neat.LogLevel == neat.LogLevelDebug {
+						neat.DebugLog(
+							fmt.Sprintf("x: %f, xv: %f, t1: %f, t2: %f, angle: %f\n",
+								cartPole.state[0], cartPole.state[1],
+								cartPole.state[2], cartPole.state[4], thirtySixDegrees))
+					}
+				} else if err != nil {
+					return 0, err
+				}
+			}
+			return generalizationScore, nil
+		})
+}
+
+// EvaluateOrganismGeneralizationParallel is the same as EvaluateOrganismGeneralization, but runs generalization tests
+// of the champion concurrently using the given number of workers. Each worker evaluates its own clone of the champion's
+// phenotype against its own cart-pole simulation.
+func EvaluateOrganismGeneralizationParallel(species []*genetics.Species, cartPole *CartDoublePole, actionType ActionType, workers int) (*genetics.Organism, error) {
+	if workers <= 0 {
+		return nil, fmt.Errorf("the number of workers must be positive, got: %d", workers)
+	}
+	return evaluateOrganismGeneralization(species, cartPole, actionType,
+		func(_ *genetics.Organism, championPhenotype *network.Network) (int, error) {
+			states := generalizationInitialStates()
+			statesChan := make(chan [6]float64, len(states))
+			for _, state := range states {
+				statesChan <- state
+			}
+			close(statesChan)
+
+			scores := make([]int, workers)
+			errs := make([]error, workers)
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(worker int, phenotype *network.Network) {
+					defer wg.Done()
+					workerCartPole := NewCartPole(false)
+					workerCartPole.generalizationTest = true
+					for state := range statesChan {
+						workerCartPole.state = state
+						// each run starts without leftover activation from the previous one
+						if _, err := phenotype.Flush(); err != nil {
+							errs[worker] = err
+							return
+						}
+						steps, err := workerCartPole.evalNet(phenotype, actionType)
+						if err != nil {
+							errs[worker] = err
+							return
+						}
+						if steps >= nonMarkovGeneralizationMaxSteps {
+							scores[worker]++
+						}
+					}
+				}(w, championPhenotype.Clone().(*network.Network))
+			}
+			wg.Wait()
+
+			generalizationScore := 0
+			for w := 0; w < workers; w++ {
+				if errs[w] != nil {
+					return 0, errs[w]
+				}
+				generalizationScore += scores[w]
+			}
+			return generalizationScore, nil
+		})
+}
+
+// generalizationInitialStates returns 625 initial states of the generalization test. The initial states are built by
+// assigning each value of the set Ω = [0.05 0.25 0.5 0.75 0.95] to each of the states x, ∆x/∆t, θ1 and ∆θ1/∆t,
+// scaled to the range of the variables. The short pole angle and its angular velocity are set to zero.
+func generalizationInitialStates() [][6]float64 {
+	stateVals := [5]float64{0.05, 0.25, 0.5, 0.75, 0.95}
+	states := make([][6]float64, 0, 625)
+	for s0c := 0; s0c < 5; s0c++ {
+		for s1c := 0; s1c < 5; s1c++ {
+			for s2c := 0; s2c < 5; s2c++ {
+				for s3c := 0; s3c < 5; s3c++ {
+					states = append(states, [6]float64{
+						stateVals[s0c]*4.32 - 2.16,
+						stateVals[s1c]*2.70 - 1.35,
+						stateVals[s2c]*0.12566304 - 0.06283152, // 0.06283152 = 3.6 degrees
+						stateVals[s3c]*0.30019504 - 0.15009752, // 0.15009752 = 8.6 degrees
+						0.0,
+						0.0,
+					})
+				}
+			}
+		}
+	}
+	return states
+}
+
+// evaluateOrganismGeneralization finds the champion of the most fit unchecked species, tests it on the non-Markov long
+// run and, if passed, calculates its generalization score using provided function.
+func evaluateOrganismGeneralization(species []*genetics.Species, cartPole *CartDoublePole, actionType ActionType,
+	generalizationScoreFunc func(champion *genetics.Organism, championPhenotype *network.Network) (int, error)) (*genetics.Organism, error) {
 	// Sort the species by max organism fitness in descending order - the highest fitness first
 	sortedSpecies := make([]*genetics.Species, len(species))
 	copy(sortedSpecies, species)
@@ -67,49 +188,11 @@ func EvaluateOrganismGeneralization(species []*genetics.Species, cartPole *CartD
 		return nil, err
 	}
 	if longRunPassed {
-
-		// the champion passed non-Markov long test, start generalization
-		cartPole.nonMarkovLong = false
-		cartPole.generalizationTest = true
-
 		// Given that the champion passed long run test, now run it on generalization tests running
 		// over 1'000 time steps, starting from 625 different initial conditions
-		stateVals := [5]float64{0.05, 0.25, 0.5, 0.75, 0.95}
-		generalizationScore := 0
-		for s0c := 0; s0c < 5; s0c++ {
-			for s1c := 0; s1c < 5; s1c++ {
-				for s2c := 0; s2c < 5; s2c++ {
-					for s3c := 0; s3c < 5; s3c++ {
-						cartPole.state[0] = stateVals[s0c]*4.32 - 2.16
-						cartPole.state[1] = stateVals[s1c]*2.70 - 1.35
-						cartPole.state[2] = stateVals[s2c]*0.12566304 - 0.06283152 // 0.06283152 = 3.6 degrees
-						cartPole.state[3] = stateVals[s3c]*0.30019504 - 0.15009752 // 0.15009752 = 8.6 degrees
-						// The short pole angle and its angular velocity are set to zero.
-						cartPole.state[4] = 0.0
-						cartPole.state[5] = 0.0
-
-						// The champion needs to be flushed here because it may have
-						// leftover activation from its last test run that could affect
-						// its recurrent memory
-						if _, err = championPhenotype.Flush(); err != nil {
-							return nil, err
-						}
-
-						if generalized, err := OrganismEvaluate(champion, cartPole, actionType); generalized {
-							generalizationScore++
-
-							if neat.LogLevel == neat.LogLevelDebug {
-								neat.DebugLog(
-									fmt.Sprintf("x: %f, xv: %f, t1: %f, t2: %f, angle: %f\n",
-										cartPole.state[0], cartPole.state[1],
-										cartPole.state[2], cartPole.state[4], thirtySixDegrees))
-							}
-						} else if err != nil {
-							return nil, err
-						}
-					}
-				}
-			}
+		generalizationScore, err := generalizationScoreFunc(champion, championPhenotype)
+		if err != nil {
+			return nil, err
 		}
 
 		if generalizationScore >= 200 {
@@ -0,0 +1,66 @@
+package pole2
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"testing"
+)
+
+// The genome of the champion able to solve the non-Markov double pole-balancing task
+const nonMarkovWinnerGenomeStr = `genomestart 194
+trait 1 0.08580616996801868 0.01629188536549083 0.058374403380168256 0.06024852341237341 0.018000498794110982 0.050925869023008774 0.06659510138949445 0.22997412575172158
+node 1 1 1 1 SigmoidSteepenedActivation
+node 2 1 1 1 SigmoidSteepenedActivation
+node 3 1 1 1 SigmoidSteepenedActivation
+node 4 1 1 3 SigmoidSteepenedActivation
+node 5 1 0 2 SigmoidSteepenedActivation
+gene 1 1 5 -2.6019352440605465 false 1 -2.6019352440605465 true
+gene 1 2 5 -11.836400371857563 false 2 -11.836400371857563 true
+gene 1 3 5 6.685612154699408 false 3 6.685612154699408 true
+gene 1 4 5 0.8308259616018865 false 4 0.8308259616018865 true
+gene 1 5 5 -1.1686649945873842 true 132 -1.1686649945873842 true
+genomeend 194`
+
+func createNonMarkovWinnerSpecies(t *testing.T) []*genetics.Species {
+	reader, err := genetics.NewGenomeReader(bytes.NewBufferString(nonMarkovWinnerGenomeStr), genetics.PlainGenomeEncoding)
+	require.NoError(t, err)
+	genome, err := reader.Read()
+	require.NoError(t, err)
+	org, err := genetics.NewOrganism(0.5, genome, 1)
+	require.NoError(t, err)
+
+	species := genetics.NewSpecies(1)
+	species.Organisms = append(species.Organisms, org)
+	org.Species = species
+	return []*genetics.Species{species}
+}
+
+func TestGeneralizationInitialStates(t *testing.T) {
+	states := generalizationInitialStates()
+	require.Len(t, states, 625)
+	assert.InDeltaSlice(t, []float64{0.05*4.32 - 2.16, 0.05*2.70 - 1.35, 0.05*0.12566304 - 0.06283152,
+		0.05*0.30019504 - 0.15009752, 0, 0}, states[0][:], 1e-12)
+	assert.InDeltaSlice(t, []float64{0.95*4.32 - 2.16, 0.95*2.70 - 1.35, 0.95*0.12566304 - 0.06283152,
+		0.95*0.30019504 - 0.15009752, 0, 0}, states[624][:], 1e-12)
+}
+
+func TestEvaluateOrganismGeneralizationParallel(t *testing.T) {
+	neat.LogLevel = neat.LogLevelInfo
+	champion, err := EvaluateOrganismGeneralization(createNonMarkovWinnerSpecies(t), NewCartPole(false), ContinuousAction)
+	require.NoError(t, err)
+	require.True(t, champion.IsWinner)
+
+	parallelChampion, err := EvaluateOrganismGeneralizationParallel(createNonMarkovWinnerSpecies(t), NewCartPole(false), ContinuousAction, 4)
+	require.NoError(t, err)
+	assert.True(t, parallelChampion.IsWinner)
+	assert.Equal(t, champion.Fitness, parallelChampion.Fitness, "generalization score must be the same")
+}
+
+func TestEvaluateOrganismGeneralizationParallel_wrongWorkers(t *testing.T) {
+	champion, err := EvaluateOrganismGeneralizationParallel(createNonMarkovWinnerSpecies(t), NewCartPole(false), ContinuousAction, 0)
+	assert.Error(t, err)
+	assert.Nil(t, champion)
+}
@@ -46,6 +46,10 @@ func (e *xorGenerationEvaluator) GenerationEvaluate(ctx context.Context, pop *ge
 	}
 	// Evaluate each organism on a test
 	for _, org := range pop.Organisms {
+		if experiment.IsEvaluationSkipped(ctx, org) {
+			// the fitness is already known to the wrapping evaluator
+			continue
+		}
 		res, err := e.orgEvaluate(org)
 		if err != nil {
 			return err
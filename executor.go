@@ -8,6 +8,7 @@ import (
 	"github.com/yaricom/goNEAT/v4/examples/pole2"
 	"github.com/yaricom/goNEAT/v4/examples/xor"
 	"github.com/yaricom/goNEAT/v4/experiment"
+	"github.com/yaricom/goNEAT/v4/experiment/utils"
 	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/genetics"
 	"log"
@@ -23,13 +24,24 @@ func main() {
 	var outDirPath = flag.String("out", "./out", "The output directory to store results.")
 	var contextPath = flag.String("context", "./data/xor.neat", "The execution context configuration file.")
 	var genomePath = flag.String("genome", "./data/xorstartgenes", "The seed genome to start with.")
+	var populationPath = flag.String("population", "", "The population file or directory with genome files to resume evolution from. Overrides the seed genome.")
+	var innovationsPath = flag.String("innovations", "", "The innovations file saved along with the population to resume evolution from. If not set, the file next to the population file is used if exists.")
 	var experimentName = flag.String("experiment", "XOR", "The name of experiment to run. [XOR, cart_pole, cart_2pole_markov, cart_2pole_non-markov]")
 	var trialsCount = flag.Int("trials", 0, "The number of trials for experiment. Overrides the one set in configuration.")
 	var logLevel = flag.String("log_level", "", "The logger level to be used. Overrides the one set in configuration.")
 	var randSeed = flag.Int64("seed", 0, "The seed for random number generator")
+	var fitnessEvals = flag.Int("fitness_evals", 1, "The number of evaluations of each organism per generation to handle noisy fitness.")
+	var fitnessAggregation = flag.String("fitness_aggregation", "mean", "The aggregation of fitness scores of multiple evaluations. [mean, median, worst]")
+	var fitnessCache = flag.Bool("fitness_cache", false, "Whether to cache fitness scores of organisms to avoid re-evaluation of identical genomes. Can not be used with noisy fitness evaluation.")
+	var surrogateRatio = flag.Float64("surrogate_ratio", 1.0, "The fraction of organisms with the best fitness predicted by surrogate model to be evaluated. The value less than 1.0 enables surrogate-assisted evaluation.")
 
 	flag.Parse()
 
+	if *fitnessCache && *fitnessEvals > 1 {
+		// the cached fitness scores of surviving organisms would never be re-evaluated
+		log.Fatal("The fitness cache can not be used with multiple fitness evaluations")
+	}
+
 	// Seed the random-number generator with current time so that
 	// the numbers will be different every time we run.
 	seed := time.Now().Unix()
@@ -44,17 +56,41 @@ func main() {
 		log.Fatal("Failed to load NEAT options: ", err)
 	}
 
-	// Load Genome
-	log.Printf("Loading start genome for %s experiment from file '%s'\n", *experimentName, *genomePath)
-	reader, err := genetics.NewGenomeReaderFromFile(*genomePath)
-	if err != nil {
-		log.Fatalf("Failed to open genome file, reason: '%s'", err)
-	}
-	startGenome, err := reader.Read()
-	if err != nil {
-		log.Fatalf("Failed to read start genome, reason: '%s'", err)
+	// Load Genome or seed population
+	var startGenome *genetics.Genome
+	var seedGenomes []*genetics.Genome
+	var seedInnovations *genetics.InnovationsStore
+	if len(*populationPath) > 0 {
+		log.Printf("Loading seed population for %s experiment from '%s'\n", *experimentName, *populationPath)
+		if seedGenomes, err = genetics.ReadPopulationGenomes(*populationPath); err != nil {
+			log.Fatalf("Failed to read seed population, reason: '%s'", err)
+		}
+		fmt.Printf("Loaded %d seed genomes\n", len(seedGenomes))
+		// restore innovations to continue the same innovation numbering
+		if len(*innovationsPath) == 0 {
+			if _, err = os.Stat(*populationPath + utils.InnovationsFileSuffix); err == nil {
+				*innovationsPath = *populationPath + utils.InnovationsFileSuffix
+			}
+		}
+		if len(*innovationsPath) > 0 {
+			log.Printf("Loading innovations of seed population from '%s'\n", *innovationsPath)
+			if seedInnovations, err = readInnovations(*innovationsPath); err != nil {
+				log.Fatalf("Failed to read innovations of seed population, reason: '%s'", err)
+			}
+		} else {
+			log.Println("Innovations of seed population not found, the innovation numbers are recovered from genomes")
+		}
+	} else {
+		log.Printf("Loading start genome for %s experiment from file '%s'\n", *experimentName, *genomePath)
+		reader, err := genetics.NewGenomeReaderFromFile(*genomePath)
+		if err != nil {
+			log.Fatalf("Failed to open genome file, reason: '%s'", err)
+		}
+		if startGenome, err = reader.Read(); err != nil {
+			log.Fatalf("Failed to read start genome, reason: '%s'", err)
+		}
+		fmt.Println(startGenome)
 	}
-	fmt.Println(startGenome)
 
 	// Check if output dir exists
 	outDir := *outDirPath
@@ -111,6 +147,26 @@ func main() {
 	default:
 		log.Fatalf("Unsupported experiment: %s", *experimentName)
 	}
+	if *fitnessEvals > 1 {
+		generationEvaluator, err = experiment.NewNoisyFitnessEvaluator(generationEvaluator, *fitnessEvals,
+			experiment.FitnessAggregation(*fitnessAggregation), seed)
+		if err != nil {
+			log.Fatal("Failed to create noisy fitness evaluator: ", err)
+		}
+	}
+	if *surrogateRatio < 1.0 {
+		generationEvaluator, err = experiment.NewSurrogateEvaluator(generationEvaluator, 5, *surrogateRatio, 1000)
+		if err != nil {
+			log.Fatal("Failed to create surrogate evaluator: ", err)
+		}
+	}
+	if *fitnessCache {
+		generationEvaluator, err = experiment.NewCachedFitnessEvaluator(generationEvaluator,
+			experiment.NewFitnessCache(0), 6)
+		if err != nil {
+			log.Fatal("Failed to create cached fitness evaluator: ", err)
+		}
+	}
 
 	// prepare to execute
 	errChan := make(chan error)
@@ -118,7 +174,13 @@ func main() {
 
 	// run experiment in the separate GO routine
 	go func() {
-		if err = exp.Execute(neat.NewContext(ctx, neatOptions), startGenome, generationEvaluator, nil); err != nil {
+		expCtx := neat.NewContext(ctx, neatOptions)
+		if seedGenomes != nil {
+			err = exp.ExecuteFromGenomesWithInnovations(expCtx, seedGenomes, seedInnovations, generationEvaluator, nil)
+		} else {
+			err = exp.Execute(expCtx, startGenome, generationEvaluator, nil)
+		}
+		if err != nil {
 			errChan <- err
 		} else {
 			errChan <- nil
@@ -153,7 +215,11 @@ func main() {
 	//
 	exp.PrintStatistics()
 
-	fmt.Printf(">>> Start genome file:  %s\n", *genomePath)
+	if seedGenomes != nil {
+		fmt.Printf(">>> Seed population:    %s\n", *populationPath)
+	} else {
+		fmt.Printf(">>> Start genome file:  %s\n", *genomePath)
+	}
 	fmt.Printf(">>> Configuration file: %s\n", *contextPath)
 
 	// Save experiment data in native format
@@ -174,3 +240,13 @@ func main() {
 		log.Fatal("Failed to save experiment results as NPZ file", err)
 	}
 }
+
+// readInnovations is to read the innovations store from the file at the given path
+func readInnovations(path string) (*genetics.InnovationsStore, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	return genetics.ReadInnovationsStore(file)
+}
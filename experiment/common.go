@@ -21,6 +21,31 @@ type GenerationEvaluator interface {
 	GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *Generation) error
 }
 
+type skippedOrganismsKey struct{}
+
+// IsEvaluationSkipped checks whether the GenerationEvaluator should skip evaluation of the organism because its
+// fitness is already known to the wrapping evaluator, e.g., taken from the fitness cache. The skipped organisms are
+// still the part of the population passed to the evaluator, so that the population snapshots written by it are
+// complete.
+func IsEvaluationSkipped(ctx context.Context, org *genetics.Organism) bool {
+	skipped, ok := ctx.Value(skippedOrganismsKey{}).(map[*genetics.Organism]bool)
+	return ok && skipped[org]
+}
+
+// withSkippedOrganisms returns the context which marks provided organisms to be skipped by the GenerationEvaluator
+// in addition to the organisms skipped by the parent context.
+func withSkippedOrganisms(ctx context.Context, organisms []*genetics.Organism) context.Context {
+	parent, _ := ctx.Value(skippedOrganismsKey{}).(map[*genetics.Organism]bool)
+	skipped := make(map[*genetics.Organism]bool, len(parent)+len(organisms))
+	for org := range parent {
+		skipped[org] = true
+	}
+	for _, org := range organisms {
+		skipped[org] = true
+	}
+	return context.WithValue(ctx, skippedOrganismsKey{}, skipped)
+}
+
 // TrialRunObserver defines observer to be notified about experiment's trial lifecycle methods
 type TrialRunObserver interface {
 	// TrialRunStarted invoked to notify that new trial run just started. Invoked before any epoch evaluation in that trial run
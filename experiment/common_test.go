@@ -63,3 +63,20 @@ func Test_epochExecutorForContext(t *testing.T) {
 		}
 	}
 }
+
+func TestIsEvaluationSkipped(t *testing.T) {
+	orgs := []*genetics.Organism{{}, {}, {}}
+	ctx := context.Background()
+	assert.False(t, IsEvaluationSkipped(ctx, orgs[0]))
+
+	ctx = withSkippedOrganisms(ctx, orgs[:1])
+	assert.True(t, IsEvaluationSkipped(ctx, orgs[0]))
+	assert.False(t, IsEvaluationSkipped(ctx, orgs[1]))
+
+	// the organisms skipped by the parent context are kept
+	child := withSkippedOrganisms(ctx, orgs[1:2])
+	assert.True(t, IsEvaluationSkipped(child, orgs[0]))
+	assert.True(t, IsEvaluationSkipped(child, orgs[1]))
+	assert.False(t, IsEvaluationSkipped(child, orgs[2]))
+	assert.False(t, IsEvaluationSkipped(ctx, orgs[1]), "parent context must not be changed")
+}
@@ -407,6 +407,7 @@ func (e *Experiment) Decode(dec *gob.Decoder) error {
 // - trial_[0...n]_epoch_best_fitnesses - the best fitness scores per epoch per trial
 // the same for AGE and COMPLEXITY per epoch per trial
 // - trial_[0...n]_epoch_diversity - the number of species per epoch per trial
+// - trial_[0...n]_epoch_inputs_usage - the usage frequencies of inputs per epoch per trial if collected
 func (e *Experiment) WriteNPZ(w io.Writer) error {
 	// write general statistics
 	trialsFitness, trialsAges, trialsComplexity := e.fitnessAgeComplexityMat()
@@ -448,6 +449,11 @@ func (e *Experiment) WriteNPZ(w io.Writer) error {
 		if err := out.Write(fmt.Sprintf("trial_%d_epoch_diversity", i), t.Diversity()); err != nil {
 			return err
 		}
+		if inputsUsage := t.InputsUsage(); inputsUsage != nil {
+			if err := out.Write(fmt.Sprintf("trial_%d_epoch_inputs_usage", i), inputsUsage); err != nil {
+				return err
+			}
+		}
 	}
 	return out.Close()
 }
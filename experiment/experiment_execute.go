@@ -1,15 +1,71 @@
 package experiment
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"github.com/pkg/errors"
 	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/genetics"
 	"time"
 )
 
+// populationSeeder is to create the initial population for each trial of the experiment
+type populationSeeder func(opts *neat.Options) (*genetics.Population, error)
+
 // Execute is to run specific experiment using provided startGenome and specific evaluator for each epoch of the experiment
 func (e *Experiment) Execute(ctx context.Context, startGenome *genetics.Genome, evaluator GenerationEvaluator, trialObserver TrialRunObserver) error {
+	seeder := func(opts *neat.Options) (*genetics.Population, error) {
+		neat.InfoLog("\n>>>>> Spawning new population ")
+		pop, err := genetics.NewPopulation(startGenome, opts)
+		if err != nil {
+			neat.InfoLog("Failed to spawn new population from start genome")
+		}
+		return pop, err
+	}
+	return e.execute(ctx, seeder, evaluator, trialObserver)
+}
+
+// ExecuteFromGenomes is to run specific experiment starting each trial from the population made of provided
+// seedGenomes, e.g., the genomes of population saved during previous run, and using specific evaluator for each epoch
+// of the experiment. The population is speciated anew and its node and innovation counters are recovered from
+// seedGenomes, so that evolution is resumed.
+func (e *Experiment) ExecuteFromGenomes(ctx context.Context, seedGenomes []*genetics.Genome, evaluator GenerationEvaluator, trialObserver TrialRunObserver) error {
+	return e.ExecuteFromGenomesWithInnovations(ctx, seedGenomes, nil, evaluator, trialObserver)
+}
+
+// ExecuteFromGenomesWithInnovations is the same as ExecuteFromGenomes, but additionally restores the innovations
+// store saved during previous run, so that the resumed evolution continues the same innovation numbering and
+// the new structural mutations do not get innovation numbers colliding with ones of seedGenomes. Each trial starts
+// with its own copy of provided innovations store. If innovations is nil, it is equivalent to ExecuteFromGenomes.
+func (e *Experiment) ExecuteFromGenomesWithInnovations(ctx context.Context, seedGenomes []*genetics.Genome, innovations *genetics.InnovationsStore, evaluator GenerationEvaluator, trialObserver TrialRunObserver) error {
+	var innovationsData []byte
+	if innovations != nil {
+		buf := bytes.NewBuffer(nil)
+		if err := innovations.Write(buf); err != nil {
+			return errors.Wrap(err, "failed to copy innovations store")
+		}
+		innovationsData = buf.Bytes()
+	}
+	seeder := func(opts *neat.Options) (*genetics.Population, error) {
+		neat.InfoLog(fmt.Sprintf("\n>>>>> Creating population from %d seed genomes ", len(seedGenomes)))
+		pop, err := genetics.NewPopulationFromGenomes(seedGenomes, opts)
+		if err != nil {
+			neat.InfoLog("Failed to create population from seed genomes")
+			return nil, err
+		}
+		if innovationsData != nil {
+			if err = pop.ReadInnovations(bytes.NewReader(innovationsData)); err != nil {
+				neat.InfoLog("Failed to restore innovations of seed population")
+				return nil, err
+			}
+		}
+		return pop, nil
+	}
+	return e.execute(ctx, seeder, evaluator, trialObserver)
+}
+
+func (e *Experiment) execute(ctx context.Context, seeder populationSeeder, evaluator GenerationEvaluator, trialObserver TrialRunObserver) error {
 	opts, found := neat.FromContext(ctx)
 	if !found {
 		return neat.ErrNEATOptionsNotFound
@@ -22,10 +78,8 @@ func (e *Experiment) Execute(ctx context.Context, startGenome *genetics.Genome,
 	for run := 0; run < opts.NumRuns; run++ {
 		trialStartTime := time.Now()
 
-		neat.InfoLog("\n>>>>> Spawning new population ")
-		pop, err := genetics.NewPopulation(startGenome, opts)
+		pop, err := seeder(opts)
 		if err != nil {
-			neat.InfoLog("Failed to spawn new population from start genome")
 			return err
 		} else {
 			neat.InfoLog("OK <<<<<")
@@ -126,3 +126,89 @@ func TestExperiment_Execute_evaluation_error(t *testing.T) {
 	genEvaluator.AssertNumberOfCalls(t, "GenerationEvaluate", 1)
 	genEvaluator.AssertExpectations(t)
 }
+
+func TestExperiment_ExecuteFromGenomes(t *testing.T) {
+	exp := Experiment{
+		Id: 0,
+	}
+	opts, err := neat.ReadNeatOptionsFromFile(xorConfigPath)
+	require.NoError(t, err, "failed to read NEAT options")
+	opts.NumRuns = 2
+	opts.NumGenerations = 5
+	ctx := neat.NewContext(context.Background(), opts)
+
+	// create seed genomes from the population of the previous run
+	genome, err := readTestGenome()
+	require.NoError(t, err, "failed to read XOR genome")
+	seedPop, err := genetics.NewPopulation(genome, opts)
+	require.NoError(t, err, "failed to create seed population")
+	seedGenomes := make([]*genetics.Genome, len(seedPop.Organisms))
+	for i, org := range seedPop.Organisms {
+		seedGenomes[i] = org.Genotype
+	}
+
+	genEvaluator := &MockedGenerationEvaluator{}
+	genEvaluator.On("GenerationEvaluate", ctx, mock.Anything, mock.Anything).Return(nil)
+
+	err = exp.ExecuteFromGenomes(ctx, seedGenomes, genEvaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+	assert.Equal(t, opts.NumRuns, len(exp.Trials), "wrong number of trials collected")
+	assert.EqualValues(t, opts.NumGenerations, exp.AvgGenerationsPerTrial())
+	genEvaluator.AssertNumberOfCalls(t, "GenerationEvaluate", opts.NumRuns*opts.NumGenerations)
+}
+
+func TestExperiment_ExecuteFromGenomesWithInnovations(t *testing.T) {
+	exp := Experiment{
+		Id: 0,
+	}
+	opts, err := neat.ReadNeatOptionsFromFile(xorConfigPath)
+	require.NoError(t, err, "failed to read NEAT options")
+	opts.NumRuns = 2
+	opts.NumGenerations = 2
+	ctx := neat.NewContext(context.Background(), opts)
+
+	// create seed genomes and innovations of the previous run
+	genome, err := readTestGenome()
+	require.NoError(t, err, "failed to read XOR genome")
+	seedPop, err := genetics.NewPopulation(genome, opts)
+	require.NoError(t, err, "failed to create seed population")
+	seedGenomes := make([]*genetics.Genome, len(seedPop.Organisms))
+	for i, org := range seedPop.Organisms {
+		seedGenomes[i] = org.Genotype
+	}
+	innovations := genetics.NewInnovationsStore(1000)
+	innovations.StoreInnovation(*genetics.NewInnovationForLink(1, 4, 1000, 1.0, 0))
+
+	// check that each trial starts with restored innovations
+	trialsStarted := 0
+	genEvaluator := &MockedGenerationEvaluator{}
+	genEvaluator.On("GenerationEvaluate", ctx, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		pop := args.Get(1).(*genetics.Population)
+		epoch := args.Get(2).(*Generation)
+		if epoch.Id == 0 {
+			trialsStarted++
+			assert.EqualValues(t, 1000, pop.InnovationsStore().LastInnovationNumber())
+			_, found := pop.FindLinkInnovation(1, 4, false)
+			assert.True(t, found, "restored innovation not found")
+		}
+	})
+
+	err = exp.ExecuteFromGenomesWithInnovations(ctx, seedGenomes, innovations, genEvaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+	assert.Equal(t, opts.NumRuns, trialsStarted)
+	assert.Equal(t, 1, innovations.Size(), "provided innovations store must not be modified")
+}
+
+func TestExperiment_ExecuteFromGenomes_no_genomes(t *testing.T) {
+	exp := Experiment{
+		Id: 0,
+	}
+	opts, err := neat.ReadNeatOptionsFromFile(xorConfigPath)
+	require.NoError(t, err, "failed to read NEAT options")
+	ctx := neat.NewContext(context.Background(), opts)
+
+	genEvaluator := &MockedGenerationEvaluator{}
+	err = exp.ExecuteFromGenomes(ctx, nil, genEvaluator, nil)
+	assert.Error(t, err)
+	genEvaluator.AssertNumberOfCalls(t, "GenerationEvaluate", 0)
+}
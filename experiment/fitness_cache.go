@@ -0,0 +1,191 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"sync"
+)
+
+// CachedFitness is the outcome of organism evaluation stored in the FitnessCache
+type CachedFitness struct {
+	// The fitness score of the organism
+	Fitness float64
+	// The error value of the organism
+	Error float64
+	// The flag to indicate whether the organism was a winner
+	IsWinner bool
+}
+
+// FitnessCache is the cache of evaluation results of organisms keyed by the canonical hash of their genomes.
+// When the capacity of the cache is exceeded, the oldest entries are evicted.
+type FitnessCache struct {
+	// The maximal number of entries in the cache, zero or negative means unlimited
+	capacity int
+	entries  map[uint64]CachedFitness
+	// The keys in order of insertion for eviction
+	keys  []uint64
+	mutex sync.Mutex
+}
+
+// NewFitnessCache creates new fitness cache with the given capacity. Zero or negative capacity means unlimited cache.
+func NewFitnessCache(capacity int) *FitnessCache {
+	return &FitnessCache{
+		capacity: capacity,
+		entries:  make(map[uint64]CachedFitness),
+		keys:     make([]uint64, 0),
+	}
+}
+
+// Get returns the evaluation results stored for the genome with given hash
+func (c *FitnessCache) Get(hash uint64) (CachedFitness, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	fitness, ok := c.entries[hash]
+	return fitness, ok
+}
+
+// Put stores the evaluation results of the genome with given hash
+func (c *FitnessCache) Put(hash uint64, fitness CachedFitness) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if _, ok := c.entries[hash]; !ok {
+		c.keys = append(c.keys, hash)
+	}
+	c.entries[hash] = fitness
+	if c.capacity > 0 && len(c.keys) > c.capacity {
+		evicted := len(c.keys) - c.capacity
+		for _, key := range c.keys[:evicted] {
+			delete(c.entries, key)
+		}
+		c.keys = append(c.keys[:0], c.keys[evicted:]...)
+	}
+}
+
+// Len returns the number of entries in the cache
+func (c *FitnessCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.entries)
+}
+
+// CachedFitnessEvaluator is the GenerationEvaluator which wraps any other evaluator to avoid re-evaluation of
+// organisms with genomes encoding already evaluated networks, e.g., the champion copies or offspring of mutations
+// which changed nothing. Before the evaluation, the fitness cache is consulted using the canonical hash of the
+// organism's genome and only organisms not found in the cache are evaluated by the wrapped evaluator. If there are
+// multiple organisms with the same hash in the population, only one of them is evaluated. The wrapped evaluator
+// receives the entire population with the rest of organisms marked to be skipped (see IsEvaluationSkipped).
+//
+// The cached fitness scores are reused as is, thus this evaluator should be used only with deterministic fitness
+// functions and can not wrap the NoisyFitnessEvaluator. The fitness scores estimated by the wrapped evaluator
+// (see genetics.Organism.FitnessEstimated), e.g., by the SurrogateEvaluator, are not cached, so the organisms with
+// such genomes are evaluated again in the following generations.
+type CachedFitnessEvaluator struct {
+	// The wrapped evaluator
+	Evaluator GenerationEvaluator
+	// The fitness cache
+	Cache *FitnessCache
+	// The number of decimal places to round link weights and trait parameters to when calculating genome hash
+	Precision int
+}
+
+// NewCachedFitnessEvaluator creates new evaluator which consults the provided fitness cache before evaluating
+// organisms with provided evaluator. The precision is the number of decimal places of link weights and trait
+// parameters taken into account when hashing genomes. Returns error if provided evaluator evaluates noisy fitness.
+func NewCachedFitnessEvaluator(evaluator GenerationEvaluator, cache *FitnessCache, precision int) (*CachedFitnessEvaluator, error) {
+	if evaluator == nil {
+		return nil, errors.New("wrapped evaluator must be provided")
+	}
+	if cache == nil {
+		return nil, errors.New("fitness cache must be provided")
+	}
+	if isNoisyEvaluator(evaluator) {
+		return nil, errors.New("noisy fitness scores can not be cached")
+	}
+	if precision < 0 {
+		return nil, errors.Errorf("the hashing precision must not be negative: %d", precision)
+	}
+	return &CachedFitnessEvaluator{
+		Evaluator: evaluator,
+		Cache:     cache,
+		Precision: precision,
+	}, nil
+}
+
+// GenerationEvaluate evaluates organisms of the population not found in the fitness cache using wrapped evaluator.
+// The number of cache hits and misses is stored into the epoch.
+func (e *CachedFitnessEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *Generation) error {
+	opts, ok := neat.FromContext(ctx)
+	if !ok {
+		return neat.ErrNEATOptionsNotFound
+	}
+
+	epoch.CacheHits, epoch.CacheMisses = 0, 0
+	hashes := make([]uint64, len(pop.Organisms))
+	toEvaluate := make(map[uint64]*genetics.Organism)
+	misses, missesHashes := make([]*genetics.Organism, 0), make([]uint64, 0)
+	skipped := make([]*genetics.Organism, 0)
+	for i, org := range pop.Organisms {
+		hashes[i] = org.Genotype.CanonicalHash(e.Precision)
+		if cached, found := e.Cache.Get(hashes[i]); found {
+			org.Fitness, org.Error, org.IsWinner = cached.Fitness, cached.Error, cached.IsWinner
+			org.FitnessEstimated = false
+			skipped = append(skipped, org)
+			epoch.CacheHits++
+		} else if _, found = toEvaluate[hashes[i]]; !found {
+			org.IsWinner = false
+			toEvaluate[hashes[i]] = org
+			misses = append(misses, org)
+			missesHashes = append(missesHashes, hashes[i])
+			epoch.CacheMisses++
+		} else {
+			skipped = append(skipped, org)
+		}
+	}
+
+	// the entire population is passed to the wrapped evaluator to have complete population snapshots written
+	if err := e.Evaluator.GenerationEvaluate(withSkippedOrganisms(ctx, skipped), pop, epoch); err != nil {
+		return err
+	}
+	for i, org := range misses {
+		org.IsWinner = org.IsWinner || (epoch.Solved && epoch.Champion == org)
+		if org.FitnessEstimated {
+			// only the true evaluation results are cached
+			continue
+		}
+		e.Cache.Put(missesHashes[i], CachedFitness{Fitness: org.Fitness, Error: org.Error, IsWinner: org.IsWinner})
+	}
+
+	// copy results to the organisms with the same genomes as evaluated ones and collect winners
+	for i, org := range pop.Organisms {
+		if evaluated, found := toEvaluate[hashes[i]]; found && evaluated != org {
+			org.Fitness, org.Error, org.IsWinner = evaluated.Fitness, evaluated.Error, evaluated.IsWinner
+			org.FitnessEstimated = evaluated.FitnessEstimated
+			epoch.CacheHits++
+		}
+		if org.IsWinner {
+			epoch.updateWinner(org, opts.PopSize)
+		}
+	}
+	neat.DebugLog(fmt.Sprintf("Fitness cache: %d hits, %d misses, %d entries",
+		epoch.CacheHits, epoch.CacheMisses, e.Cache.Len()))
+
+	epoch.FillPopulationStatistics(pop)
+	return nil
+}
+
+// isNoisyEvaluator checks whether provided evaluator or any of the evaluators wrapped by it is NoisyFitnessEvaluator
+func isNoisyEvaluator(evaluator GenerationEvaluator) bool {
+	switch e := evaluator.(type) {
+	case *NoisyFitnessEvaluator:
+		return true
+	case *SurrogateEvaluator:
+		return isNoisyEvaluator(e.Evaluator)
+	case *CachedFitnessEvaluator:
+		return isNoisyEvaluator(e.Evaluator)
+	default:
+		return false
+	}
+}
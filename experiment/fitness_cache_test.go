@@ -0,0 +1,228 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"math"
+	"testing"
+)
+
+// countingEvaluator assigns to organisms the fitness equal to their genome ID and records the number of evaluated
+// organisms and the size of the last population passed. The organism with genome ID equal to winnerId is reported
+// as the epoch champion.
+type countingEvaluator struct {
+	evaluated int
+	calls     int
+	popSize   int
+	winnerId  int
+}
+
+func (e *countingEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *Generation) error {
+	e.calls++
+	e.popSize = len(pop.Organisms)
+	for _, org := range pop.Organisms {
+		if IsEvaluationSkipped(ctx, org) {
+			continue
+		}
+		e.evaluated++
+		org.Fitness = float64(org.Genotype.Id)
+		org.Error = 1.0
+		if org.Genotype.Id == e.winnerId {
+			epoch.Solved = true
+			epoch.Champion = org
+		}
+	}
+	epoch.FillPopulationStatistics(pop)
+	return nil
+}
+
+func TestFitnessCache(t *testing.T) {
+	cache := NewFitnessCache(2)
+	cache.Put(1, CachedFitness{Fitness: 1})
+	cache.Put(2, CachedFitness{Fitness: 2})
+	assert.Equal(t, 2, cache.Len())
+
+	fitness, found := cache.Get(1)
+	assert.True(t, found)
+	assert.Equal(t, 1.0, fitness.Fitness)
+
+	// update existing
+	cache.Put(2, CachedFitness{Fitness: 3, IsWinner: true})
+	assert.Equal(t, 2, cache.Len())
+	fitness, found = cache.Get(2)
+	assert.True(t, found)
+	assert.Equal(t, CachedFitness{Fitness: 3, IsWinner: true}, fitness)
+
+	// evict the oldest
+	cache.Put(4, CachedFitness{Fitness: 4})
+	assert.Equal(t, 2, cache.Len())
+	_, found = cache.Get(1)
+	assert.False(t, found)
+	_, found = cache.Get(4)
+	assert.True(t, found)
+
+	// unlimited
+	cache = NewFitnessCache(0)
+	for i := 0; i < 100; i++ {
+		cache.Put(uint64(i), CachedFitness{})
+	}
+	assert.Equal(t, 100, cache.Len())
+}
+
+func TestNewCachedFitnessEvaluator(t *testing.T) {
+	evaluator, err := NewCachedFitnessEvaluator(&countingEvaluator{}, NewFitnessCache(0), 6)
+	require.NoError(t, err)
+	assert.NotNil(t, evaluator)
+
+	_, err = NewCachedFitnessEvaluator(nil, NewFitnessCache(0), 6)
+	assert.Error(t, err)
+	_, err = NewCachedFitnessEvaluator(&countingEvaluator{}, nil, 6)
+	assert.Error(t, err)
+	_, err = NewCachedFitnessEvaluator(&countingEvaluator{}, NewFitnessCache(0), -1)
+	assert.Error(t, err)
+
+	// noisy fitness can not be cached
+	noisy, err := NewNoisyFitnessEvaluator(&countingEvaluator{}, 3, MeanFitnessAggregation, 42)
+	require.NoError(t, err)
+	_, err = NewCachedFitnessEvaluator(noisy, NewFitnessCache(0), 6)
+	assert.Error(t, err)
+	surrogate, err := NewSurrogateEvaluator(noisy, 3, 0.5, 0)
+	require.NoError(t, err)
+	_, err = NewCachedFitnessEvaluator(surrogate, NewFitnessCache(0), 6)
+	assert.Error(t, err)
+}
+
+func TestCachedFitnessEvaluator_GenerationEvaluate(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	ctx := neat.NewContext(context.Background(), &neat.Options{PopSize: len(pop.Organisms)})
+
+	inner := &countingEvaluator{winnerId: -1}
+	evaluator, err := NewCachedFitnessEvaluator(inner, NewFitnessCache(0), 6)
+	require.NoError(t, err)
+
+	// the first evaluation - all misses
+	epoch := Generation{Id: 1}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.Equal(t, len(pop.Organisms), inner.evaluated)
+	assert.Equal(t, 0, epoch.CacheHits)
+	assert.Equal(t, len(pop.Organisms), epoch.CacheMisses)
+	assert.False(t, epoch.Solved)
+	assert.NotNil(t, epoch.Champion)
+	assert.Equal(t, len(pop.Species), epoch.Diversity)
+
+	// the second evaluation - all hits
+	for _, org := range pop.Organisms {
+		org.Fitness = 0
+	}
+	epoch = Generation{Id: 2}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.Equal(t, len(pop.Organisms), inner.evaluated, "cached organisms must not be evaluated")
+	assert.Equal(t, len(pop.Organisms), inner.popSize, "entire population must be passed")
+	assert.Equal(t, len(pop.Organisms), epoch.CacheHits)
+	assert.Equal(t, 0, epoch.CacheMisses)
+	for _, org := range pop.Organisms {
+		assert.Equal(t, float64(org.Genotype.Id), org.Fitness)
+	}
+}
+
+func TestCachedFitnessEvaluator_GenerationEvaluate_duplicates(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	ctx := neat.NewContext(context.Background(), &neat.Options{PopSize: len(pop.Organisms)})
+
+	// make clones of the first organism
+	winnerId := pop.Organisms[0].Genotype.Id
+	clones := 3
+	for i := 1; i <= clones; i++ {
+		buf := bytes.NewBufferString("")
+		err := pop.Organisms[0].Genotype.Write(buf)
+		require.NoError(t, err)
+		genome, err := genetics.ReadGenome(buf, 1000+i)
+		require.NoError(t, err)
+		pop.Organisms[i].Genotype = genome
+	}
+
+	inner := &countingEvaluator{winnerId: winnerId}
+	evaluator, err := NewCachedFitnessEvaluator(inner, NewFitnessCache(0), 6)
+	require.NoError(t, err)
+
+	epoch := Generation{Id: 1}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.Equal(t, len(pop.Organisms)-clones, inner.evaluated)
+	assert.Equal(t, len(pop.Organisms), inner.popSize, "entire population must be passed")
+	assert.Equal(t, clones, epoch.CacheHits)
+	assert.Equal(t, len(pop.Organisms)-clones, epoch.CacheMisses)
+	for i := 0; i <= clones; i++ {
+		assert.Equal(t, float64(winnerId), pop.Organisms[i].Fitness)
+		assert.True(t, pop.Organisms[i].IsWinner)
+	}
+	assert.True(t, epoch.Solved)
+	assert.Equal(t, pop.Organisms[0], epoch.Champion)
+
+	// the winner found in the cache
+	epoch = Generation{Id: 2}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.True(t, epoch.Solved)
+	require.NotNil(t, epoch.Champion)
+	assert.True(t, epoch.Champion.IsWinner)
+	assert.Equal(t, float64(winnerId), epoch.Champion.Fitness)
+}
+
+func TestCachedFitnessEvaluator_GenerationEvaluate_error(t *testing.T) {
+	evaluator, err := NewCachedFitnessEvaluator(&MockedGenerationEvaluator{}, NewFitnessCache(0), 6)
+	require.NoError(t, err)
+	err = evaluator.GenerationEvaluate(context.Background(), nil, &Generation{})
+	assert.ErrorIs(t, err, neat.ErrNEATOptionsNotFound)
+}
+
+func TestCachedFitnessEvaluator_GenerationEvaluate_surrogate(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	ctx := neat.NewContext(context.Background(), &neat.Options{
+		PopSize:       len(pop.Organisms),
+		DisjointCoeff: 0.5,
+		ExcessCoeff:   0.5,
+		MutdiffCoeff:  0.5,
+	})
+
+	inner := &countingEvaluator{winnerId: -1}
+	surrogate, err := NewSurrogateEvaluator(inner, 3, 0.25, 0)
+	require.NoError(t, err)
+	cache := NewFitnessCache(0)
+	evaluator, err := NewCachedFitnessEvaluator(surrogate, cache, 6)
+	require.NoError(t, err)
+
+	// collect data for the surrogate model
+	err = surrogate.GenerationEvaluate(ctx, pop, &Generation{Id: 1})
+	require.NoError(t, err)
+
+	// only truly evaluated organisms are cached
+	inner.evaluated = 0
+	epoch := Generation{Id: 2}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	expectedEvaluated := int(math.Ceil(0.25 * float64(len(pop.Organisms))))
+	require.Equal(t, expectedEvaluated, inner.evaluated)
+	require.Equal(t, len(pop.Organisms)-expectedEvaluated, epoch.SurrogateEstimated)
+	assert.Equal(t, expectedEvaluated, cache.Len())
+	for _, org := range pop.Organisms {
+		_, found := cache.Get(org.Genotype.CanonicalHash(6))
+		assert.Equal(t, !org.FitnessEstimated, found, "organism: %d", org.Genotype.Id)
+	}
+
+	// the organisms with estimated fitness are passed to the surrogate again
+	inner.evaluated = 0
+	epoch = Generation{Id: 3}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.Equal(t, expectedEvaluated, epoch.CacheHits)
+	assert.Equal(t, len(pop.Organisms)-expectedEvaluated, epoch.CacheMisses)
+	assert.Equal(t, int(math.Ceil(0.25*float64(epoch.CacheMisses))), inner.evaluated)
+	assert.Equal(t, expectedEvaluated+inner.evaluated, cache.Len())
+}
@@ -43,6 +43,29 @@ type Generation struct {
 
 	// The ID of Trial this Generation was evaluated in
 	TrialId int
+
+	// The IDs of input nodes found in population sorted in ascending order
+	InputIds []int
+	// The usage frequency of each input listed in InputIds, i.e., the fraction of organisms in population having
+	// at least one enabled link from the corresponding input
+	InputsUsage Floats
+
+	// The variance of fitness scores of each organism in population if it was evaluated multiple times,
+	// e.g., by NoisyFitnessEvaluator
+	FitnessVariance Floats
+
+	// The number of organisms which evaluation results was found in the fitness cache, e.g., by CachedFitnessEvaluator
+	CacheHits int
+	// The number of organisms which was not found in the fitness cache and was evaluated
+	CacheMisses int
+
+	// The number of organisms with fitness estimated by the surrogate model, e.g., by SurrogateEvaluator
+	SurrogateEstimated int
+	// The mean absolute error of the surrogate model fitness predictions for organisms evaluated in this epoch.
+	// NaN if not available.
+	SurrogateError float64
+	// The correlation between predicted and true fitness of organisms evaluated in this epoch. NaN if not available.
+	SurrogateCorrelation float64
 }
 
 // FillPopulationStatistics Collects statistics about given population
@@ -67,6 +90,20 @@ func (g *Generation) FillPopulationStatistics(pop *genetics.Population) {
 			}
 		}
 	}
+	g.InputIds, g.InputsUsage = pop.InputsUsage()
+}
+
+// updateWinner is to record provided winner organism as the champion of this epoch if it has better fitness
+// than the already recorded winner
+func (g *Generation) updateWinner(org *genetics.Organism, popSize int) {
+	if g.Solved && g.Champion != nil && org.Fitness <= g.Champion.Fitness {
+		return
+	}
+	g.Solved = true
+	g.WinnerNodes = len(org.Genotype.Nodes)
+	g.WinnerGenes = org.Genotype.Extrons()
+	g.WinnerEvals = popSize*g.Id + org.Genotype.Id
+	g.Champion = org
 }
 
 // Average the average fitness, age, and complexity among the best organisms of each species in the population
@@ -87,8 +124,17 @@ func (g *Generation) ChampionComplexity() int {
 	return organismComplexity(g.Champion)
 }
 
+// generationEncodingVersion is the version of the generation encoding format. The legacy format has no version and
+// starts with the generation ID. The versioned format starts with the negated version followed by the generation ID,
+// which is never negative, and has the statistics fields appended after the TrialId. This allows decoding of
+// the generations encoded in the legacy format.
+const generationEncodingVersion = 1
+
 // Encode is to encode the generation with provided GOB encoder
 func (g *Generation) Encode(enc *gob.Encoder) error {
+	if err := enc.EncodeValue(reflect.ValueOf(-generationEncodingVersion)); err != nil {
+		return err
+	}
 	if err := enc.EncodeValue(reflect.ValueOf(g.Id)); err != nil {
 		return err
 	}
@@ -125,6 +171,30 @@ func (g *Generation) Encode(enc *gob.Encoder) error {
 	if err := enc.EncodeValue(reflect.ValueOf(g.TrialId)); err != nil {
 		return err
 	}
+	if err := enc.Encode(g.InputIds); err != nil {
+		return err
+	}
+	if err := enc.Encode(g.InputsUsage); err != nil {
+		return err
+	}
+	if err := enc.Encode(g.FitnessVariance); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.CacheHits)); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.CacheMisses)); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.SurrogateEstimated)); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.SurrogateError)); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.SurrogateCorrelation)); err != nil {
+		return err
+	}
 
 	// encode best organism
 	if g.Champion != nil {
@@ -173,6 +243,16 @@ func (g *Generation) Decode(dec *gob.Decoder) error {
 	if err := dec.Decode(&g.Id); err != nil {
 		return errors.Wrap(err, "failed to decode Id")
 	}
+	// the legacy format starts with the generation ID and has no version
+	version := 0
+	if g.Id < 0 {
+		if version = -g.Id; version > generationEncodingVersion {
+			return errors.Errorf("unsupported generation encoding version: %d", version)
+		}
+		if err := dec.Decode(&g.Id); err != nil {
+			return errors.Wrap(err, "failed to decode Id")
+		}
+	}
 	if err := dec.Decode(&g.Executed); err != nil {
 		return errors.Wrap(err, "failed to decode Executed")
 	}
@@ -206,6 +286,11 @@ func (g *Generation) Decode(dec *gob.Decoder) error {
 	if err := dec.Decode(&g.TrialId); err != nil {
 		return errors.Wrap(err, "failed to decode TrialId")
 	}
+	if version > 0 {
+		if err := g.decodeStatistics(dec); err != nil {
+			return err
+		}
+	}
 
 	// decode organism
 	if org, err := decodeOrganism(dec); err != nil {
@@ -216,6 +301,35 @@ func (g *Generation) Decode(dec *gob.Decoder) error {
 	return nil
 }
 
+// decodeStatistics is to decode the statistics fields of the versioned generation encoding format
+func (g *Generation) decodeStatistics(dec *gob.Decoder) error {
+	if err := dec.Decode(&g.InputIds); err != nil {
+		return errors.Wrap(err, "failed to decode InputIds")
+	}
+	if err := dec.Decode(&g.InputsUsage); err != nil {
+		return errors.Wrap(err, "failed to decode InputsUsage")
+	}
+	if err := dec.Decode(&g.FitnessVariance); err != nil {
+		return errors.Wrap(err, "failed to decode FitnessVariance")
+	}
+	if err := dec.Decode(&g.CacheHits); err != nil {
+		return errors.Wrap(err, "failed to decode CacheHits")
+	}
+	if err := dec.Decode(&g.CacheMisses); err != nil {
+		return errors.Wrap(err, "failed to decode CacheMisses")
+	}
+	if err := dec.Decode(&g.SurrogateEstimated); err != nil {
+		return errors.Wrap(err, "failed to decode SurrogateEstimated")
+	}
+	if err := dec.Decode(&g.SurrogateError); err != nil {
+		return errors.Wrap(err, "failed to decode SurrogateError")
+	}
+	if err := dec.Decode(&g.SurrogateCorrelation); err != nil {
+		return errors.Wrap(err, "failed to decode SurrogateCorrelation")
+	}
+	return nil
+}
+
 func decodeOrganism(dec *gob.Decoder) (*genetics.Organism, error) {
 	org := genetics.Organism{}
 	if err := dec.Decode(&org.Fitness); err != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/yaricom/goNEAT/v4/neat/network"
 	"math"
 	"math/rand"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -43,6 +44,8 @@ func TestGeneration_FillPopulationStatistics(t *testing.T) {
 	assert.EqualValues(t, Floats{11, 25, 36, 32, 35}, gen.Complexity)
 	assert.NotNil(t, gen.Champion)
 	assert.Equal(t, maxFitness, gen.Champion.Fitness)
+	assert.Equal(t, len(gen.InputIds), len(gen.InputsUsage))
+	assert.True(t, len(gen.InputIds) > 0, "inputs usage expected")
 }
 
 func createGenerationWith(fitness Floats, ages Floats, complexities Floats) *Generation {
@@ -58,6 +61,14 @@ func TestGeneration_Encode_Decode(t *testing.T) {
 	genomeId, fitness := 10, 23.0
 	gen := buildTestGeneration(genomeId, fitness)
 	gen.TrialId = 10101
+	gen.InputIds = []int{1, 2, 3}
+	gen.InputsUsage = Floats{0.5, 1.0, 0.0}
+	gen.FitnessVariance = Floats{0.1, 0.2}
+	gen.CacheHits = 5
+	gen.CacheMisses = 7
+	gen.SurrogateEstimated = 3
+	gen.SurrogateError = 0.25
+	gen.SurrogateCorrelation = 0.9
 
 	var buff bytes.Buffer
 	enc := gob.NewEncoder(&buff)
@@ -77,6 +88,48 @@ func TestGeneration_Encode_Decode(t *testing.T) {
 	assert.EqualValues(t, gen, dgen)
 }
 
+// encodeLegacyGeneration is to encode the generation in the legacy format without version and statistics fields
+func encodeLegacyGeneration(t *testing.T, enc *gob.Encoder, g *Generation) {
+	for _, v := range []interface{}{g.Id, g.Executed, g.Solved, g.Fitness, g.Age, g.Complexity, g.Diversity,
+		g.WinnerEvals, g.WinnerNodes, g.WinnerGenes, g.Duration, g.TrialId} {
+		require.NoError(t, enc.EncodeValue(reflect.ValueOf(v)))
+	}
+	require.NoError(t, encodeOrganism(enc, g.Champion))
+}
+
+func TestGeneration_Decode_legacy(t *testing.T) {
+	gen := buildTestGeneration(10, 23.0)
+	gen.TrialId = 10101
+	nextGen := buildTestGeneration(11, 24.0)
+	nextGen.Id = 0
+
+	var buff bytes.Buffer
+	enc := gob.NewEncoder(&buff)
+	encodeLegacyGeneration(t, enc, gen)
+	encodeLegacyGeneration(t, enc, nextGen)
+
+	// decode generations
+	dec := gob.NewDecoder(bytes.NewBuffer(buff.Bytes()))
+	dgen := &Generation{}
+	err := dgen.Decode(dec)
+	require.NoError(t, err, "failed to decode generation")
+	assert.EqualValues(t, gen, dgen)
+	dgen = &Generation{}
+	err = dgen.Decode(dec)
+	require.NoError(t, err, "failed to decode next generation")
+	assert.EqualValues(t, nextGen, dgen)
+}
+
+func TestGeneration_Decode_unsupportedVersion(t *testing.T) {
+	var buff bytes.Buffer
+	enc := gob.NewEncoder(&buff)
+	require.NoError(t, enc.EncodeValue(reflect.ValueOf(-(generationEncodingVersion + 1))))
+
+	dgen := &Generation{}
+	err := dgen.Decode(gob.NewDecoder(bytes.NewBuffer(buff.Bytes())))
+	assert.EqualError(t, err, "unsupported generation encoding version: 2")
+}
+
 func TestGeneration_ChampionComplexity(t *testing.T) {
 	rand.Seed(42)
 	pop, maxFitness := buildTestPopulation(t)
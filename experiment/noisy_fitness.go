@@ -0,0 +1,213 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"math/rand"
+	"sort"
+)
+
+// FitnessAggregation defines how fitness scores of multiple evaluations of the same organism are combined
+type FitnessAggregation string
+
+const (
+	// MeanFitnessAggregation the mean of fitness scores
+	MeanFitnessAggregation FitnessAggregation = "mean"
+	// MedianFitnessAggregation the median of fitness scores
+	MedianFitnessAggregation FitnessAggregation = "median"
+	// WorstFitnessAggregation the lowest fitness score
+	WorstFitnessAggregation FitnessAggregation = "worst"
+)
+
+// Validate is to check if this aggregation type is supported by algorithm
+func (a FitnessAggregation) Validate() error {
+	if a != MeanFitnessAggregation && a != MedianFitnessAggregation && a != WorstFitnessAggregation {
+		return errors.Errorf("unsupported fitness aggregation type: [%s]", a)
+	}
+	return nil
+}
+
+// aggregate is to combine provided fitness scores
+func (a FitnessAggregation) aggregate(scores Floats) float64 {
+	switch a {
+	case MedianFitnessAggregation:
+		sorted := make(Floats, len(scores))
+		copy(sorted, scores)
+		sort.Float64s(sorted)
+		return sorted.Median()
+	case WorstFitnessAggregation:
+		return scores.Min()
+	default:
+		return scores.Mean()
+	}
+}
+
+// noisyFitnessHashPrecision is the precision of genome hashing used to identify the organisms survived into
+// the next generation unmodified
+const noisyFitnessHashPrecision = 12
+
+type evaluationSeedKey struct{}
+
+// noisySamples is the fitness scores and error values collected over evaluations of the organism
+type noisySamples struct {
+	fitness Floats
+	errors  Floats
+}
+
+// EvaluationSeedFromContext returns the seed of the random number generator to be used for the current evaluation
+// of the organisms when running under NoisyFitnessEvaluator
+func EvaluationSeedFromContext(ctx context.Context) (int64, bool) {
+	seed, ok := ctx.Value(evaluationSeedKey{}).(int64)
+	return seed, ok
+}
+
+// NoisyFitnessEvaluator is the GenerationEvaluator which wraps any other evaluator to handle noisy (stochastic)
+// fitness. The wrapped evaluator is invoked several times per generation, each time with a different seed of
+// the random number generator, and the fitness scores of each organism are aggregated. The organism is considered
+// a winner only if it was a winner in every evaluation. The organisms that survived into the next generation
+// unmodified (elites) keep the fitness scores and error values collected in previous generations, and their fitness
+// is aggregated over all accumulated scores, while the error is the mean of all accumulated error values.
+//
+// The seed of each evaluation is available to the wrapped evaluator through EvaluationSeedFromContext and should be
+// used to create its own random number generator to make evaluations reproducible. The global random number generator
+// is not affected. The accumulated fitness scores are dropped when the new trial starts.
+type NoisyFitnessEvaluator struct {
+	// The wrapped evaluator
+	Evaluator GenerationEvaluator
+	// The number of evaluations per organism in each generation
+	Evaluations int
+	// The method to combine fitness scores of the organism
+	Aggregation FitnessAggregation
+
+	// The source of evaluation seeds
+	rng *rand.Rand
+	// The samples accumulated for organisms evaluated in the current trial by canonical hash of their genomes
+	scores map[uint64]noisySamples
+	// The IDs of the trial and generation evaluated last
+	trialId, epochId int
+}
+
+// NewNoisyFitnessEvaluator creates new evaluator which evaluates each organism the specified number of times using
+// provided evaluator and aggregates fitness scores. The seed is used to produce seeds of the individual evaluations.
+func NewNoisyFitnessEvaluator(evaluator GenerationEvaluator, evaluations int, aggregation FitnessAggregation, seed int64) (*NoisyFitnessEvaluator, error) {
+	if evaluator == nil {
+		return nil, errors.New("wrapped evaluator must be provided")
+	}
+	if evaluations <= 0 {
+		return nil, errors.Errorf("the number of evaluations must be positive: %d", evaluations)
+	}
+	if err := aggregation.Validate(); err != nil {
+		return nil, err
+	}
+	return &NoisyFitnessEvaluator{
+		Evaluator:   evaluator,
+		Evaluations: evaluations,
+		Aggregation: aggregation,
+		rng:         rand.New(rand.NewSource(seed)),
+		scores:      make(map[uint64]noisySamples),
+		epochId:     -1,
+	}, nil
+}
+
+// GenerationEvaluate evaluates the population using wrapped evaluator several times and aggregates fitness scores
+// of organisms. The fitness variance of each organism is stored into the epoch.
+func (e *NoisyFitnessEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *Generation) error {
+	opts, ok := neat.FromContext(ctx)
+	if !ok {
+		return neat.ErrNEATOptionsNotFound
+	}
+
+	// the generations of the new trial start from scratch
+	if epoch.TrialId != e.trialId || epoch.Id <= e.epochId {
+		e.scores = make(map[uint64]noisySamples)
+	}
+	e.trialId, e.epochId = epoch.TrialId, epoch.Id
+
+	// the organisms skipped by the wrapping evaluator keep their fitness
+	skipped := make([]bool, len(pop.Organisms))
+	for i, org := range pop.Organisms {
+		skipped[i] = IsEvaluationSkipped(ctx, org)
+	}
+	fitness := make([]Floats, len(pop.Organisms))
+	errs := make([]Floats, len(pop.Organisms))
+	winner := make([]bool, len(pop.Organisms))
+	for i := range winner {
+		winner[i] = true
+	}
+	for k := 0; k < e.Evaluations; k++ {
+		seed := e.rng.Int63()
+		evalEpoch := Generation{Id: epoch.Id, TrialId: epoch.TrialId}
+		for i, org := range pop.Organisms {
+			if !skipped[i] {
+				org.IsWinner = false
+			}
+		}
+		if err := e.Evaluator.GenerationEvaluate(context.WithValue(ctx, evaluationSeedKey{}, seed), pop, &evalEpoch); err != nil {
+			return err
+		}
+		for i, org := range pop.Organisms {
+			if skipped[i] {
+				continue
+			}
+			fitness[i] = append(fitness[i], org.Fitness)
+			errs[i] = append(errs[i], org.Error)
+			isWinner := org.IsWinner || (evalEpoch.Solved && evalEpoch.Champion == org)
+			winner[i] = winner[i] && isWinner
+		}
+	}
+	// aggregate fitness scores, taking into account the ones accumulated by elites in previous generations and
+	// the ones of the organisms with identical genomes
+	hashes := make([]uint64, len(pop.Organisms))
+	scores := make(map[uint64]noisySamples, len(pop.Organisms))
+	elites, evaluated := 0, 0
+	for i, org := range pop.Organisms {
+		hashes[i] = org.Genotype.CanonicalHash(noisyFitnessHashPrecision)
+		if skipped[i] {
+			continue
+		}
+		evaluated++
+		accumulated, ok := scores[hashes[i]]
+		if !ok {
+			if accumulated, ok = e.scores[hashes[i]]; ok {
+				elites++
+			}
+		}
+		scores[hashes[i]] = noisySamples{
+			fitness: append(append(Floats{}, accumulated.fitness...), fitness[i]...),
+			errors:  append(append(Floats{}, accumulated.errors...), errs[i]...),
+		}
+	}
+	epoch.FitnessVariance = make(Floats, len(pop.Organisms))
+	for i, org := range pop.Organisms {
+		if skipped[i] {
+			continue
+		}
+		samples := scores[hashes[i]]
+		org.Fitness = e.Aggregation.aggregate(samples.fitness)
+		org.Error = samples.errors.Mean()
+		org.IsWinner = winner[i]
+		if len(samples.fitness) > 1 {
+			epoch.FitnessVariance[i] = samples.fitness.Variance()
+		}
+	}
+	// update the samples of evaluated genomes, the samples of genomes not evaluated in this generation are kept
+	for hash, samples := range scores {
+		e.scores[hash] = samples
+	}
+	neat.DebugLog(fmt.Sprintf("Noisy fitness: %d organisms evaluated %d times, %d elites re-evaluated",
+		evaluated, e.Evaluations, elites))
+
+	// collect the statistics using aggregated fitness
+	epoch.Solved = false
+	epoch.Champion = nil
+	for _, org := range pop.Organisms {
+		if org.IsWinner {
+			epoch.updateWinner(org, opts.PopSize)
+		}
+	}
+	epoch.FillPopulationStatistics(pop)
+	return nil
+}
@@ -0,0 +1,217 @@
+package experiment
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"math/rand"
+	"testing"
+)
+
+// sequenceEvaluator assigns to all not skipped organisms the fitness equal to the number of its invocations and makes
+// the first organism a winner in all evaluations, the second one only in the first evaluation
+type sequenceEvaluator struct {
+	calls int
+	seeds []int64
+}
+
+func (e *sequenceEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *Generation) error {
+	e.calls++
+	if seed, ok := EvaluationSeedFromContext(ctx); ok {
+		e.seeds = append(e.seeds, seed)
+	}
+	for i, org := range pop.Organisms {
+		if IsEvaluationSkipped(ctx, org) {
+			continue
+		}
+		org.Fitness = float64(e.calls)
+		org.Error = 1.0 / float64(e.calls)
+		org.IsWinner = i == 0 || (i == 1 && e.calls == 1)
+	}
+	epoch.FillPopulationStatistics(pop)
+	return nil
+}
+
+func TestFitnessAggregation_Validate(t *testing.T) {
+	for _, a := range []FitnessAggregation{MeanFitnessAggregation, MedianFitnessAggregation, WorstFitnessAggregation} {
+		assert.NoError(t, a.Validate())
+	}
+	assert.Error(t, FitnessAggregation("best").Validate())
+}
+
+func TestFitnessAggregation_aggregate(t *testing.T) {
+	scores := Floats{4, 1, 3, 10}
+	assert.Equal(t, 4.5, MeanFitnessAggregation.aggregate(scores))
+	assert.Equal(t, 3.0, MedianFitnessAggregation.aggregate(scores))
+	assert.Equal(t, 1.0, WorstFitnessAggregation.aggregate(scores))
+	assert.Equal(t, Floats{4, 1, 3, 10}, scores, "scores must not be modified")
+}
+
+func TestNewNoisyFitnessEvaluator(t *testing.T) {
+	evaluator, err := NewNoisyFitnessEvaluator(&sequenceEvaluator{}, 3, MeanFitnessAggregation, 42)
+	require.NoError(t, err)
+	assert.NotNil(t, evaluator)
+
+	_, err = NewNoisyFitnessEvaluator(nil, 3, MeanFitnessAggregation, 42)
+	assert.Error(t, err)
+	_, err = NewNoisyFitnessEvaluator(&sequenceEvaluator{}, 0, MeanFitnessAggregation, 42)
+	assert.Error(t, err)
+	_, err = NewNoisyFitnessEvaluator(&sequenceEvaluator{}, 3, "best", 42)
+	assert.Error(t, err)
+}
+
+func TestNoisyFitnessEvaluator_GenerationEvaluate(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	ctx := neat.NewContext(context.Background(), &neat.Options{PopSize: len(pop.Organisms)})
+
+	testCases := map[FitnessAggregation]float64{
+		MeanFitnessAggregation:   2.0,
+		MedianFitnessAggregation: 2.0,
+		WorstFitnessAggregation:  1.0,
+	}
+	for aggregation, expected := range testCases {
+		t.Run(string(aggregation), func(t *testing.T) {
+			inner := &sequenceEvaluator{}
+			evaluator, err := NewNoisyFitnessEvaluator(inner, 3, aggregation, 42)
+			require.NoError(t, err)
+
+			epoch := Generation{Id: 1}
+			err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+			require.NoError(t, err)
+
+			assert.Equal(t, 3, inner.calls)
+			require.Len(t, inner.seeds, 3)
+			assert.NotEqual(t, inner.seeds[0], inner.seeds[1])
+			assert.NotEqual(t, inner.seeds[1], inner.seeds[2])
+
+			require.Len(t, epoch.FitnessVariance, len(pop.Organisms))
+			for i, org := range pop.Organisms {
+				assert.Equal(t, expected, org.Fitness)
+				assert.InDelta(t, (1.0+0.5+1.0/3.0)/3.0, org.Error, 1e-9)
+				assert.Equal(t, 1.0, epoch.FitnessVariance[i])
+				assert.Equal(t, i == 0, org.IsWinner, "only first organism is winner in all evaluations")
+			}
+			assert.True(t, epoch.Solved)
+			assert.Equal(t, pop.Organisms[0], epoch.Champion)
+			assert.Equal(t, len(pop.Species), epoch.Diversity)
+		})
+	}
+}
+
+func TestNoisyFitnessEvaluator_GenerationEvaluate_elites(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	ctx := neat.NewContext(context.Background(), &neat.Options{PopSize: len(pop.Organisms)})
+
+	inner := &sequenceEvaluator{}
+	evaluator, err := NewNoisyFitnessEvaluator(inner, 2, MeanFitnessAggregation, 42)
+	require.NoError(t, err)
+
+	err = evaluator.GenerationEvaluate(ctx, pop, &Generation{Id: 1})
+	require.NoError(t, err)
+	for _, org := range pop.Organisms {
+		assert.Equal(t, 1.5, org.Fitness)
+	}
+
+	// change genome of the first organism to make it new offspring, other organisms are considered as elites
+	pop.Organisms[0].Genotype.Genes[0].Link.ConnectionWeight += 1.0
+
+	epoch := Generation{Id: 2}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.Equal(t, 3.5, pop.Organisms[0].Fitness)
+	assert.InDelta(t, 0.5, epoch.FitnessVariance[0], 1e-9)
+	assert.InDelta(t, (1.0/3.0+1.0/4.0)/2.0, pop.Organisms[0].Error, 1e-9)
+	for i, org := range pop.Organisms[1:] {
+		assert.Equal(t, 2.5, org.Fitness, "accumulated mean expected")
+		assert.InDelta(t, (1.0+1.0/2.0+1.0/3.0+1.0/4.0)/4.0, org.Error, 1e-9, "accumulated mean error expected")
+		assert.InDelta(t, Floats{1, 2, 3, 4}.Variance(), epoch.FitnessVariance[i+1], 1e-9)
+	}
+
+	// the scores of organisms absent from the evaluated population are kept
+	absent := pop.Organisms[len(pop.Organisms)-1]
+	pop.Organisms = pop.Organisms[:len(pop.Organisms)-1]
+	err = evaluator.GenerationEvaluate(ctx, pop, &Generation{Id: 3})
+	require.NoError(t, err)
+	pop.Organisms = append(pop.Organisms, absent)
+	err = evaluator.GenerationEvaluate(ctx, pop, &Generation{Id: 4})
+	require.NoError(t, err)
+	assert.Equal(t, Floats{1, 2, 3, 4, 7, 8}.Mean(), absent.Fitness)
+}
+
+func TestNoisyFitnessEvaluator_GenerationEvaluate_skipped(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	ctx := neat.NewContext(context.Background(), &neat.Options{PopSize: len(pop.Organisms)})
+
+	inner := &sequenceEvaluator{}
+	evaluator, err := NewNoisyFitnessEvaluator(inner, 2, MeanFitnessAggregation, 42)
+	require.NoError(t, err)
+
+	err = evaluator.GenerationEvaluate(ctx, pop, &Generation{Id: 1})
+	require.NoError(t, err)
+
+	// the skipped organism keeps its fitness and the scores accumulated so far
+	skipped := pop.Organisms[1]
+	skipped.Fitness, skipped.Error = 100, 0.5
+	err = evaluator.GenerationEvaluate(withSkippedOrganisms(ctx, []*genetics.Organism{skipped}), pop,
+		&Generation{Id: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, skipped.Fitness)
+	assert.Equal(t, 0.5, skipped.Error)
+	assert.Equal(t, 2.5, pop.Organisms[0].Fitness)
+	assert.Equal(t, Floats{1, 2}, evaluator.scores[skipped.Genotype.CanonicalHash(noisyFitnessHashPrecision)].fitness)
+
+	err = evaluator.GenerationEvaluate(ctx, pop, &Generation{Id: 3})
+	require.NoError(t, err)
+	assert.Equal(t, Floats{1, 2, 5, 6}, evaluator.scores[skipped.Genotype.CanonicalHash(noisyFitnessHashPrecision)].fitness)
+	assert.Equal(t, 3.5, skipped.Fitness)
+}
+
+func TestNoisyFitnessEvaluator_GenerationEvaluate_newTrial(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	ctx := neat.NewContext(context.Background(), &neat.Options{PopSize: len(pop.Organisms)})
+
+	inner := &sequenceEvaluator{}
+	evaluator, err := NewNoisyFitnessEvaluator(inner, 2, MeanFitnessAggregation, 42)
+	require.NoError(t, err)
+
+	err = evaluator.GenerationEvaluate(ctx, pop, &Generation{Id: 0, TrialId: 0})
+	require.NoError(t, err)
+	err = evaluator.GenerationEvaluate(ctx, pop, &Generation{Id: 1, TrialId: 0})
+	require.NoError(t, err)
+	for _, org := range pop.Organisms {
+		assert.Equal(t, 2.5, org.Fitness, "accumulated mean expected")
+	}
+
+	// the scores accumulated in previous trial are dropped
+	epoch := Generation{Id: 0, TrialId: 1}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	for i, org := range pop.Organisms {
+		assert.Equal(t, 5.5, org.Fitness)
+		assert.InDelta(t, 0.5, epoch.FitnessVariance[i], 1e-9)
+	}
+}
+
+func TestNoisyFitnessEvaluator_GenerationEvaluate_globalRand(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	ctx := neat.NewContext(context.Background(), &neat.Options{PopSize: len(pop.Organisms)})
+
+	evaluator, err := NewNoisyFitnessEvaluator(&sequenceEvaluator{}, 3, MeanFitnessAggregation, 42)
+	require.NoError(t, err)
+
+	rand.Seed(7)
+	expected := rand.Int63()
+	rand.Seed(7)
+	err = evaluator.GenerationEvaluate(ctx, pop, &Generation{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, expected, rand.Int63(), "global random number generator must not be reseeded")
+}
+
+func TestNoisyFitnessEvaluator_GenerationEvaluate_error(t *testing.T) {
+	evaluator, err := NewNoisyFitnessEvaluator(&MockedGenerationEvaluator{}, 2, MeanFitnessAggregation, 42)
+	require.NoError(t, err)
+	err = evaluator.GenerationEvaluate(context.Background(), nil, &Generation{})
+	assert.ErrorIs(t, err, neat.ErrNEATOptionsNotFound)
+}
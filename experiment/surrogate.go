@@ -0,0 +1,180 @@
+package experiment
+
+import (
+	"context"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"gonum.org/v1/gonum/stat"
+	"math"
+	"sort"
+)
+
+// surrogateSample is the genome with known fitness used by the surrogate model
+type surrogateSample struct {
+	genome  *genetics.Genome
+	fitness float64
+}
+
+// SurrogateEvaluator is the GenerationEvaluator which wraps any other evaluator to skip evaluation of unpromising
+// organisms. The fitness of each organism is predicted by the surrogate model using k-nearest neighbors regression
+// over the compatibility distance to the genomes evaluated so far. Only the top predicted fraction of organisms
+// defined by the pre-screen ratio are evaluated by the wrapped evaluator, and the rest get the predicted fitness
+// and flagged with genetics.Organism.FitnessEstimated. The wrapped evaluator receives the entire population with
+// the estimated organisms marked to be skipped (see IsEvaluationSkipped).
+//
+// Until the surrogate model has collected enough evaluated genomes, all organisms are evaluated by the wrapped
+// evaluator. The accuracy of the surrogate model measured on the evaluated organisms is stored into the Generation.
+type SurrogateEvaluator struct {
+	// The wrapped evaluator
+	Evaluator GenerationEvaluator
+	// The number of nearest neighbors to predict fitness from
+	Neighbors int
+	// The fraction of organisms with the best predicted fitness to be evaluated by the wrapped evaluator
+	PreScreenRatio float64
+	// The maximal number of evaluated genomes kept by the surrogate model, zero or negative means unlimited
+	ArchiveSize int
+
+	// The genomes evaluated so far in order of evaluation
+	archive []surrogateSample
+}
+
+// NewSurrogateEvaluator creates new surrogate-assisted evaluator which evaluates with provided evaluator only
+// the preScreenRatio fraction of organisms with the best fitness predicted by k-nearest neighbors regression over
+// the archive of evaluated genomes with given size.
+func NewSurrogateEvaluator(evaluator GenerationEvaluator, neighbors int, preScreenRatio float64, archiveSize int) (*SurrogateEvaluator, error) {
+	if evaluator == nil {
+		return nil, errors.New("wrapped evaluator must be provided")
+	}
+	if neighbors <= 0 {
+		return nil, errors.Errorf("the number of neighbors must be positive: %d", neighbors)
+	}
+	if preScreenRatio <= 0 || preScreenRatio > 1 {
+		return nil, errors.Errorf("the pre-screen ratio must be in range (0, 1]: %f", preScreenRatio)
+	}
+	if archiveSize > 0 && archiveSize < neighbors {
+		return nil, errors.Errorf("the archive size: %d is less than the number of neighbors: %d",
+			archiveSize, neighbors)
+	}
+	return &SurrogateEvaluator{
+		Evaluator:      evaluator,
+		Neighbors:      neighbors,
+		PreScreenRatio: preScreenRatio,
+		ArchiveSize:    archiveSize,
+		archive:        make([]surrogateSample, 0),
+	}, nil
+}
+
+// GenerationEvaluate evaluates the top predicted fraction of organisms with wrapped evaluator and assigns predicted
+// fitness to the rest of organisms. The number of estimated organisms and the surrogate accuracy are stored into
+// the epoch.
+func (e *SurrogateEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *Generation) error {
+	opts, ok := neat.FromContext(ctx)
+	if !ok {
+		return neat.ErrNEATOptionsNotFound
+	}
+
+	epoch.SurrogateEstimated = 0
+	epoch.SurrogateError, epoch.SurrogateCorrelation = math.NaN(), math.NaN()
+	// the organisms skipped by the wrapping evaluator are neither predicted nor evaluated
+	organisms := make(genetics.Organisms, 0, len(pop.Organisms))
+	for _, org := range pop.Organisms {
+		if !IsEvaluationSkipped(ctx, org) {
+			org.FitnessEstimated = false
+			organisms = append(organisms, org)
+		}
+	}
+	if len(e.archive) < e.Neighbors {
+		// not enough data to make predictions
+		if err := e.Evaluator.GenerationEvaluate(ctx, pop, epoch); err != nil {
+			return err
+		}
+		e.store(organisms)
+		return nil
+	}
+
+	// predict fitness and select the most promising organisms
+	predicted := make(map[*genetics.Organism]float64, len(organisms))
+	for _, org := range organisms {
+		predicted[org] = e.predict(org.Genotype, opts)
+	}
+	sort.SliceStable(organisms, func(i, j int) bool {
+		return predicted[organisms[i]] > predicted[organisms[j]]
+	})
+	evaluateNum := int(math.Ceil(e.PreScreenRatio * float64(len(organisms))))
+	promising, estimated := organisms[:evaluateNum], organisms[evaluateNum:]
+
+	for _, org := range estimated {
+		org.Fitness = predicted[org]
+		org.IsWinner = false
+		org.FitnessEstimated = true
+	}
+	// the entire population is passed to the wrapped evaluator to have complete population snapshots written
+	if err := e.Evaluator.GenerationEvaluate(withSkippedOrganisms(ctx, estimated), pop, epoch); err != nil {
+		return err
+	}
+	e.store(promising)
+
+	// collect the accuracy of the surrogate model
+	epoch.SurrogateEstimated = len(estimated)
+	trueFitness, predictedFitness := make(Floats, len(promising)), make(Floats, len(promising))
+	absErrors := make(Floats, len(promising))
+	for i, org := range promising {
+		trueFitness[i], predictedFitness[i] = org.Fitness, predicted[org]
+		absErrors[i] = math.Abs(org.Fitness - predicted[org])
+	}
+	epoch.SurrogateError = absErrors.Mean()
+	if len(promising) > 1 {
+		epoch.SurrogateCorrelation = stat.Correlation(trueFitness, predictedFitness, nil)
+	}
+	neat.DebugLog(fmt.Sprintf("Surrogate: %d organisms evaluated, %d estimated, MAE: %f, correlation: %f",
+		len(promising), len(estimated), epoch.SurrogateError, epoch.SurrogateCorrelation))
+
+	epoch.FillPopulationStatistics(pop)
+	return nil
+}
+
+// predict is to estimate fitness of the genome as the inverse distance weighted mean of fitness of k nearest
+// evaluated genomes. If there are evaluated genomes at zero distance, the mean of their fitness is returned.
+func (e *SurrogateEvaluator) predict(genome *genetics.Genome, opts *neat.Options) float64 {
+	distances := make([]float64, len(e.archive))
+	indices := make([]int, len(e.archive))
+	for i, sample := range e.archive {
+		distances[i] = genome.Compatibility(sample.genome, opts)
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return distances[indices[i]] < distances[indices[j]]
+	})
+	if len(indices) > e.Neighbors {
+		indices = indices[:e.Neighbors]
+	}
+
+	exact, exactNum := 0.0, 0
+	weighted, weights := 0.0, 0.0
+	for _, i := range indices {
+		if distances[i] == 0 {
+			exact += e.archive[i].fitness
+			exactNum++
+			continue
+		}
+		w := 1.0 / distances[i]
+		weighted += w * e.archive[i].fitness
+		weights += w
+	}
+	if exactNum > 0 {
+		return exact / float64(exactNum)
+	}
+	return weighted / weights
+}
+
+// store is to add evaluated organisms to the archive of the surrogate model evicting the oldest ones if needed
+func (e *SurrogateEvaluator) store(organisms []*genetics.Organism) {
+	for _, org := range organisms {
+		e.archive = append(e.archive, surrogateSample{genome: org.Genotype, fitness: org.Fitness})
+	}
+	if e.ArchiveSize > 0 && len(e.archive) > e.ArchiveSize {
+		e.archive = append(e.archive[:0], e.archive[len(e.archive)-e.ArchiveSize:]...)
+	}
+}
@@ -0,0 +1,119 @@
+package experiment
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"math"
+	"testing"
+)
+
+func TestNewSurrogateEvaluator(t *testing.T) {
+	evaluator, err := NewSurrogateEvaluator(&countingEvaluator{}, 3, 0.5, 10)
+	require.NoError(t, err)
+	assert.NotNil(t, evaluator)
+
+	_, err = NewSurrogateEvaluator(nil, 3, 0.5, 10)
+	assert.Error(t, err)
+	_, err = NewSurrogateEvaluator(&countingEvaluator{}, 0, 0.5, 10)
+	assert.Error(t, err)
+	_, err = NewSurrogateEvaluator(&countingEvaluator{}, 3, 0, 10)
+	assert.Error(t, err)
+	_, err = NewSurrogateEvaluator(&countingEvaluator{}, 3, 1.1, 10)
+	assert.Error(t, err)
+	_, err = NewSurrogateEvaluator(&countingEvaluator{}, 3, 0.5, 2)
+	assert.Error(t, err)
+	_, err = NewSurrogateEvaluator(&countingEvaluator{}, 3, 0.5, 0)
+	assert.NoError(t, err)
+}
+
+func TestSurrogateEvaluator_GenerationEvaluate(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	ctx := neat.NewContext(context.Background(), &neat.Options{
+		PopSize:       len(pop.Organisms),
+		DisjointCoeff: 0.5,
+		ExcessCoeff:   0.5,
+		MutdiffCoeff:  0.5,
+	})
+
+	inner := &countingEvaluator{winnerId: -1}
+	evaluator, err := NewSurrogateEvaluator(inner, 3, 0.25, 0)
+	require.NoError(t, err)
+
+	// the first evaluation - all organisms evaluated to collect data
+	epoch := Generation{Id: 1}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.Equal(t, len(pop.Organisms), inner.evaluated)
+	assert.Equal(t, 0, epoch.SurrogateEstimated)
+	assert.True(t, math.IsNaN(epoch.SurrogateError))
+	assert.True(t, math.IsNaN(epoch.SurrogateCorrelation))
+	assert.Len(t, evaluator.archive, len(pop.Organisms))
+
+	// the second evaluation - the same genomes are predicted exactly and only the top fraction is evaluated
+	for _, org := range pop.Organisms {
+		org.Fitness = 0
+	}
+	inner.evaluated = 0
+	epoch = Generation{Id: 2}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	expectedEvaluated := int(math.Ceil(0.25 * float64(len(pop.Organisms))))
+	assert.Equal(t, expectedEvaluated, inner.evaluated)
+	assert.Equal(t, len(pop.Organisms), inner.popSize, "entire population must be passed")
+	assert.Equal(t, len(pop.Organisms)-expectedEvaluated, epoch.SurrogateEstimated)
+	estimated := 0
+	for _, org := range pop.Organisms {
+		assert.Equal(t, float64(org.Genotype.Id), org.Fitness)
+		if org.FitnessEstimated {
+			estimated++
+		}
+	}
+	assert.Equal(t, epoch.SurrogateEstimated, estimated)
+	assert.Equal(t, 0.0, epoch.SurrogateError)
+	assert.InDelta(t, 1.0, epoch.SurrogateCorrelation, 1e-9)
+	assert.NotNil(t, epoch.Champion)
+	assert.Len(t, evaluator.archive, len(pop.Organisms)+expectedEvaluated)
+}
+
+func TestSurrogateEvaluator_predict(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	opts := &neat.Options{DisjointCoeff: 0.5, ExcessCoeff: 0.5, MutdiffCoeff: 0.5}
+	evaluator, err := NewSurrogateEvaluator(&countingEvaluator{}, 2, 0.5, 0)
+	require.NoError(t, err)
+
+	pop.Organisms[0].Fitness, pop.Organisms[1].Fitness = 10, 20
+	evaluator.store(pop.Organisms[:2])
+
+	// exact match
+	assert.Equal(t, 10.0, evaluator.predict(pop.Organisms[0].Genotype, opts))
+
+	// inverse distance weighted
+	genome := pop.Organisms[2].Genotype
+	d0 := genome.Compatibility(pop.Organisms[0].Genotype, opts)
+	d1 := genome.Compatibility(pop.Organisms[1].Genotype, opts)
+	require.True(t, d0 > 0 && d1 > 0)
+	expected := (10/d0 + 20/d1) / (1/d0 + 1/d1)
+	assert.InDelta(t, expected, evaluator.predict(genome, opts), 1e-9)
+}
+
+func TestSurrogateEvaluator_store(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	evaluator, err := NewSurrogateEvaluator(&countingEvaluator{}, 3, 0.5, 10)
+	require.NoError(t, err)
+
+	evaluator.store(pop.Organisms)
+	require.Len(t, evaluator.archive, 10)
+	last := pop.Organisms[len(pop.Organisms)-10:]
+	for i, sample := range evaluator.archive {
+		assert.Equal(t, last[i].Genotype, sample.genome)
+	}
+}
+
+func TestSurrogateEvaluator_GenerationEvaluate_error(t *testing.T) {
+	evaluator, err := NewSurrogateEvaluator(&MockedGenerationEvaluator{}, 3, 0.5, 10)
+	require.NoError(t, err)
+	err = evaluator.GenerationEvaluate(context.Background(), nil, &Generation{})
+	assert.ErrorIs(t, err, neat.ErrNEATOptionsNotFound)
+}
@@ -3,6 +3,7 @@ package experiment
 import (
 	"encoding/gob"
 	"github.com/yaricom/goNEAT/v4/neat/genetics"
+	"gonum.org/v1/gonum/mat"
 	"math"
 	"sort"
 	"time"
@@ -117,6 +118,27 @@ func (t *Trial) Diversity() Floats {
 	return x
 }
 
+// InputsUsage returns the usage frequencies of inputs for each epoch as matrix with row per epoch and column per input.
+// Returns nil if no inputs usage was collected.
+func (t *Trial) InputsUsage() *mat.Dense {
+	inputs := 0
+	for _, e := range t.Generations {
+		if len(e.InputsUsage) > inputs {
+			inputs = len(e.InputsUsage)
+		}
+	}
+	if inputs == 0 {
+		return nil
+	}
+	x := mat.NewDense(len(t.Generations), inputs, nil)
+	for i, e := range t.Generations {
+		for j, usage := range e.InputsUsage {
+			x.Set(i, j, usage)
+		}
+	}
+	return x
+}
+
 // Average the average fitness, age, and complexity of the best organisms per species for each epoch in this trial
 func (t *Trial) Average() (fitness, age, complexity Floats) {
 	fitness = make(Floats, len(t.Generations))
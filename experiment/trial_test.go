@@ -143,6 +143,25 @@ func TestTrial_Diversity_emptyEpochs(t *testing.T) {
 	assert.Equal(t, 0, len(div))
 }
 
+func TestTrial_InputsUsage(t *testing.T) {
+	trial := Trial{Id: 1, Generations: []Generation{
+		{Id: 0, InputsUsage: Floats{0.1, 0.2}},
+		{Id: 1, InputsUsage: Floats{0.3, 0.4, 0.5}},
+	}}
+	usage := trial.InputsUsage()
+	require.NotNil(t, usage)
+	rows, cols := usage.Dims()
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, 3, cols)
+	assert.EqualValues(t, []float64{0.1, 0.2, 0}, usage.RawRowView(0))
+	assert.EqualValues(t, []float64{0.3, 0.4, 0.5}, usage.RawRowView(1))
+}
+
+func TestTrial_InputsUsage_emptyEpochs(t *testing.T) {
+	trial := Trial{Id: 1, Generations: make([]Generation, 0)}
+	assert.Nil(t, trial.InputsUsage())
+}
+
 func TestTrial_Average(t *testing.T) {
 	numGen := 4
 	trial := buildTestTrial(1, numGen)
@@ -9,10 +9,15 @@ import (
 	"github.com/yaricom/goNEAT/v4/neat/genetics"
 	"github.com/yaricom/goNEAT/v4/neat/network"
 	"github.com/yaricom/goNEAT/v4/neat/network/formats"
+	"io"
 	"log"
 	"os"
 )
 
+// InnovationsFileSuffix is the suffix of the file with innovations store written next to the population file. It is
+// the file extension, so that innovations files are skipped when reading genome files from the directory.
+const InnovationsFileSuffix = ".innovations"
+
 // WriteGenomePlain is to write genome of the organism to the genomeFile in the outDir directory using plain encoding.
 // The method return path to the file if successful or error if failed.
 func WriteGenomePlain(genomeFile, outDir string, org *genetics.Organism, epoch *experiment.Generation) (string, error) {
@@ -65,17 +70,35 @@ func WriteGenomeCytoscapeJSON(genomeFile, outDir string, org *genetics.Organism,
 }
 
 // WritePopulationPlain is to write genomes of the entire population using plain encoding in the outDir directory.
-// The methods return path to the file if successful or error if failed.
+// The innovations store of the population is written next to it into the file with InnovationsFileSuffix appended,
+// so that the evolution can be resumed from the saved population. The methods return path to the population file
+// if successful or error if failed.
 func WritePopulationPlain(outDir string, pop *genetics.Population, epoch *experiment.Generation) (string, error) {
 	popPath := fmt.Sprintf("%s/gen_%d", CreateOutDirForTrial(outDir, epoch.TrialId), epoch.Id)
-	if file, err := os.Create(popPath); err != nil {
+	if err := writeToFile(popPath, pop.WriteBySpecies); err != nil {
 		return "", err
-	} else if err = pop.WriteBySpecies(file); err != nil {
+	}
+	if err := writeToFile(popPath+InnovationsFileSuffix, pop.WriteInnovations); err != nil {
 		return "", err
 	}
 	return popPath, nil
 }
 
+// writeToFile creates the file at the given path and writes data into it using provided write function. The file
+// is closed afterwards and the error of closing is returned if writing succeeded.
+func writeToFile(path string, write func(w io.Writer) error) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return write(file)
+}
+
 // CreateOutDirForTrial allows creating the output directory for specific trial of the experiment using standard name.
 func CreateOutDirForTrial(outDir string, trialID int) string {
 	dir := fmt.Sprintf("%s/%d", outDir, trialID)
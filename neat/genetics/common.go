@@ -5,6 +5,7 @@ import (
 	"errors"
 	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/network"
+	"path/filepath"
 	"strings"
 )
 
@@ -75,3 +76,14 @@ func genomeEncodingFromFileName(fileName string) GenomeEncoding {
 		return PlainGenomeEncoding
 	}
 }
+
+// isGenomeFileName checks whether the file with given name can hold genome, i.e., it either has no extension as
+// the plain encoded genome files or has YAML extension.
+func isGenomeFileName(fileName string) bool {
+	switch filepath.Ext(fileName) {
+	case "", ".yml", ".yaml":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,161 @@
+package genetics
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"sort"
+)
+
+// ErrEliteLost is returned by the epoch executors when elite organisms were lost during reproduction and
+// neat.Options.ElitismStrict is set
+var ErrEliteLost = errors.New("elite organisms lost during reproduction")
+
+// ElitismReport is the outcome of the elitism policy applied during the last epoch of the population
+type ElitismReport struct {
+	// The number of elite organisms expected to be copied unmodified into the new generation
+	ElitesExpected int
+	// The number of elite organisms actually copied unmodified into the new generation
+	ElitesPreserved int
+	// The flag to indicate that the best species of the previous generation died without offspring
+	BestSpeciesLost bool
+	// The flag to indicate that the best ever genome was re-inserted into the new generation
+	BestEverReinserted bool
+}
+
+// ElitesLost returns the number of elite organisms which was not copied into the new generation
+func (r ElitismReport) ElitesLost() int {
+	return r.ElitesExpected - r.ElitesPreserved
+}
+
+// IsLost returns true if either any elite organism or the best species was lost
+func (r ElitismReport) IsLost() bool {
+	return r.ElitesLost() > 0 || r.BestSpeciesLost
+}
+
+func (r ElitismReport) String() string {
+	return fmt.Sprintf("elites preserved: %d of %d, best species lost: %t, best ever re-inserted: %t",
+		r.ElitesPreserved, r.ElitesExpected, r.BestSpeciesLost, r.BestEverReinserted)
+}
+
+// prepareElitism marks the population-wide elites and updates the best ever genome according to the elitism options.
+// Returns true if the best ever genome is not present in the population, or its species produces no offspring and
+// will be purged, and it should be re-inserted after reproduction. In this case, one offspring slot is reserved for it.
+func (p *Population) prepareElitism(sortedSpecies []*Species, opts *neat.Options) (bool, error) {
+	p.EliteReport = ElitismReport{}
+
+	if opts.PopulationElitism > 0 {
+		organisms := make(byOriginalFitness, 0, len(p.Organisms))
+		for _, org := range p.Organisms {
+			if org.Species.ExpectedOffspring > 0 {
+				organisms = append(organisms, org)
+			}
+		}
+		sort.Stable(sort.Reverse(organisms))
+		for i := 0; i < opts.PopulationElitism && i < len(organisms); i++ {
+			organisms[i].isPopulationElite = true
+			organisms[i].toEliminate = false
+		}
+	}
+
+	if !opts.KeepBestEver || len(sortedSpecies) == 0 {
+		return false, nil
+	}
+
+	championSpecies := sortedSpecies[0]
+	champion := championSpecies.Organisms[0]
+	if p.bestEverGenome == nil || champion.originalFitness > p.bestEverFitness {
+		genome, err := champion.Genotype.duplicate(champion.Genotype.Id)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to store the best ever genome")
+		}
+		p.bestEverGenome = genome
+		p.bestEverFitness = champion.originalFitness
+	}
+
+	if champion.originalFitness >= p.bestEverFitness && championSpecies.ExpectedOffspring > 0 {
+		// the best ever is in population and its species survives - make sure it will be copied
+		champion.isPopulationElite = true
+		champion.toEliminate = false
+		return false, nil
+	}
+
+	// reserve the slot for the best ever genome in the species with the most offspring
+	var donor *Species
+	for _, sp := range sortedSpecies {
+		if donor == nil || sp.ExpectedOffspring > donor.ExpectedOffspring {
+			donor = sp
+		}
+	}
+	if donor == nil || donor.ExpectedOffspring == 0 {
+		return false, nil
+	}
+	donor.ExpectedOffspring--
+	return true, nil
+}
+
+// completeElitism is to finish elitism policy after the reproduction: re-insert the best ever genome into
+// the babies if requested and collect the outcome of the elitism from all species.
+func (p *Population) completeElitism(babies []*Organism, reinsertBestEver bool, generation int) ([]*Organism, error) {
+	if reinsertBestEver && p.bestEverGenome != nil {
+		genome, err := p.bestEverGenome.duplicate(len(babies))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to re-insert the best ever genome")
+		}
+		baby, err := NewOrganism(0.0, genome, generation)
+		if err != nil {
+			return nil, err
+		}
+		babies = append(babies, baby)
+		p.EliteReport.BestEverReinserted = true
+		neat.InfoLog(fmt.Sprintf("POPULATION: The best ever genome with fitness: %f re-inserted into generation: %d",
+			p.bestEverFitness, generation))
+	}
+
+	for _, sp := range p.Species {
+		p.EliteReport.ElitesExpected += sp.elitesExpected
+		p.EliteReport.ElitesPreserved += sp.elitesPreserved
+	}
+	return babies, nil
+}
+
+// checkElitism checks if the best species survived reproduction and reports elites lost. Returns error only if
+// the loss detected and strict elitism requested by options.
+// N.B. the mutated offspring of best species may be added to other more compatible species and as result
+// the best species from previous generation will be removed, but their offspring still be alive.
+func (p *Population) checkElitism(bestSpeciesId int, bestSpeciesReproduced bool, opts *neat.Options) error {
+	bestOk := false
+	for _, currSpecies := range p.Species {
+		if currSpecies.Id == bestSpeciesId {
+			bestOk = true
+			if neat.LogLevel == neat.LogLevelDebug {
+				neat.DebugLog(fmt.Sprintf("POPULATION: The best survived species Id: %d, max fitness ever: %f",
+					bestSpeciesId, currSpecies.MaxFitnessEver))
+			}
+			break
+		}
+	}
+	p.EliteReport.BestSpeciesLost = !bestOk && !bestSpeciesReproduced
+
+	if !p.EliteReport.IsLost() {
+		return nil
+	}
+	if opts.ElitismStrict {
+		return errors.Wrap(ErrEliteLost, p.EliteReport.String())
+	}
+	neat.WarnLog(fmt.Sprintf("POPULATION: Elitism policy violated, %s", p.EliteReport))
+	return nil
+}
+
+// byOriginalFitness is to sort organisms by their original fitness
+type byOriginalFitness []*Organism
+
+func (f byOriginalFitness) Len() int {
+	return len(f)
+}
+func (f byOriginalFitness) Swap(i, j int) {
+	f[i], f[j] = f[j], f[i]
+}
+func (f byOriginalFitness) Less(i, j int) bool {
+	return f[i].originalFitness < f[j].originalFitness
+}
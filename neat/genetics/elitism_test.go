@@ -0,0 +1,217 @@
+package genetics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"math/rand"
+	"testing"
+)
+
+func TestSpecies_findElites(t *testing.T) {
+	sp, err := buildSpeciesWithOrganisms(1)
+	require.NoError(t, err)
+	sp.findChampion() // sort organisms
+
+	// legacy behavior - champion is elite only for big enough species
+	opts := &neat.Options{}
+	sp.ExpectedOffspring = 5
+	assert.Len(t, sp.findElites(opts), 0)
+	sp.ExpectedOffspring = 6
+	elites := sp.findElites(opts)
+	require.Len(t, elites, 1)
+	assert.Equal(t, sp.Organisms[0], elites[0])
+
+	// explicit species elitism
+	opts.SpeciesElitism = 2
+	sp.ExpectedOffspring = 1
+	elites = sp.findElites(opts)
+	require.Len(t, elites, 2)
+	assert.Equal(t, sp.Organisms[0], elites[0])
+	assert.Equal(t, sp.Organisms[1], elites[1])
+
+	// population elite
+	sp.Organisms[2].isPopulationElite = true
+	elites = sp.findElites(opts)
+	require.Len(t, elites, 3)
+	assert.Equal(t, sp.Organisms[2], elites[2])
+}
+
+func TestSpecies_reproduce_elitesLost(t *testing.T) {
+	rand.Seed(42)
+	sp, err := buildSpeciesWithOrganisms(1)
+	require.NoError(t, err)
+	sp.findChampion()
+
+	opts := &neat.Options{
+		SpeciesElitism:     3,
+		MutateOnlyProb:     1.0,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	pop := newPopulation()
+	sp.ExpectedOffspring = 2
+	babies, err := sp.reproduce(opts.NeatContext(), 1, pop, []*Species{sp})
+	require.NoError(t, err)
+	assert.Len(t, babies, 2)
+	assert.Equal(t, 3, sp.elitesExpected)
+	assert.Equal(t, 2, sp.elitesPreserved)
+
+	sp.ExpectedOffspring = 4
+	babies, err = sp.reproduce(opts.NeatContext(), 1, pop, []*Species{sp})
+	require.NoError(t, err)
+	assert.Len(t, babies, 4)
+	assert.Equal(t, 3, sp.elitesExpected)
+	assert.Equal(t, 3, sp.elitesPreserved)
+}
+
+func TestPopulation_checkElitism(t *testing.T) {
+	sp, err := buildSpeciesWithOrganisms(1)
+	require.NoError(t, err)
+	pop := newPopulation()
+	pop.Species = []*Species{sp}
+
+	neat.LogLevel = neat.LogLevelInfo
+	opts := &neat.Options{}
+	// best species alive
+	err = pop.checkElitism(1, true, opts)
+	assert.NoError(t, err)
+	assert.False(t, pop.EliteReport.IsLost())
+
+	// best species died, but reproduced
+	err = pop.checkElitism(2, true, opts)
+	assert.NoError(t, err)
+	assert.False(t, pop.EliteReport.BestSpeciesLost)
+
+	// best species died without offspring - only reported
+	err = pop.checkElitism(2, false, opts)
+	assert.NoError(t, err)
+	assert.True(t, pop.EliteReport.BestSpeciesLost)
+
+	// strict policy
+	opts.ElitismStrict = true
+	err = pop.checkElitism(2, false, opts)
+	assert.ErrorIs(t, err, ErrEliteLost)
+
+	// elites lost
+	pop.EliteReport = ElitismReport{ElitesExpected: 2, ElitesPreserved: 1}
+	err = pop.checkElitism(1, true, opts)
+	assert.ErrorIs(t, err, ErrEliteLost)
+	assert.Equal(t, 1, pop.EliteReport.ElitesLost())
+}
+
+func TestPopulation_prepareElitism_championSpeciesPurged(t *testing.T) {
+	best, err := buildSpeciesWithOrganisms(2)
+	require.NoError(t, err)
+	other, err := buildSpeciesWithOrganisms(1)
+	require.NoError(t, err)
+	pop := newPopulation()
+	pop.Species = []*Species{best, other}
+	for _, sp := range pop.Species {
+		sp.findChampion()
+		for _, org := range sp.Organisms {
+			org.originalFitness = org.Fitness
+			pop.Organisms = append(pop.Organisms, org)
+		}
+	}
+	sortedSpecies := []*Species{best, other}
+	opts := &neat.Options{KeepBestEver: true}
+
+	// the species of champion survives - champion is copied
+	best.ExpectedOffspring, other.ExpectedOffspring = 2, 4
+	reinsert, err := pop.prepareElitism(sortedSpecies, opts)
+	require.NoError(t, err)
+	assert.False(t, reinsert)
+	assert.True(t, best.Organisms[0].isPopulationElite)
+	assert.Equal(t, 30.0, pop.bestEverFitness)
+
+	// the species of champion produces no offspring - champion is re-inserted into the reserved slot
+	best.Organisms[0].isPopulationElite = false
+	best.ExpectedOffspring, other.ExpectedOffspring = 0, 6
+	reinsert, err = pop.prepareElitism(sortedSpecies, opts)
+	require.NoError(t, err)
+	assert.True(t, reinsert)
+	assert.False(t, best.Organisms[0].isPopulationElite)
+	assert.Equal(t, 5, other.ExpectedOffspring)
+	require.NotNil(t, pop.bestEverGenome)
+	assert.Equal(t, best.Organisms[0].Genotype.Id, pop.bestEverGenome.Id)
+}
+
+func TestPopulationEpochExecutor_NextEpoch_elitism(t *testing.T) {
+	rand.Seed(42)
+	in, out, maxHidden, n := 3, 2, 15, 3
+	conf := &neat.Options{
+		CompatThreshold:    0.5,
+		DropOffAge:         10,
+		SurvivalThresh:     0.2,
+		AgeSignificance:    1.0,
+		PopSize:            30,
+		MutateOnlyProb:     0.5,
+		MutateAddNodeProb:  0.1,
+		MutateAddLinkProb:  0.1,
+		NewLinkTries:       10,
+		SpeciesElitism:     1,
+		PopulationElitism:  2,
+		KeepBestEver:       true,
+		ElitismStrict:      true,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen, err := newGenomeRand(1, in, out, n, maxHidden, false, 0.8, conf)
+	require.NoError(t, err, "failed to create random genome")
+
+	pop, err := NewPopulation(gen, conf)
+	require.NoError(t, err, "failed to create population")
+
+	executors := []PopulationEpochExecutor{&SequentialPopulationEpochExecutor{}, &ParallelPopulationEpochExecutor{}}
+	for _, ex := range executors {
+		for i := 0; i < 5; i++ {
+			for _, org := range pop.Organisms {
+				org.Fitness = rand.Float64() * 10.0
+			}
+			err = ex.NextEpoch(conf.NeatContext(), i+1, pop)
+			require.NoError(t, err, "failed at epoch: %d", i)
+			assert.Len(t, pop.Organisms, conf.PopSize)
+			assert.True(t, pop.EliteReport.ElitesExpected >= conf.PopulationElitism)
+			assert.False(t, pop.EliteReport.IsLost(), "elites lost: %s", pop.EliteReport)
+		}
+	}
+}
+
+func TestPopulationEpochExecutor_NextEpoch_reinsertBestEver(t *testing.T) {
+	rand.Seed(42)
+	in, out, maxHidden, n := 3, 2, 15, 3
+	conf := &neat.Options{
+		CompatThreshold:    0.5,
+		DropOffAge:         10,
+		SurvivalThresh:     0.2,
+		PopSize:            30,
+		MutateOnlyProb:     1.0,
+		KeepBestEver:       true,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen, err := newGenomeRand(1, in, out, n, maxHidden, false, 0.8, conf)
+	require.NoError(t, err, "failed to create random genome")
+
+	pop, err := NewPopulation(gen, conf)
+	require.NoError(t, err, "failed to create population")
+
+	// set the best ever genome with fitness above any in population
+	pop.bestEverGenome, err = gen.duplicate(100)
+	require.NoError(t, err)
+	pop.bestEverFitness = 100.0
+
+	for _, org := range pop.Organisms {
+		org.Fitness = rand.Float64()
+	}
+	ex := SequentialPopulationEpochExecutor{}
+	err = ex.NextEpoch(conf.NeatContext(), 1, pop)
+	require.NoError(t, err)
+	assert.Len(t, pop.Organisms, conf.PopSize)
+	assert.True(t, pop.EliteReport.BestEverReinserted)
+}
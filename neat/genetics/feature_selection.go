@@ -0,0 +1,98 @@
+package genetics
+
+import (
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"math/rand"
+	"sort"
+)
+
+// selectFeature prepares this genome for the FS-NEAT (feature selection NEAT) mode. All links originating from
+// the input nodes are removed and exactly one link from randomly selected input node to randomly selected output
+// node is added. The links from the bias and hidden nodes are kept, thus the seed genome can keep outputs
+// connected to the bias to make sure that all outputs are activated. The remaining inputs are left disconnected
+// and will be connected through evolution by the link addition mutators.
+func (g *Genome) selectFeature(innovations InnovationsObserver, opts *neat.Options) error {
+	inputs := make([]*network.NNode, 0)
+	outputs := make([]*network.NNode, 0)
+	for _, n := range g.Nodes {
+		switch n.NeuronType {
+		case network.InputNeuron:
+			inputs = append(inputs, n)
+		case network.OutputNeuron:
+			outputs = append(outputs, n)
+		}
+	}
+	if len(inputs) == 0 || len(outputs) == 0 {
+		return errors.New("genome has no input or output nodes to select feature from")
+	}
+	input := inputs[rand.Intn(len(inputs))]
+	output := outputs[rand.Intn(len(outputs))]
+
+	// remove links from inputs keeping the one between selected nodes if found
+	var selectedGene *Gene
+	genes := make([]*Gene, 0, len(g.Genes))
+	for _, gene := range g.Genes {
+		if gene.Link.InNode.NeuronType != network.InputNeuron {
+			genes = append(genes, gene)
+		} else if gene.Link.InNode.Id == input.Id && gene.Link.OutNode.Id == output.Id && !gene.Link.IsRecurrent {
+			selectedGene = gene
+		}
+	}
+
+	if selectedGene == nil {
+		// Check to see if this innovation already occurred in the population
+		if inn, found := findLinkInnovation(innovations, input.Id, output.Id, false); found {
+			selectedGene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight, input, output,
+				false, inn.InnovationNum, 0)
+		} else {
+			traitNum := rand.Intn(len(g.Traits))
+			newWeight := opts.RandomInitialWeight(1, 1.0)
+			nextInnovId := innovations.NextInnovationNumber()
+			selectedGene = NewGeneWithTrait(g.Traits[traitNum], newWeight, input, output,
+				false, nextInnovId, newWeight)
+			innovations.StoreInnovation(*NewInnovationForLink(input.Id, output.Id, nextInnovId, newWeight, traitNum))
+		}
+	}
+	selectedGene.IsEnabled = true
+	g.Genes = geneInsert(genes, selectedGene)
+	return nil
+}
+
+// InputsUsage returns IDs of the input nodes found in genomes of this population sorted in ascending order along
+// with the usage frequency of each input. The usage frequency is the fraction of organisms having at least one
+// enabled link originating from the corresponding input node. It allows seeing which inputs (features) was selected
+// by evolution, e.g., when running in the FS-NEAT mode.
+func (p *Population) InputsUsage() ([]int, []float64) {
+	counts := make(map[int]int)
+	for _, org := range p.Organisms {
+		for _, n := range org.Genotype.Nodes {
+			if _, ok := counts[n.Id]; !ok && n.NeuronType == network.InputNeuron {
+				counts[n.Id] = 0
+			}
+		}
+		used := make(map[int]bool)
+		for _, gene := range org.Genotype.Genes {
+			if gene.IsEnabled && gene.Link.InNode.NeuronType == network.InputNeuron {
+				used[gene.Link.InNode.Id] = true
+			}
+		}
+		for id := range used {
+			counts[id]++
+		}
+	}
+
+	ids := make([]int, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	usage := make([]float64, len(ids))
+	if len(p.Organisms) > 0 {
+		for i, id := range ids {
+			usage[i] = float64(counts[id]) / float64(len(p.Organisms))
+		}
+	}
+	return ids, usage
+}
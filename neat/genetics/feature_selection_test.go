@@ -0,0 +1,127 @@
+package genetics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"math/rand"
+	"testing"
+)
+
+// countInputLinks returns the number of links originating from the input nodes of the genome
+func countInputLinks(g *Genome) int {
+	count := 0
+	for _, gene := range g.Genes {
+		if gene.Link.InNode.NeuronType == network.InputNeuron {
+			count++
+		}
+	}
+	return count
+}
+
+func TestGenome_selectFeature(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	pop := newPopulation()
+	pop.innovations.updateLastInnovationNumber(3)
+
+	err := gnome.selectFeature(pop, &neat.Options{})
+	require.NoError(t, err, "failed to select feature")
+	require.Len(t, gnome.Genes, 2)
+	assert.Equal(t, 1, countInputLinks(gnome))
+	// the link from bias kept
+	assert.Equal(t, 3, gnome.Genes[1].Link.InNode.Id)
+	// the link from input reused from the seed genome
+	assert.EqualValues(t, gnome.Genes[0].Link.InNode.Id, gnome.Genes[0].InnovationNum)
+	assert.Equal(t, 0, pop.innovations.Size(), "no new innovations expected")
+
+	_, err = gnome.Genesis(1)
+	assert.NoError(t, err, "failed to create phenotype")
+}
+
+func TestGenome_selectFeature_newInnovation(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	// remove all links from inputs
+	gnome.Genes = gnome.Genes[2:]
+	pop := newPopulation()
+	pop.innovations.updateLastInnovationNumber(3)
+
+	err := gnome.selectFeature(pop, &neat.Options{})
+	require.NoError(t, err, "failed to select feature")
+	require.Len(t, gnome.Genes, 2)
+	assert.Equal(t, 1, countInputLinks(gnome))
+	require.Equal(t, 1, pop.innovations.Size(), "new innovation expected")
+	assert.EqualValues(t, 4, gnome.Genes[1].InnovationNum)
+
+	// the same innovation must be used for the same link in other genome
+	for i := 0; i < 10; i++ {
+		other := buildTestGenome(2)
+		other.Genes = other.Genes[2:]
+		err = other.selectFeature(pop, &neat.Options{})
+		require.NoError(t, err, "failed to select feature")
+		for _, gene := range other.Genes {
+			if gene.Link.InNode.Id == gnome.Genes[1].Link.InNode.Id {
+				assert.EqualValues(t, 4, gene.InnovationNum)
+			}
+		}
+	}
+	assert.Equal(t, 2, pop.innovations.Size(), "only two possible innovations")
+}
+
+func TestGenome_selectFeature_noInputs(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Nodes = gnome.Nodes[2:]
+	err := gnome.selectFeature(newPopulation(), &neat.Options{})
+	assert.Error(t, err)
+}
+
+func TestNewPopulation_featureSelection(t *testing.T) {
+	rand.Seed(42)
+	in, out, maxHidden, linkProb := 10, 3, 0, 1.0
+	gnome, err := newGenomeRand(1, in, out, 0, maxHidden, false, linkProb, &neat.Options{})
+	require.NoError(t, err, "failed to create seed genome")
+
+	opts := &neat.Options{
+		PopSize:          50,
+		CompatThreshold:  0.5,
+		FeatureSelection: true,
+	}
+	pop, err := NewPopulation(gnome, opts)
+	require.NoError(t, err, "failed to create population")
+	require.Len(t, pop.Organisms, opts.PopSize)
+	for _, org := range pop.Organisms {
+		assert.Equal(t, 1, countInputLinks(org.Genotype), "exactly one input link expected")
+	}
+
+	// the last input node is bias
+	ids, usage := pop.InputsUsage()
+	require.Len(t, ids, in-1)
+	require.Len(t, usage, in-1)
+	total := 0.0
+	for i, id := range ids {
+		assert.Equal(t, i+1, id)
+		total += usage[i]
+	}
+	assert.InDelta(t, 1.0, total, 1e-9, "each organism uses exactly one input")
+}
+
+func TestPopulation_InputsUsage(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Genes[1].IsEnabled = false
+	org1, err := NewOrganism(0, gnome, 1)
+	require.NoError(t, err)
+	org2, err := NewOrganism(0, buildTestGenome(2), 1)
+	require.NoError(t, err)
+	pop := newPopulation()
+	pop.Organisms = []*Organism{org1, org2}
+
+	ids, usage := pop.InputsUsage()
+	assert.Equal(t, []int{1, 2}, ids)
+	assert.Equal(t, []float64{1.0, 0.5}, usage)
+
+	ids, usage = newPopulation().InputsUsage()
+	assert.Empty(t, ids)
+	assert.Empty(t, usage)
+}
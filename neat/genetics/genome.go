@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"github.com/yaricom/goNEAT/v4/neat"
-	"github.com/yaricom/goNEAT/v4/neat/math"
 	"github.com/yaricom/goNEAT/v4/neat/network"
 	"io"
 	"math/rand"
@@ -123,6 +122,11 @@ func newGenomeRand(newId, in, out, n, maxHidden int, recurrent bool, linkProb fl
 		} else {
 			newNode.ActivationType = activationType
 		}
+		if aggregationType, err := opts.RandomNodeAggregationType(); err != nil {
+			return nil, err
+		} else {
+			newNode.AggregationType = aggregationType
+		}
 		newNode.Trait = newTrait
 		gnome.addNode(newNode)
 	}
@@ -182,9 +186,8 @@ func newGenomeRand(newId, in, out, n, maxHidden int, recurrent bool, linkProb fl
 						}
 					}
 
-					// Create the gene
-					weight := float64(math.RandSign()) * rand.Float64()
-					gene := NewGeneWithTrait(newTrait, weight, inNode, outNode, flagRecurrent, int64(count), weight)
+					// Create the gene, its weight will be assigned later when fan-in of all nodes is known
+					gene := NewGeneWithTrait(newTrait, 0, inNode, outNode, flagRecurrent, int64(count), 0)
 
 					//Add the gene to the genome
 					gnome.Genes = append(gnome.Genes, gene)
@@ -197,6 +200,13 @@ func newGenomeRand(newId, in, out, n, maxHidden int, recurrent bool, linkProb fl
 			inNode, outNode = nil, nil
 		}
 	}
+
+	// Assign initial weights of the genes
+	for _, gene := range gnome.Genes {
+		weight := opts.RandomInitialWeight(gnome.fanIn(gene.Link.OutNode.Id), 1.0)
+		gene.Link.ConnectionWeight = weight
+		gene.MutationNum = weight
+	}
 	return &gnome, nil
 }
 
@@ -349,6 +359,38 @@ func (g *Genome) getNextGeneInnovNum() (int64, error) {
 	return innNum + int64(1), nil
 }
 
+// Returns the maximal ID of the node in this Genome, including control nodes of modules
+func (g *Genome) maxNodeId() int {
+	id := 0
+	for _, n := range g.Nodes {
+		if n.Id > id {
+			id = n.Id
+		}
+	}
+	for _, cg := range g.ControlGenes {
+		if cg.ControlNode.Id > id {
+			id = cg.ControlNode.Id
+		}
+	}
+	return id
+}
+
+// Returns the maximal innovation number among connection and control genes of this Genome
+func (g *Genome) maxInnovationNum() int64 {
+	innNum := int64(0)
+	for _, gn := range g.Genes {
+		if gn.InnovationNum > innNum {
+			innNum = gn.InnovationNum
+		}
+	}
+	for _, cg := range g.ControlGenes {
+		if cg.InnovationNum > innNum {
+			innNum = cg.InnovationNum
+		}
+	}
+	return innNum
+}
+
 func (g *Genome) addNode(node *network.NNode) {
 	g.Nodes = append(g.Nodes, node)
 	g.mapNodeId(node)
@@ -378,6 +420,17 @@ func (g *Genome) haveNode(nodeId int) bool {
 	return ok
 }
 
+// Returns the number of enabled genes connecting into the node with given ID
+func (g *Genome) fanIn(nodeId int) int {
+	count := 0
+	for _, gn := range g.Genes {
+		if gn.IsEnabled && gn.Link.OutNode.Id == nodeId {
+			count++
+		}
+	}
+	return count
+}
+
 // Returns true if this Genome already includes provided gene
 func (g *Genome) haveGene(gene *Gene) bool {
 	if inn, _ := g.getNextGeneInnovNum(); gene.InnovationNum >= inn {
@@ -0,0 +1,363 @@
+package genetics
+
+import (
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+)
+
+// GenomeBuilder provides fluent API to construct the seed genomes in code. The nodes get sequential IDs in order of
+// creation unless added with explicit IDs, and the genes get sequential innovation numbers in order of creation. The
+// errors found during construction are reported by Build, which also verifies the created genome.
+//
+// Example of the XOR experiment seed genome:
+//
+//	gnome, err := NewGenomeBuilder().
+//		Trait(1, 0.1, 0, 0, 0, 0, 0, 0, 0).
+//		Bias().
+//		Inputs(2).
+//		Outputs(1, math.SigmoidSteepenedActivation).
+//		FullyConnect().
+//		Build()
+type GenomeBuilder struct {
+	// The ID of the genome
+	id int
+	// The traits of the genome
+	traits []*neat.Trait
+	// The trait assigned to the created nodes and genes
+	currentTrait *neat.Trait
+	// The flag to indicate whether traits should be assigned to the created genes in round-robin order
+	cycleTraits bool
+	// The index of the next trait to be assigned to the gene in round-robin order
+	nextTraitIndex int
+
+	// The nodes of the genome by ID
+	nodes map[int]*network.NNode
+	// The sensor nodes (inputs and bias) in order of creation
+	sensors []*network.NNode
+	// The hidden nodes in layers in order of creation
+	hiddenLayers [][]*network.NNode
+	// The output nodes in order of creation
+	outputs []*network.NNode
+	// The maximal node ID seen so far
+	maxNodeId int
+
+	// The connection genes in order of creation
+	genes []*Gene
+	// The connected nodes pairs to check for duplicate genes
+	connections map[geneLinkKey]bool
+	// The MIMO control genes in order of creation
+	controlGenes []*MIMOControlGene
+	// The last assigned innovation number
+	innovation int64
+
+	// The first error found during construction
+	err error
+}
+
+type geneLinkKey struct {
+	inId, outId int
+	recurrent   bool
+}
+
+// NewGenomeBuilder creates new builder of the genome with ID 1
+func NewGenomeBuilder() *GenomeBuilder {
+	return &GenomeBuilder{
+		id:          1,
+		nodes:       make(map[int]*network.NNode),
+		connections: make(map[geneLinkKey]bool),
+	}
+}
+
+// WithId sets the ID of the genome
+func (b *GenomeBuilder) WithId(id int) *GenomeBuilder {
+	b.id = id
+	return b
+}
+
+// Trait adds trait with given ID and parameters to the genome. The trait parameters are padded with zeros to the
+// neat.NumTraitParams length. Use WithTrait or CycleTraits to assign traits to the created nodes and genes.
+func (b *GenomeBuilder) Trait(id int, params ...float64) *GenomeBuilder {
+	if b.err != nil {
+		return b
+	}
+	if TraitWithId(id, b.traits) != nil {
+		b.err = errors.Errorf("duplicate trait ID: %d", id)
+		return b
+	}
+	if len(params) > neat.NumTraitParams {
+		b.err = errors.Errorf("too many parameters of trait: %d, maximum: %d", id, neat.NumTraitParams)
+		return b
+	}
+	trait := neat.NewTrait()
+	trait.Id = id
+	trait.Params = make([]float64, neat.NumTraitParams)
+	copy(trait.Params, params)
+	b.traits = append(b.traits, trait)
+	return b
+}
+
+// WithTrait sets the trait with given ID to be assigned to all subsequently created nodes and genes. The zero ID
+// resets the trait.
+func (b *GenomeBuilder) WithTrait(id int) *GenomeBuilder {
+	if b.err != nil {
+		return b
+	}
+	b.cycleTraits = false
+	if id == 0 {
+		b.currentTrait = nil
+		return b
+	}
+	if b.currentTrait = TraitWithId(id, b.traits); b.currentTrait == nil {
+		b.err = errors.Errorf("trait with ID: %d not found", id)
+	}
+	return b
+}
+
+// CycleTraits sets the traits of the genome to be assigned in round-robin order to all subsequently created genes
+// as it is usually done in the seed genome files. The created nodes get no trait.
+func (b *GenomeBuilder) CycleTraits() *GenomeBuilder {
+	if b.err != nil {
+		return b
+	}
+	if len(b.traits) == 0 {
+		b.err = errors.New("no traits defined to be cycled")
+		return b
+	}
+	b.cycleTraits = true
+	b.currentTrait = nil
+	b.nextTraitIndex = 0
+	return b
+}
+
+// Inputs adds given number of input sensors
+func (b *GenomeBuilder) Inputs(count int) *GenomeBuilder {
+	for i := 0; i < count && b.err == nil; i++ {
+		b.sensors = append(b.sensors, b.addNode(b.maxNodeId+1, network.InputNeuron, math.NullActivation))
+	}
+	return b
+}
+
+// Bias adds the bias sensor
+func (b *GenomeBuilder) Bias() *GenomeBuilder {
+	if b.err == nil {
+		b.sensors = append(b.sensors, b.addNode(b.maxNodeId+1, network.BiasNeuron, math.NullActivation))
+	}
+	return b
+}
+
+// Outputs adds given number of output nodes with specified activation function
+func (b *GenomeBuilder) Outputs(count int, activation math.NodeActivationType) *GenomeBuilder {
+	for i := 0; i < count && b.err == nil; i++ {
+		b.outputs = append(b.outputs, b.addNode(b.maxNodeId+1, network.OutputNeuron, activation))
+	}
+	return b
+}
+
+// Hidden adds the layer of hidden nodes of given size with specified activation function. The layers are used by
+// FullyConnect.
+func (b *GenomeBuilder) Hidden(count int, activation math.NodeActivationType) *GenomeBuilder {
+	layer := make([]*network.NNode, 0, count)
+	for i := 0; i < count && b.err == nil; i++ {
+		layer = append(layer, b.addNode(b.maxNodeId+1, network.HiddenNeuron, activation))
+	}
+	if b.err == nil {
+		b.hiddenLayers = append(b.hiddenLayers, layer)
+	}
+	return b
+}
+
+// Node adds the node with explicit ID, neuron type and activation function. The hidden node is added to the last
+// hidden layer.
+func (b *GenomeBuilder) Node(id int, neuronType network.NodeNeuronType, activation math.NodeActivationType) *GenomeBuilder {
+	if b.err != nil {
+		return b
+	}
+	node := b.addNode(id, neuronType, activation)
+	if b.err != nil {
+		return b
+	}
+	switch neuronType {
+	case network.InputNeuron, network.BiasNeuron:
+		b.sensors = append(b.sensors, node)
+	case network.OutputNeuron:
+		b.outputs = append(b.outputs, node)
+	default:
+		if len(b.hiddenLayers) == 0 {
+			b.hiddenLayers = append(b.hiddenLayers, nil)
+		}
+		last := len(b.hiddenLayers) - 1
+		b.hiddenLayers[last] = append(b.hiddenLayers[last], node)
+	}
+	return b
+}
+
+// FullyConnect connects all nodes of the consecutive layers with zero weight links: the sensors with the first hidden
+// layer, each hidden layer with the next one, and the last hidden layer with the outputs. If there are no hidden
+// layers, the sensors are connected with the outputs. Only the nodes added before the call are connected. The weights
+// of the links are randomized by population when spawning the initial organisms.
+func (b *GenomeBuilder) FullyConnect() *GenomeBuilder {
+	if b.err != nil {
+		return b
+	}
+	layers := make([][]*network.NNode, 0, len(b.hiddenLayers)+2)
+	layers = append(layers, b.sensors)
+	layers = append(layers, b.hiddenLayers...)
+	layers = append(layers, b.outputs)
+	for i := 1; i < len(layers); i++ {
+		for _, target := range layers[i] {
+			for _, source := range layers[i-1] {
+				if b.addGene(source.Id, target.Id, 0, false); b.err != nil {
+					return b
+				}
+			}
+		}
+	}
+	return b
+}
+
+// Connect adds the connection gene linking nodes with given IDs
+func (b *GenomeBuilder) Connect(inId, outId int, weight float64) *GenomeBuilder {
+	if b.err == nil {
+		b.addGene(inId, outId, weight, false)
+	}
+	return b
+}
+
+// ConnectRecurrent adds the connection gene with recurrent link between nodes with given IDs
+func (b *GenomeBuilder) ConnectRecurrent(inId, outId int, weight float64) *GenomeBuilder {
+	if b.err == nil {
+		b.addGene(inId, outId, weight, true)
+	}
+	return b
+}
+
+// Module adds the MIMO control gene with control node of given activation function connecting the input nodes
+// with the output nodes of the module. The control node gets the next node ID.
+func (b *GenomeBuilder) Module(activation math.NodeActivationType, inputIds, outputIds []int) *GenomeBuilder {
+	if b.err != nil {
+		return b
+	}
+	if len(inputIds) == 0 || len(outputIds) == 0 {
+		b.err = errors.New("module must have inputs and outputs")
+		return b
+	}
+	b.maxNodeId++
+	controlNode := network.NewNNode(b.maxNodeId, network.HiddenNeuron)
+	controlNode.ActivationType = activation
+	controlNode.Trait = b.currentTrait
+	for _, id := range inputIds {
+		inNode, ok := b.nodes[id]
+		if !ok {
+			b.err = errors.Errorf("input node: %d of module not found", id)
+			return b
+		}
+		controlNode.Incoming = append(controlNode.Incoming, network.NewLink(1.0, inNode, controlNode, false))
+	}
+	for _, id := range outputIds {
+		outNode, ok := b.nodes[id]
+		if !ok {
+			b.err = errors.Errorf("output node: %d of module not found", id)
+			return b
+		}
+		if outNode.IsSensor() {
+			b.err = errors.Errorf("output node: %d of module is a sensor", id)
+			return b
+		}
+		controlNode.Outgoing = append(controlNode.Outgoing, network.NewLink(1.0, controlNode, outNode, false))
+	}
+	b.innovation++
+	b.controlGenes = append(b.controlGenes, NewMIMOGene(controlNode, b.innovation, 0, true))
+	return b
+}
+
+// Build creates and verifies the genome. It returns the first error found during construction if any. If no traits
+// were defined, the default trait is added to the genome to be used by mutations.
+func (b *GenomeBuilder) Build() (*Genome, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.sensors) == 0 {
+		return nil, errors.New("genome has no sensors")
+	}
+	if len(b.outputs) == 0 {
+		return nil, errors.New("genome has no outputs")
+	}
+
+	traits := b.traits
+	if len(traits) == 0 {
+		// Create a dummy trait to be used by mutations
+		trait := neat.NewTrait()
+		trait.Id = 1
+		trait.Params = make([]float64, neat.NumTraitParams)
+		traits = []*neat.Trait{trait}
+	}
+	gnome := NewGenome(b.id, traits, make([]*network.NNode, 0, len(b.nodes)), b.genes)
+	for _, node := range b.nodes {
+		gnome.nodeInsert(node)
+	}
+	if len(b.controlGenes) > 0 {
+		gnome.ControlGenes = b.controlGenes
+	}
+	if ok, err := gnome.verify(); !ok {
+		return nil, errors.Wrap(err, "failed to verify built genome")
+	}
+	return gnome, nil
+}
+
+func (b *GenomeBuilder) addNode(id int, neuronType network.NodeNeuronType, activation math.NodeActivationType) *network.NNode {
+	if id <= 0 {
+		b.err = errors.Errorf("node ID must be positive, found: %d", id)
+		return nil
+	}
+	if _, ok := b.nodes[id]; ok {
+		b.err = errors.Errorf("duplicate node ID: %d", id)
+		return nil
+	}
+	for _, cg := range b.controlGenes {
+		if cg.ControlNode.Id == id {
+			b.err = errors.Errorf("node ID: %d is used by control node", id)
+			return nil
+		}
+	}
+	node := network.NewNNode(id, neuronType)
+	node.ActivationType = activation
+	node.Trait = b.currentTrait
+	b.nodes[id] = node
+	if id > b.maxNodeId {
+		b.maxNodeId = id
+	}
+	return node
+}
+
+func (b *GenomeBuilder) addGene(inId, outId int, weight float64, recurrent bool) {
+	inNode, ok := b.nodes[inId]
+	if !ok {
+		b.err = errors.Errorf("input node: %d of link not found", inId)
+		return
+	}
+	outNode, ok := b.nodes[outId]
+	if !ok {
+		b.err = errors.Errorf("output node: %d of link not found", outId)
+		return
+	}
+	if outNode.IsSensor() {
+		b.err = errors.Errorf("link: %d -> %d leads into sensor", inId, outId)
+		return
+	}
+	key := geneLinkKey{inId: inId, outId: outId, recurrent: recurrent}
+	if b.connections[key] {
+		b.err = errors.Errorf("duplicate link: %d -> %d", inId, outId)
+		return
+	}
+	b.connections[key] = true
+
+	trait := b.currentTrait
+	if b.cycleTraits {
+		trait = b.traits[b.nextTraitIndex%len(b.traits)]
+		b.nextTraitIndex++
+	}
+	b.innovation++
+	b.genes = append(b.genes, NewGeneWithTrait(trait, weight, inNode, outNode, recurrent, b.innovation, 0))
+}
@@ -0,0 +1,146 @@
+package genetics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"os"
+	"testing"
+)
+
+func TestGenomeBuilder_Build_xorSeed(t *testing.T) {
+	gnome, err := NewGenomeBuilder().
+		Trait(1, 0.1, 0, 0, 0, 0, 0, 0, 0).
+		Trait(2, 0.2).
+		Trait(3, 0.3).
+		CycleTraits().
+		Bias().
+		Inputs(2).
+		Outputs(1, math.SigmoidSteepenedActivation).
+		FullyConnect().
+		Build()
+	require.NoError(t, err)
+
+	genomeFile, err := os.Open("../../data/xorstartgenes")
+	require.NoError(t, err, "failed to open genome file")
+	r, err := NewGenomeReader(genomeFile, PlainGenomeEncoding)
+	require.NoError(t, err, "failed to create genome reader")
+	expected, err := r.Read()
+	require.NoError(t, err, "failed to read genome")
+
+	equal, err := gnome.IsEqual(expected)
+	assert.NoError(t, err)
+	assert.True(t, equal)
+}
+
+func TestGenomeBuilder_Build_hidden(t *testing.T) {
+	gnome, err := NewGenomeBuilder().
+		WithId(5).
+		Inputs(3).
+		Bias().
+		Hidden(2, math.TanhActivation).
+		Hidden(3, math.SineActivation).
+		Outputs(2, math.SigmoidSteepenedActivation).
+		FullyConnect().
+		ConnectRecurrent(11, 8, 0.5).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, gnome.Id)
+	require.Len(t, gnome.Nodes, 11)
+	for i, node := range gnome.Nodes {
+		assert.Equal(t, i+1, node.Id)
+	}
+	assert.Equal(t, network.BiasNeuron, gnome.Nodes[3].NeuronType)
+	assert.Equal(t, math.TanhActivation, gnome.Nodes[4].ActivationType)
+	assert.Equal(t, math.SineActivation, gnome.Nodes[6].ActivationType)
+	assert.Equal(t, network.OutputNeuron, gnome.Nodes[10].NeuronType)
+
+	// 4 * 2 + 2 * 3 + 3 * 2 + 1
+	require.Len(t, gnome.Genes, 21)
+	for i, gene := range gnome.Genes {
+		assert.EqualValues(t, i+1, gene.InnovationNum)
+		assert.Nil(t, gene.Link.Trait)
+	}
+	assert.True(t, gnome.Genes[20].Link.IsRecurrent)
+
+	// the default trait is added
+	require.Len(t, gnome.Traits, 1)
+	assert.Equal(t, 1, gnome.Traits[0].Id)
+
+	net, err := gnome.Genesis(gnome.Id)
+	require.NoError(t, err)
+	assert.Equal(t, 11, net.NodeCount())
+	assert.Equal(t, 21, net.LinkCount())
+}
+
+func TestGenomeBuilder_Build_modular(t *testing.T) {
+	gnome, err := NewGenomeBuilder().
+		Trait(1, 0.1).
+		Trait(2, 0.2).
+		WithTrait(2).
+		Inputs(2).
+		Bias().
+		Outputs(1, math.LinearActivation).
+		Hidden(2, math.LinearActivation).
+		Node(7, network.HiddenNeuron, math.NullActivation).
+		Connect(1, 5, 1.5).
+		Connect(2, 6, 2.5).
+		Connect(7, 4, 3.5).
+		Module(math.MultiplyModuleActivation, []int{5, 6}, []int{7}).
+		Build()
+	require.NoError(t, err)
+
+	require.Len(t, gnome.Nodes, 7)
+	for _, node := range gnome.Nodes {
+		assert.Equal(t, 2, node.Trait.Id)
+	}
+	require.Len(t, gnome.Genes, 3)
+	require.Len(t, gnome.ControlGenes, 1)
+	cg := gnome.ControlGenes[0]
+	assert.EqualValues(t, 4, cg.InnovationNum)
+	assert.Equal(t, 8, cg.ControlNode.Id)
+	assert.Equal(t, math.MultiplyModuleActivation, cg.ControlNode.ActivationType)
+	assert.Len(t, cg.ioNodes, 3)
+
+	net, err := gnome.Genesis(gnome.Id)
+	require.NoError(t, err)
+	err = net.LoadSensors([]float64{2, 3})
+	require.NoError(t, err)
+	_, err = net.ForwardSteps(5)
+	require.NoError(t, err)
+	// (2 * 1.5) * (3 * 2.5) * 3.5
+	assert.InDelta(t, 78.75, net.ReadOutputs()[0], 1e-9)
+}
+
+func TestGenomeBuilder_Build_errors(t *testing.T) {
+	testCases := map[string]*GenomeBuilder{
+		"no sensors":          NewGenomeBuilder().Outputs(1, math.LinearActivation),
+		"no outputs":          NewGenomeBuilder().Inputs(1),
+		"no genes":            NewGenomeBuilder().Inputs(1).Outputs(1, math.LinearActivation),
+		"duplicate node":      NewGenomeBuilder().Inputs(2).Node(2, network.OutputNeuron, math.LinearActivation),
+		"wrong node ID":       NewGenomeBuilder().Node(0, network.InputNeuron, math.NullActivation),
+		"unknown input":       NewGenomeBuilder().Inputs(1).Outputs(1, math.LinearActivation).Connect(5, 2, 1),
+		"unknown output":      NewGenomeBuilder().Inputs(1).Outputs(1, math.LinearActivation).Connect(1, 5, 1),
+		"link into sensor":    NewGenomeBuilder().Inputs(2).Outputs(1, math.LinearActivation).Connect(1, 2, 1),
+		"duplicate link":      NewGenomeBuilder().Inputs(1).Outputs(1, math.LinearActivation).FullyConnect().Connect(1, 2, 1),
+		"duplicate trait":     NewGenomeBuilder().Trait(1).Trait(1),
+		"too many parameters": NewGenomeBuilder().Trait(1, make([]float64, neat.NumTraitParams+1)...),
+		"unknown trait":       NewGenomeBuilder().Trait(1).WithTrait(2),
+		"no traits to cycle":  NewGenomeBuilder().CycleTraits(),
+		"module no outputs":   NewGenomeBuilder().Inputs(1).Module(math.MultiplyModuleActivation, []int{1}, nil),
+		"module unknown node": NewGenomeBuilder().Inputs(1).Module(math.MultiplyModuleActivation, []int{1}, []int{3}),
+		"module into sensor":  NewGenomeBuilder().Inputs(2).Module(math.MultiplyModuleActivation, []int{1}, []int{2}),
+		"control node ID": NewGenomeBuilder().Inputs(1).Outputs(1, math.LinearActivation).
+			Module(math.MultiplyModuleActivation, []int{1}, []int{2}).Node(3, network.HiddenNeuron, math.LinearActivation),
+	}
+	for name, builder := range testCases {
+		t.Run(name, func(t *testing.T) {
+			gnome, err := builder.Build()
+			assert.Error(t, err)
+			assert.Nil(t, gnome)
+		})
+	}
+}
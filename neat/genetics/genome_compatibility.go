@@ -7,15 +7,16 @@ import (
 
 /* ******** COMPATIBILITY CHECKING METHODS * ********/
 
-// This function gives a measure of compatibility between two Genomes by computing a linear combination of three
+// Compatibility gives a measure of compatibility between two Genomes by computing a linear combination of three
 // characterizing variables of their compatibility. The three variables represent PERCENT DISJOINT GENES,
 // PERCENT EXCESS GENES, MUTATIONAL DIFFERENCE WITHIN MATCHING GENES. So the formula for compatibility
 // is:  disjoint_coeff * pdg + excess_coeff * peg + mutdiff_coeff * mdmg
-// The three coefficients are global system parameters.
+// The three coefficients are global system parameters. If aggregation_diff_coeff is set, the number of matching
+// neuron nodes with different aggregation functions multiplied by it is added to the compatibility.
 // The bigger returned value the less compatible the genomes.
 //
 // Fully compatible genomes has 0.0 returned.
-func (g *Genome) compatibility(og *Genome, opts *neat.Options) float64 {
+func (g *Genome) Compatibility(og *Genome, opts *neat.Options) float64 {
 	if opts.GenCompatMethod == neat.GenomeCompatibilityMethodLinear {
 		return g.compatLinear(og, opts)
 	} else {
@@ -74,7 +75,7 @@ func (g *Genome) compatLinear(og *Genome, opts *neat.Options) float64 {
 	comp := opts.DisjointCoeff*numDisjoint + opts.ExcessCoeff*numExcess +
 		opts.MutdiffCoeff*(mutDiffTotal/numMatching)
 
-	return comp
+	return comp + g.compatAggregation(og, opts)
 }
 
 // The faster version of genome compatibility checking. The compatibility check will start from the end of genome where
@@ -92,23 +93,24 @@ func (g *Genome) compatLinear(og *Genome, opts *neat.Options) float64 {
 // Fully compatible genomes has 0.0 returned.
 func (g *Genome) compatFast(og *Genome, opts *neat.Options) float64 {
 	list1Count, list2Count := len(g.Genes), len(og.Genes)
+	aggregationDiff := g.compatAggregation(og, opts)
 	// First test edge cases
 	if list1Count == 0 && list2Count == 0 {
 		// Both lists are empty! No disparities, therefore the genomes are compatible!
-		return 0.0
+		return aggregationDiff
 	}
 	if list1Count == 0 {
 		// All list2 genes are excess.
-		return float64(list2Count) * opts.ExcessCoeff
+		return float64(list2Count)*opts.ExcessCoeff + aggregationDiff
 	}
 
 	if list2Count == 0 {
 		// All list1 genes are excess.
-		return float64(list1Count) * opts.ExcessCoeff
+		return float64(list1Count)*opts.ExcessCoeff + aggregationDiff
 	}
 
 	excessGenesSwitch, numMatching := 0, 0
-	compatibility, mutDiff := 0.0, 0.0
+	compatibility, mutDiff := aggregationDiff, 0.0
 	list1Idx, list2Idx := list1Count-1, list2Count-1
 	gene1, gene2 := g.Genes[list1Idx], og.Genes[list2Idx]
 
@@ -189,3 +191,22 @@ func (g *Genome) compatFast(og *Genome, opts *neat.Options) float64 {
 	}
 	return compatibility
 }
+
+// Returns the aggregation functions difference between genomes as: aggregation_diff_coeff * number of neuron nodes
+// with the same ID but different aggregation functions. The difference is not calculated if aggregation_diff_coeff
+// is not set.
+func (g *Genome) compatAggregation(og *Genome, opts *neat.Options) float64 {
+	if opts.AggregationDiffCoeff <= 0 {
+		return 0.0
+	}
+	numDiff := 0
+	for _, node := range g.Nodes {
+		if !node.IsNeuron() {
+			continue
+		}
+		if oNode := og.NodeWithId(node.Id); oNode != nil && oNode.AggregationType != node.AggregationType {
+			numDiff++
+		}
+	}
+	return float64(numDiff) * opts.AggregationDiffCoeff
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/math"
 	"github.com/yaricom/goNEAT/v4/neat/network"
 	"testing"
 )
@@ -22,22 +23,22 @@ func TestGenome_Compatibility_Linear(t *testing.T) {
 	}
 
 	// Test fully compatible
-	comp := gnome1.compatibility(gnome2, &conf)
+	comp := gnome1.Compatibility(gnome2, &conf)
 	assert.Equal(t, 0.0, comp, "not fully compatible")
 
 	// Test incompatible
 	gnome2.Genes = append(gnome2.Genes, NewGene(1.0, network.NewNNode(1, network.InputNeuron),
 		network.NewNNode(1, network.OutputNeuron), false, 10, 1.0))
-	comp = gnome1.compatibility(gnome2, &conf)
+	comp = gnome1.Compatibility(gnome2, &conf)
 	assert.Equal(t, 0.5, comp)
 
 	gnome2.Genes = append(gnome2.Genes, NewGene(2.0, network.NewNNode(1, network.InputNeuron),
 		network.NewNNode(1, network.OutputNeuron), false, 5, 1.0))
-	comp = gnome1.compatibility(gnome2, &conf)
+	comp = gnome1.Compatibility(gnome2, &conf)
 	assert.Equal(t, 1.0, comp)
 
 	gnome2.Genes[1].MutationNum = 6.0
-	comp = gnome1.compatibility(gnome2, &conf)
+	comp = gnome1.Compatibility(gnome2, &conf)
 	assert.Equal(t, 2.0, comp)
 }
 
@@ -55,22 +56,22 @@ func TestGenome_Compatibility_Fast(t *testing.T) {
 	}
 
 	// Test fully compatible
-	comp := gnome1.compatibility(gnome2, &conf)
+	comp := gnome1.Compatibility(gnome2, &conf)
 	assert.Equal(t, 0.0, comp, "not fully compatible")
 
 	// Test incompatible
 	gnome2.Genes = append(gnome2.Genes, NewGene(1.0, network.NewNNode(1, network.InputNeuron),
 		network.NewNNode(1, network.OutputNeuron), false, 10, 1.0))
-	comp = gnome1.compatibility(gnome2, &conf)
+	comp = gnome1.Compatibility(gnome2, &conf)
 	assert.Equal(t, 0.5, comp)
 
 	gnome2.Genes = append(gnome2.Genes, NewGene(2.0, network.NewNNode(1, network.InputNeuron),
 		network.NewNNode(1, network.OutputNeuron), false, 5, 1.0))
-	comp = gnome1.compatibility(gnome2, &conf)
+	comp = gnome1.Compatibility(gnome2, &conf)
 	assert.Equal(t, 1.0, comp)
 
 	gnome2.Genes[1].MutationNum = 6.0
-	comp = gnome1.compatibility(gnome2, &conf)
+	comp = gnome1.Compatibility(gnome2, &conf)
 	assert.Equal(t, 2.0, comp)
 }
 
@@ -88,6 +89,35 @@ func TestGenome_Compatibility_Duplicate(t *testing.T) {
 	}
 
 	// Test fully compatible
-	comp := gnome1.compatibility(gnome2, &conf)
+	comp := gnome1.Compatibility(gnome2, &conf)
 	assert.Equal(t, 0.0, comp, "not fully compatible")
 }
+
+func TestGenome_Compatibility_Aggregation(t *testing.T) {
+	for _, method := range []neat.GenomeCompatibilityMethod{neat.GenomeCompatibilityMethodLinear, neat.GenomeCompatibilityMethodFast} {
+		gnome1 := buildTestGenome(1)
+		gnome2 := buildTestGenome(2)
+
+		// Configuration
+		conf := neat.Options{
+			DisjointCoeff:   0.5,
+			ExcessCoeff:     0.5,
+			MutdiffCoeff:    0.5,
+			GenCompatMethod: method,
+		}
+
+		// the different aggregation functions ignored when coefficient not set
+		gnome2.Nodes[3].AggregationType = math.MedianAggregation
+		comp := gnome1.Compatibility(gnome2, &conf)
+		assert.Equal(t, 0.0, comp, "not fully compatible, method: %s", method)
+
+		conf.AggregationDiffCoeff = 0.7
+		comp = gnome1.Compatibility(gnome2, &conf)
+		assert.Equal(t, 0.7, comp, "wrong compatibility, method: %s", method)
+
+		// the same aggregation functions are fully compatible
+		gnome1.Nodes[3].AggregationType = math.MedianAggregation
+		comp = gnome1.Compatibility(gnome2, &conf)
+		assert.Equal(t, 0.0, comp, "not fully compatible, method: %s", method)
+	}
+}
@@ -0,0 +1,102 @@
+package genetics
+
+import (
+	"fmt"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+)
+
+// validateConstraints checks that this genome satisfies the topology restrictions and complexity caps defined
+// by the options. Returns error describing the first violation found.
+func (g *Genome) validateConstraints(opts *neat.Options) error {
+	if opts.MaxNodes > 0 && len(g.Nodes) > opts.MaxNodes {
+		return fmt.Errorf("genome [%d] has %d nodes, which exceeds the maximal nodes number: %d",
+			g.Id, len(g.Nodes), opts.MaxNodes)
+	}
+	if opts.MaxLinks > 0 && len(g.Genes) > opts.MaxLinks {
+		return fmt.Errorf("genome [%d] has %d links, which exceeds the maximal links number: %d",
+			g.Id, len(g.Genes), opts.MaxLinks)
+	}
+	if !opts.DisallowRecurrentLinks && !opts.DisallowSelfLinks {
+		return nil
+	}
+	for _, gene := range g.Genes {
+		// the link is forbidden if it is a self-loop or it closes a loop with other genes
+		if !isLinkAllowed(gene.Link.InNode.Id, gene.Link.OutNode.Id, gene.Link.IsRecurrent, g.Genes, opts) {
+			return fmt.Errorf("genome [%d] has forbidden recurrent link: %s", g.Id, gene)
+		}
+	}
+	return nil
+}
+
+// canAddLinks checks whether the specified number of links can be added to this genome without exceeding
+// the maximal links number
+func (g *Genome) canAddLinks(count int, opts *neat.Options) bool {
+	return opts.MaxLinks <= 0 || len(g.Genes)+count <= opts.MaxLinks
+}
+
+// canAddNodes checks whether the specified number of nodes can be added to this genome without exceeding
+// the maximal nodes number
+func (g *Genome) canAddNodes(count int, opts *neat.Options) bool {
+	return opts.MaxNodes <= 0 || len(g.Nodes)+count <= opts.MaxNodes
+}
+
+// isGeneAllowed checks whether the gene chosen during crossover can be added to the offspring with provided genes and
+// nodes without violating topology restrictions and complexity caps defined by the options.
+func isGeneAllowed(gene *Gene, genes []*Gene, nodes []*network.NNode, opts *neat.Options) bool {
+	if opts.MaxLinks > 0 && len(genes) >= opts.MaxLinks {
+		return false
+	}
+	if opts.MaxNodes > 0 {
+		newNodes := 0
+		if NodeWithId(gene.Link.InNode.Id, nodes) == nil {
+			newNodes++
+		}
+		if gene.Link.InNode.Id != gene.Link.OutNode.Id && NodeWithId(gene.Link.OutNode.Id, nodes) == nil {
+			newNodes++
+		}
+		if len(nodes)+newNodes > opts.MaxNodes {
+			return false
+		}
+	}
+	return isLinkAllowed(gene.Link.InNode.Id, gene.Link.OutNode.Id, gene.Link.IsRecurrent, genes, opts)
+}
+
+// isLinkAllowed checks whether the link between given nodes is allowed to be added to the provided genes according
+// to the recurrence restrictions defined by the options. The link is considered recurrent if it is marked so, or if
+// it closes a loop with the provided genes. Both enabled and disabled genes are considered, because disabled genes
+// can be re-enabled.
+func isLinkAllowed(inNodeId, outNodeId int, recurrent bool, genes []*Gene, opts *neat.Options) bool {
+	if inNodeId == outNodeId {
+		return !opts.DisallowSelfLinks && !opts.DisallowRecurrentLinks
+	}
+	if !opts.DisallowRecurrentLinks {
+		return true
+	}
+	return !recurrent && !pathExists(outNodeId, inNodeId, genes)
+}
+
+// pathExists checks whether there is a directed path between nodes with given IDs formed by provided genes
+func pathExists(fromNodeId, toNodeId int, genes []*Gene) bool {
+	outgoing := make(map[int][]int)
+	for _, gene := range genes {
+		in := gene.Link.InNode.Id
+		outgoing[in] = append(outgoing[in], gene.Link.OutNode.Id)
+	}
+	visited := map[int]bool{fromNodeId: true}
+	stack := []int{fromNodeId}
+	for len(stack) > 0 {
+		nodeId := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if nodeId == toNodeId {
+			return true
+		}
+		for _, next := range outgoing[nodeId] {
+			if !visited[next] {
+				visited[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+	return false
+}
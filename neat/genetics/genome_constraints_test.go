@@ -0,0 +1,196 @@
+package genetics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"math/rand"
+	"testing"
+)
+
+// builds test genome with hidden node 5 connected as: 1 -> 5 -> 4
+func buildTestGenomeWithHidden(id int) *Genome {
+	gnome := buildTestGenome(id)
+	hidden := network.NewNNode(5, network.HiddenNeuron)
+	hidden.ActivationType = math.SigmoidSteepenedActivation
+	gnome.addNode(hidden)
+	gnome.Genes = append(gnome.Genes,
+		NewConnectionGene(network.NewLinkWithTrait(gnome.Traits[0], 1.0, gnome.Nodes[0], hidden, false), 4, 0, true),
+		NewConnectionGene(network.NewLinkWithTrait(gnome.Traits[0], 1.0, hidden, gnome.Nodes[3], false), 5, 0, true),
+	)
+	return gnome
+}
+
+func TestGenome_validateConstraints(t *testing.T) {
+	gnome := buildTestGenomeWithHidden(1)
+	opts := &neat.Options{}
+	assert.NoError(t, gnome.validateConstraints(opts))
+
+	opts.MaxNodes = 4
+	assert.Error(t, gnome.validateConstraints(opts))
+
+	opts = &neat.Options{MaxLinks: 4}
+	assert.Error(t, gnome.validateConstraints(opts))
+
+	opts = &neat.Options{MaxNodes: 5, MaxLinks: 5, DisallowRecurrentLinks: true, DisallowSelfLinks: true}
+	assert.NoError(t, gnome.validateConstraints(opts))
+
+	// add self-loop
+	selfGene := NewConnectionGene(network.NewLinkWithTrait(gnome.Traits[0], 1.0, gnome.Nodes[4], gnome.Nodes[4], true), 6, 0, true)
+	gnome.Genes = append(gnome.Genes, selfGene)
+	opts = &neat.Options{DisallowSelfLinks: true}
+	assert.Error(t, gnome.validateConstraints(opts))
+	opts = &neat.Options{DisallowRecurrentLinks: true}
+	assert.Error(t, gnome.validateConstraints(opts))
+
+	// add loop: 4 -> 5, which closes 5 -> 4
+	gnome = buildTestGenomeWithHidden(1)
+	loopGene := NewConnectionGene(network.NewLinkWithTrait(gnome.Traits[0], 1.0, gnome.Nodes[3], gnome.Nodes[4], false), 6, 0, false)
+	gnome.Genes = append(gnome.Genes, loopGene)
+	opts = &neat.Options{DisallowSelfLinks: true}
+	assert.NoError(t, gnome.validateConstraints(opts))
+	opts = &neat.Options{DisallowRecurrentLinks: true}
+	assert.Error(t, gnome.validateConstraints(opts), "disabled genes must be considered")
+}
+
+func TestPathExists(t *testing.T) {
+	gnome := buildTestGenomeWithHidden(1)
+	assert.True(t, pathExists(1, 4, gnome.Genes))
+	assert.True(t, pathExists(1, 5, gnome.Genes))
+	assert.True(t, pathExists(5, 4, gnome.Genes))
+	assert.False(t, pathExists(4, 1, gnome.Genes))
+	assert.False(t, pathExists(2, 5, gnome.Genes))
+}
+
+func TestGenome_mutateAddLink_disallowRecurrent(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	opts := &neat.Options{
+		RecurOnlyProb:          1.0,
+		NewLinkTries:           10,
+		DisallowRecurrentLinks: true,
+	}
+	_, err := gnome.Genesis(1)
+	require.NoError(t, err, "genesis failed")
+
+	pop := newPopulation()
+	// the only possible link is a self-loop of output node
+	res, err := gnome.mutateAddLink(pop, 1, opts)
+	require.NoError(t, err, "failed to add link")
+	assert.False(t, res, "no link expected")
+	assert.Len(t, gnome.Genes, 3)
+
+	// self-loop disallowed only
+	opts = &neat.Options{
+		RecurOnlyProb:     1.0,
+		NewLinkTries:      10,
+		DisallowSelfLinks: true,
+	}
+	res, err = gnome.mutateAddLink(pop, 1, opts)
+	require.NoError(t, err, "failed to add link")
+	assert.False(t, res, "no link expected")
+}
+
+func TestGenome_mutateAddLink_maxLinks(t *testing.T) {
+	rand.Seed(42)
+	gnome := buildTestGenome(1)
+	opts := &neat.Options{
+		RecurOnlyProb: 1.0,
+		NewLinkTries:  10,
+		MaxLinks:      3,
+	}
+	_, err := gnome.Genesis(1)
+	require.NoError(t, err, "genesis failed")
+
+	res, err := gnome.mutateAddLink(newPopulation(), 1, opts)
+	require.NoError(t, err, "failed to add link")
+	assert.False(t, res, "no link expected")
+	assert.Len(t, gnome.Genes, 3)
+}
+
+func TestGenome_mutateAddNode_caps(t *testing.T) {
+	opts := &neat.Options{
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+		MaxNodes:           4,
+	}
+	gnome := buildTestGenome(1)
+	pop := newPopulation()
+	res, err := gnome.mutateAddNode(pop, pop, opts)
+	require.NoError(t, err, "failed to mutate")
+	assert.False(t, res, "no node expected")
+	assert.Len(t, gnome.Nodes, 4)
+	for _, gene := range gnome.Genes {
+		assert.True(t, gene.IsEnabled, "no genes should be disabled")
+	}
+
+	opts.MaxNodes = 5
+	opts.MaxLinks = 4
+	res, err = gnome.mutateAddNode(pop, pop, opts)
+	require.NoError(t, err, "failed to mutate")
+	assert.False(t, res, "no node expected")
+
+	opts.MaxLinks = 5
+	res, err = gnome.mutateAddNode(pop, pop, opts)
+	require.NoError(t, err, "failed to mutate")
+	assert.True(t, res, "node expected")
+	assert.Len(t, gnome.Nodes, 5)
+	assert.Len(t, gnome.Genes, 5)
+}
+
+func TestGenome_mutateConnectSensors_maxLinks(t *testing.T) {
+	gnome := buildTestGenome(1)
+	node := network.NewNNode(5, network.InputNeuron)
+	gnome.addNode(node)
+	_, err := gnome.Genesis(1)
+	require.NoError(t, err, "genesis failed")
+
+	opts := &neat.Options{MaxLinks: 3}
+	res, err := gnome.mutateConnectSensors(newPopulation(), opts)
+	require.NoError(t, err, "failed to mutate")
+	assert.False(t, res, "no link expected")
+	assert.Len(t, gnome.Genes, 3)
+}
+
+func TestGenome_mateMultipoint_constraints(t *testing.T) {
+	rand.Seed(42)
+	gnome1 := buildTestGenomeWithHidden(1)
+	gnome2 := buildTestGenomeWithHidden(2)
+	// the loop gene in the second parent
+	loopGene := NewConnectionGene(network.NewLinkWithTrait(gnome2.Traits[0], 1.0, gnome2.Nodes[3], gnome2.Nodes[4], false), 6, 0, true)
+	gnome2.Genes = append(gnome2.Genes, loopGene)
+
+	opts := &neat.Options{DisallowRecurrentLinks: true}
+	child, err := gnome1.mateMultipoint(gnome2, 3, 1.0, 2.0, opts)
+	require.NoError(t, err, "failed to mate")
+	assert.Len(t, child.Genes, 5)
+	assert.NoError(t, child.validateConstraints(opts))
+
+	opts = &neat.Options{MaxLinks: 3, MaxNodes: 4}
+	child, err = gnome1.mateMultipoint(gnome2, 3, 1.0, 2.0, opts)
+	require.NoError(t, err, "failed to mate")
+	assert.NoError(t, child.validateConstraints(opts))
+
+	for _, mate := range []func() (*Genome, error){
+		func() (*Genome, error) { return gnome1.mateMultipointAvg(gnome2, 3, 1.0, 2.0, opts) },
+		func() (*Genome, error) { return gnome1.mateSinglePoint(gnome2, 3, opts) },
+	} {
+		child, err = mate()
+		require.NoError(t, err, "failed to mate")
+		assert.NoError(t, child.validateConstraints(opts))
+	}
+}
+
+func TestNewPopulation_invalidSeedGenome(t *testing.T) {
+	gnome := buildTestGenomeWithHidden(1)
+	opts := &neat.Options{
+		PopSize:         10,
+		CompatThreshold: 0.5,
+		MaxNodes:        4,
+	}
+	pop, err := NewPopulation(gnome, opts)
+	assert.Error(t, err)
+	assert.Nil(t, pop)
+}
@@ -0,0 +1,114 @@
+package genetics
+
+import (
+	"encoding/binary"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"hash"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// CanonicalHash returns the hash of this genome which is the same for all genomes encoding the same network. The hash
+// is based on the nodes with their activation and aggregation functions and traits, and the enabled connection and
+// control genes with their weights and traits. The disabled genes, genome ID, and the order of genes are not taken into
+// account. The link weights and trait parameters are rounded to the given number of decimal places before hashing, thus
+// genomes with weights differing less than the precision will have the same hash.
+func (g *Genome) CanonicalHash(precision int) uint64 {
+	h := canonicalHasher{Hash64: fnv.New64a(), scale: math.Pow10(precision)}
+
+	nodes := make([]*network.NNode, len(g.Nodes))
+	copy(nodes, g.Nodes)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Id < nodes[j].Id
+	})
+	for _, n := range nodes {
+		h.writeInt(int64(n.Id))
+		h.writeInt(int64(n.NeuronType))
+		h.writeInt(int64(n.ActivationType))
+		h.writeInt(int64(n.AggregationType))
+		h.writeTrait(n.Trait)
+	}
+
+	genes := make([]*Gene, 0, len(g.Genes))
+	for _, gene := range g.Genes {
+		if gene.IsEnabled {
+			genes = append(genes, gene)
+		}
+	}
+	sort.Slice(genes, func(i, j int) bool {
+		li, lj := genes[i].Link, genes[j].Link
+		if li.InNode.Id != lj.InNode.Id {
+			return li.InNode.Id < lj.InNode.Id
+		}
+		if li.OutNode.Id != lj.OutNode.Id {
+			return li.OutNode.Id < lj.OutNode.Id
+		}
+		return !li.IsRecurrent && lj.IsRecurrent
+	})
+	for _, gene := range genes {
+		h.writeInt(int64(gene.Link.InNode.Id))
+		h.writeInt(int64(gene.Link.OutNode.Id))
+		h.writeBool(gene.Link.IsRecurrent)
+		h.writeFloat(gene.Link.ConnectionWeight)
+		h.writeTrait(gene.Link.Trait)
+	}
+
+	for _, cg := range g.ControlGenes {
+		if !cg.IsEnabled {
+			continue
+		}
+		h.writeInt(int64(cg.ControlNode.Id))
+		h.writeInt(int64(cg.ControlNode.ActivationType))
+		for _, l := range cg.ControlNode.Incoming {
+			h.writeInt(int64(l.InNode.Id))
+		}
+		for _, l := range cg.ControlNode.Outgoing {
+			h.writeInt(int64(l.OutNode.Id))
+		}
+	}
+	return h.Sum64()
+}
+
+// canonicalHasher is to write values into the hash in platform independent way
+type canonicalHasher struct {
+	hash.Hash64
+	// The scale to round floating point values with
+	scale float64
+	buf   [8]byte
+}
+
+func (h *canonicalHasher) writeInt(v int64) {
+	binary.LittleEndian.PutUint64(h.buf[:], uint64(v))
+	_, _ = h.Write(h.buf[:])
+}
+
+func (h *canonicalHasher) writeBool(v bool) {
+	if v {
+		h.writeInt(1)
+	} else {
+		h.writeInt(0)
+	}
+}
+
+func (h *canonicalHasher) writeFloat(v float64) {
+	rounded := math.Round(v * h.scale)
+	if rounded == 0 {
+		// to avoid distinction between positive and negative zero
+		rounded = 0
+	}
+	binary.LittleEndian.PutUint64(h.buf[:], math.Float64bits(rounded))
+	_, _ = h.Write(h.buf[:])
+}
+
+func (h *canonicalHasher) writeTrait(t *neat.Trait) {
+	if t == nil {
+		h.writeInt(-1)
+		return
+	}
+	h.writeInt(int64(len(t.Params)))
+	for _, p := range t.Params {
+		h.writeFloat(p)
+	}
+}
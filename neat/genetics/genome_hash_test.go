@@ -0,0 +1,87 @@
+package genetics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"testing"
+)
+
+func TestGenome_CanonicalHash(t *testing.T) {
+	gnome := buildTestGenome(1)
+	hash := gnome.CanonicalHash(6)
+
+	// the same genome with different ID
+	other := buildTestGenome(2)
+	assert.Equal(t, hash, other.CanonicalHash(6))
+
+	// duplicate
+	dup, err := gnome.duplicate(3)
+	require.NoError(t, err)
+	assert.Equal(t, hash, dup.CanonicalHash(6))
+
+	// the order of genes is not important
+	other.Genes[0], other.Genes[2] = other.Genes[2], other.Genes[0]
+	assert.Equal(t, hash, other.CanonicalHash(6))
+
+	// the weight difference below precision
+	other.Genes[0].Link.ConnectionWeight += 1e-8
+	assert.Equal(t, hash, other.CanonicalHash(6))
+	assert.NotEqual(t, hash, other.CanonicalHash(10))
+}
+
+func TestGenome_CanonicalHash_differences(t *testing.T) {
+	hash := buildTestGenome(1).CanonicalHash(6)
+
+	testCases := map[string]func(g *Genome){
+		"weight": func(g *Genome) {
+			g.Genes[1].Link.ConnectionWeight += 0.001
+		},
+		"disabled gene": func(g *Genome) {
+			g.Genes[1].IsEnabled = false
+		},
+		"activation": func(g *Genome) {
+			g.Nodes[3].ActivationType = math.TanhActivation
+		},
+		"aggregation": func(g *Genome) {
+			g.Nodes[3].AggregationType = math.ProductAggregation
+		},
+		"gene trait": func(g *Genome) {
+			g.Genes[0].Link.Trait = g.Traits[1]
+		},
+		"node trait": func(g *Genome) {
+			g.Nodes[3].Trait = g.Traits[1]
+		},
+		"recurrent": func(g *Genome) {
+			g.Genes[0].Link.IsRecurrent = true
+		},
+	}
+	for name, modify := range testCases {
+		t.Run(name, func(t *testing.T) {
+			gnome := buildTestGenome(1)
+			modify(gnome)
+			assert.NotEqual(t, hash, gnome.CanonicalHash(6))
+		})
+	}
+}
+
+func TestGenome_CanonicalHash_disabledGenes(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Genes[1].IsEnabled = false
+	hash := gnome.CanonicalHash(6)
+
+	// weight of disabled gene is not important
+	gnome.Genes[1].Link.ConnectionWeight = 100
+	assert.Equal(t, hash, gnome.CanonicalHash(6))
+}
+
+func TestGenome_CanonicalHash_modular(t *testing.T) {
+	gnome := buildTestModularGenome(1)
+	hash := gnome.CanonicalHash(6)
+	dup, err := gnome.duplicate(2)
+	require.NoError(t, err)
+	assert.Equal(t, hash, dup.CanonicalHash(6))
+
+	dup.ControlGenes[0].ControlNode.ActivationType = math.MaxModuleActivation
+	assert.NotEqual(t, hash, dup.CanonicalHash(6))
+}
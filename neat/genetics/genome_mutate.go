@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/yaricom/goNEAT/v4/neat"
-	"github.com/yaricom/goNEAT/v4/neat/math"
 	"github.com/yaricom/goNEAT/v4/neat/network"
 	"math/rand"
 )
@@ -18,7 +17,7 @@ import (
 // 	(1) You can start minimally even in problems with many inputs and
 // 	(2) you don't need to know a priori what the important features of the domain are.
 // If all sensors already connected than do nothing.
-func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.Options) (bool, error) {
+func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, opts *neat.Options) (bool, error) {
 
 	if len(g.Genes) == 0 {
 		return false, errors.New("genome has no genes")
@@ -64,6 +63,10 @@ func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.O
 	// add new links to chosen sensor, avoiding redundancy
 	linkAdded := false
 	for _, output := range outputs {
+		if !g.canAddLinks(1, opts) {
+			// the maximal number of links reached
+			break
+		}
 		found := false
 		for _, gene := range g.Genes {
 			if gene.Link.InNode == sensor && gene.Link.OutNode == output {
@@ -75,19 +78,10 @@ func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.O
 		if !found {
 			var gene *Gene
 			// Check to see if this innovation already occurred in the population
-			innovationFound := false
-			for _, inn := range innovations.Innovations() {
-				if inn.innovationType == newLinkInnType &&
-					inn.InNodeId == sensor.Id &&
-					inn.OutNodeId == output.Id &&
-					!inn.IsRecurrent {
-
-					gene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight,
-						sensor, output, false, inn.InnovationNum, 0)
-
-					innovationFound = true
-					break
-				}
+			inn, innovationFound := findLinkInnovation(innovations, sensor.Id, output.Id, false)
+			if innovationFound {
+				gene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight,
+					sensor, output, false, inn.InnovationNum, 0)
 			}
 
 			// The innovation is totally novel
@@ -95,7 +89,7 @@ func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.O
 				// Choose a random trait
 				traitNum := rand.Intn(len(g.Traits))
 				// Choose the new weight
-				newWeight := float64(math.RandSign()) * rand.Float64() * 10.0
+				newWeight := opts.RandomInitialWeight(g.fanIn(output.Id)+1, 10.0)
 				// read next innovation id
 				nextInnovId := innovations.NextInnovationNumber()
 
@@ -139,12 +133,16 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, generation int,
 	} else if len(g.Nodes) == 0 {
 		return false, errors.New("genome has no nodes to be connected by new link")
 	}
+	if !g.canAddLinks(1, opts) {
+		// the maximal number of links reached
+		return false, nil
+	}
 
 	nodesLen := len(g.Nodes)
 
 	// Decide whether to make link recurrent
 	doRecur := false
-	if rand.Float64() < opts.RecurOnlyProb {
+	if rand.Float64() < opts.RecurOnlyProb && !opts.DisallowRecurrentLinks {
 		doRecur = true
 	}
 
@@ -173,7 +171,7 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, generation int,
 			if rand.Float64() > 0.5 {
 				loopRecur = true
 			}
-			if loopRecur {
+			if loopRecur && !opts.DisallowSelfLinks {
 				nodeNum1 = firstNonSensor + rand.Intn(nodesLen-firstNonSensor) // only NON SENSOR
 				nodeNum2 = nodeNum1
 			} else {
@@ -228,9 +226,11 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, generation int,
 				}
 			}
 
-			// Make sure it finds the right kind of link (recurrent or not)
+			// Make sure it finds the right kind of link (recurrent or not) and that it is allowed by options
 			if (!recurFlag && doRecur) || (recurFlag && !doRecur) {
 				tryCount++
+			} else if !isLinkAllowed(node1.Id, node2.Id, doRecur, g.Genes, opts) {
+				tryCount++
 			} else {
 				// The open link found
 				tryCount = opts.NewLinkTries
@@ -245,27 +245,17 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, generation int,
 	if node1 != nil && node2 != nil && found {
 		var gene *Gene
 		// Check to see if this innovation already occurred in the population
-		innovationFound := false
-		for _, inn := range innovations.Innovations() {
-			// match the innovation in the innovations list
-			if inn.innovationType == newLinkInnType &&
-				inn.InNodeId == node1.Id &&
-				inn.OutNodeId == node2.Id &&
-				inn.IsRecurrent == doRecur {
-
-				// Create new gene
-				gene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight, node1, node2, doRecur, inn.InnovationNum, 0)
-
-				innovationFound = true
-				break
-			}
+		inn, innovationFound := findLinkInnovation(innovations, node1.Id, node2.Id, doRecur)
+		if innovationFound {
+			// Create new gene
+			gene = NewGeneWithTrait(g.Traits[inn.NewTraitNum], inn.NewWeight, node1, node2, doRecur, inn.InnovationNum, 0)
 		}
 		// The innovation is totally novel
 		if !innovationFound {
 			// Choose a random trait
 			traitNum := rand.Intn(len(g.Traits))
 			// Choose the new weight
-			newWeight := float64(math.RandSign()) * rand.Float64() * 10.0
+			newWeight := opts.RandomInitialWeight(g.fanIn(node2.Id)+1, 10.0)
 			// read next innovation id
 			nextInnovId := innovations.NextInnovationNumber()
 
@@ -311,6 +301,10 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 	if len(g.Genes) == 0 {
 		return false, nil // it's possible to have such a network without any link
 	}
+	if !g.canAddNodes(1, opts) || !g.canAddLinks(2, opts) {
+		// the complexity caps reached
+		return false, nil
+	}
 
 	// First, find a random gene already in the genome
 	found := false
@@ -363,33 +357,27 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 	var node *network.NNode
 
 	// Check to see if this innovation already occurred in the population
-	innovationFound := false
-	for _, inn := range innovations.Innovations() {
-		/* We check to see if an innovation already occurred that was:
-			-A new node
-			-Stuck between the same nodes as were chosen for this mutation
-			-Splitting the same gene as chosen for this mutation
-		If so, we know this mutation is not a novel innovation in this generation,
-		so we make it match the original, identical mutation which occurred
-		elsewhere in the population by coincidence */
-		if inn.innovationType == newNodeInnType &&
-			inn.InNodeId == inNode.Id &&
-			inn.OutNodeId == outNode.Id &&
-			inn.OldInnovNum == gene.InnovationNum {
-
-			// Create the new NNode
-			node = network.NewNNode(inn.NewNodeId, network.HiddenNeuron)
-			// By convention, it will point to the first trait
-			// Note: In future may want to change this
-			node.Trait = g.Traits[0]
-
-			// Create the new Genes
-			gene1 = NewGeneWithTrait(trait, 1.0, inNode, node, link.IsRecurrent, inn.InnovationNum, 0)
-			gene2 = NewGeneWithTrait(trait, oldWeight, node, outNode, false, inn.InnovationNum2, 0)
-
-			innovationFound = true
-			break
-		}
+	/* We check to see if an innovation already occurred that was:
+		-A new node
+		-Stuck between the same nodes as were chosen for this mutation
+		-Splitting the same gene as chosen for this mutation
+	If so, we know this mutation is not a novel innovation in this generation,
+	so we make it match the original, identical mutation which occurred
+	elsewhere in the population by coincidence */
+	inn, innovationFound := findNodeInnovation(innovations, inNode.Id, outNode.Id, gene.InnovationNum)
+	if innovationFound {
+		// Create the new NNode
+		node = network.NewNNode(inn.NewNodeId, network.HiddenNeuron)
+		// By convention, it will point to the first trait
+		// Note: In future may want to change this
+		node.Trait = g.Traits[0]
+		// Use the same functions as the node created by original mutation
+		node.ActivationType = inn.NewNodeActivation
+		node.AggregationType = inn.NewNodeAggregation
+
+		// Create the new Genes
+		gene1 = NewGeneWithTrait(trait, 1.0, inNode, node, link.IsRecurrent, inn.InnovationNum, 0)
+		gene2 = NewGeneWithTrait(trait, oldWeight, node, outNode, false, inn.InnovationNum2, 0)
 	}
 	// The innovation is totally novel
 	if !innovationFound {
@@ -406,6 +394,12 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 		} else {
 			node.ActivationType = activationType
 		}
+		// Set node aggregation function as random from a list of types registered with opts
+		if aggregationType, err := opts.RandomNodeAggregationType(); err != nil {
+			return false, err
+		} else {
+			node.AggregationType = aggregationType
+		}
 
 		// get the next innovation id for gene 1
 		gene1Innovation := innovations.NextInnovationNumber()
@@ -419,6 +413,8 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 
 		// Store innovation
 		innovation := NewInnovationForNode(inNode.Id, outNode.Id, gene1Innovation, gene2Innovation, node.Id, gene.InnovationNum)
+		innovation.NewNodeActivation = node.ActivationType
+		innovation.NewNodeAggregation = node.AggregationType
 		innovations.StoreInnovation(*innovation)
 	} else if node != nil && g.haveNode(node.Id) {
 		// The same add node innovation occurred in the same genome (parent) - just skip.
@@ -445,8 +441,9 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 }
 
 // Adds Gaussian noise to link weights either GAUSSIAN or COLD_GAUSSIAN (from zero).
-// The COLD_GAUSSIAN means ALL connection weights will be given completely new values
-func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType) (bool, error) {
+// The COLD_GAUSSIAN means ALL connection weights will be given completely new values.
+// The noise distribution, the replacement ratio and the weights range are defined by provided options.
+func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType, opts *neat.Options) (bool, error) {
 	if len(g.Genes) == 0 {
 		return false, errors.New("genome has no genes")
 	}
@@ -462,6 +459,17 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType
 	endPart := genesCount * 0.8
 	var gaussPoint, coldGaussPoint float64
 
+	// The replaced weights are drawn from the initial weights distribution which may depend on the fan-in of nodes
+	fanIns := make(map[int]int)
+	for _, gene := range g.Genes {
+		if gene.IsEnabled {
+			fanIns[gene.Link.OutNode.Id]++
+		}
+	}
+	replacement := func(gene *Gene) float64 {
+		return opts.RandomInitialWeight(fanIns[gene.Link.OutNode.Id], power)
+	}
+
 	for _, gene := range g.Genes {
 		// The following if determines the probabilities of doing cold gaussian
 		// mutation, meaning the probability of replacing a link weight with
@@ -486,17 +494,26 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType
 			}
 		}
 
-		random := float64(math.RandSign()) * rand.Float64() * power
 		if mutationType == gaussianMutator {
 			randChoice := rand.Float64()
-			if randChoice > gaussPoint {
-				gene.Link.ConnectionWeight += random
+			if opts.WeightMutReplaceRate > 0 {
+				// Use explicit ratio of replaced weights among mutated ones
+				if randChoice < rate {
+					if rand.Float64() < opts.WeightMutReplaceRate {
+						gene.Link.ConnectionWeight = replacement(gene)
+					} else {
+						gene.Link.ConnectionWeight += opts.RandomWeightPerturbation(power)
+					}
+				}
+			} else if randChoice > gaussPoint {
+				gene.Link.ConnectionWeight += opts.RandomWeightPerturbation(power)
 			} else if randChoice > coldGaussPoint {
-				gene.Link.ConnectionWeight = random
+				gene.Link.ConnectionWeight = replacement(gene)
 			}
 		} else if mutationType == goldGaussianMutator {
-			gene.Link.ConnectionWeight = random
+			gene.Link.ConnectionWeight = replacement(gene)
 		}
+		gene.Link.ConnectionWeight = opts.ClampWeight(gene.Link.ConnectionWeight)
 
 		// Record the innovation
 		gene.MutationNum = gene.Link.ConnectionWeight
@@ -558,6 +575,32 @@ func (g *Genome) mutateNodeTrait(times int) (bool, error) {
 	return true, nil
 }
 
+// This chooses a random neuron node and changes its aggregation function to the random one among registered with
+// options specified number of times
+func (g *Genome) mutateNodeAggregation(times int, opts *neat.Options) (bool, error) {
+	neurons := make([]*network.NNode, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		if node.IsNeuron() {
+			neurons = append(neurons, node)
+		}
+	}
+	if len(neurons) == 0 {
+		return false, nil
+	}
+	for loop := 0; loop < times; loop++ {
+		// Choose a random neuron node
+		node := neurons[rand.Intn(len(neurons))]
+
+		// set the node aggregation function
+		if aggregationType, err := opts.RandomNodeAggregationType(); err != nil {
+			return false, err
+		} else {
+			node.AggregationType = aggregationType
+		}
+	}
+	return true, nil
+}
+
 // Toggle genes from enable ON to enable OFF or vice versa. Do it specified number of times.
 func (g *Genome) mutateToggleEnable(times int) (bool, error) {
 	if len(g.Genes) == 0 {
@@ -616,9 +659,14 @@ func (g *Genome) mutateAllNonstructural(context *neat.Options) (bool, error) {
 		res, err = g.mutateNodeTrait(1)
 	}
 
+	if err == nil && context.MutateNodeAggregationProb > 0 && rand.Float64() < context.MutateNodeAggregationProb {
+		// mutate node aggregation
+		res, err = g.mutateNodeAggregation(1, context)
+	}
+
 	if err == nil && rand.Float64() < context.MutateLinkWeightsProb {
 		// mutate link weight
-		res, err = g.mutateLinkWeights(context.WeightMutPower, 1.0, gaussianMutator)
+		res, err = g.mutateLinkWeights(context.WeightMutPower, 1.0, gaussianMutator, context)
 	}
 
 	if err == nil && rand.Float64() < context.MutateToggleEnableProb {
@@ -34,7 +34,7 @@ func TestGenome_mutateAddLink(t *testing.T) {
 	require.True(t, res, "New link not added")
 
 	// one gene was added innovNum = 3 + 1
-	assert.EqualValues(t, 4, pop.nextInnovNum, "wrong next innovation number of the population")
+	assert.EqualValues(t, 4, pop.innovations.nextInnovNum, "wrong next innovation number of the population")
 	assert.Len(t, pop.Innovations(), 1, "wrong number of innovations in population")
 	assert.Len(t, gnome1.Genes, 4, "No new gene was added")
 	gene := gnome1.Genes[3]
@@ -57,7 +57,7 @@ func TestGenome_mutateAddLink(t *testing.T) {
 	require.True(t, res, "New link not added")
 
 	// one gene was added innovNum = 4 + 1
-	assert.EqualValues(t, 5, pop.nextInnovNum, "wrong next innovation number of the population")
+	assert.EqualValues(t, 5, pop.innovations.nextInnovNum, "wrong next innovation number of the population")
 	assert.Len(t, pop.Innovations(), 2, "wrong number of innovations in population")
 	assert.Len(t, gnome1.Genes, 5, "No new gene was added")
 
@@ -103,7 +103,7 @@ func TestGenome_mutateConnectSensors(t *testing.T) {
 	assert.Len(t, gnome1.Genes, 4, "wrong number of genome genes")
 	assert.Len(t, pop.Innovations(), 1, "wrong number of innovations")
 	// one gene was added, expecting innovation + 1 (3+1)
-	assert.EqualValues(t, 4, pop.nextInnovNum, "wrong innovation in population")
+	assert.EqualValues(t, 4, pop.innovations.nextInnovNum, "wrong innovation in population")
 }
 
 func TestGenome_mutateAddNode(t *testing.T) {
@@ -128,7 +128,7 @@ func TestGenome_mutateAddNode(t *testing.T) {
 	require.True(t, res, "mutation failed")
 
 	// two genes was added, expecting innovation + 2 (3+2)
-	assert.EqualValues(t, 5, pop.nextInnovNum, "wrong next innovation number set for population")
+	assert.EqualValues(t, 5, pop.innovations.nextInnovNum, "wrong next innovation number set for population")
 	assert.Len(t, pop.Innovations(), 1, "wrong number of innovations")
 	assert.Len(t, gnome1.Genes, 5, "wrong number of genes")
 	require.Len(t, gnome1.Nodes, 5, "wrong number of nodes")
@@ -138,10 +138,60 @@ func TestGenome_mutateAddNode(t *testing.T) {
 	assert.Equal(t, math.SigmoidSteepenedActivation, addedNode.ActivationType, "wrong activation type")
 }
 
+func TestGenome_mutateAddNode_innovationFound(t *testing.T) {
+	rand.Seed(42)
+	context := &neat.Options{
+		NodeActivators:      []math.NodeActivationType{math.LinearActivation},
+		NodeActivatorsProb:  []float64{1.0},
+		NodeAggregators:     []math.NodeAggregationType{math.MaxAggregation},
+		NodeAggregatorsProb: []float64{1.0},
+	}
+	context.PopSize = 1
+	pop := newPopulation()
+	err := pop.spawn(buildTestGenome(1), context)
+	require.NoError(t, err, "failed to spawn population")
+
+	// the novel innovation stores functions of the new node
+	gnome1 := buildTestGenome(1)
+	for _, gn := range gnome1.Genes[1:] {
+		gn.IsEnabled = false // force splitting of the first gene
+	}
+	// the gene to split is chosen randomly, repeat until chosen
+	res := false
+	for i := 0; i < 100 && !res; i++ {
+		res, err = gnome1.mutateAddNode(pop, pop, context)
+		require.NoError(t, err, "failed to mutate")
+	}
+	require.True(t, res, "mutation failed")
+	require.Len(t, pop.Innovations(), 1, "wrong number of innovations")
+	assert.Equal(t, math.LinearActivation, pop.Innovations()[0].NewNodeActivation)
+	assert.Equal(t, math.MaxAggregation, pop.Innovations()[0].NewNodeAggregation)
+
+	// the same mutation in other genome creates the node with the same functions
+	context.NodeActivators = []math.NodeActivationType{math.SigmoidSteepenedActivation}
+	context.NodeAggregators = []math.NodeAggregationType{math.SumAggregation}
+	gnome2 := buildTestGenome(2)
+	for _, gn := range gnome2.Genes[1:] {
+		gn.IsEnabled = false // force splitting of the same gene
+	}
+	res = false
+	for i := 0; i < 100 && !res; i++ {
+		res, err = gnome2.mutateAddNode(pop, pop, context)
+		require.NoError(t, err, "failed to mutate")
+	}
+	require.True(t, res, "mutation failed")
+	assert.Len(t, pop.Innovations(), 1, "existing innovation expected")
+
+	addedNode := gnome2.Nodes[len(gnome2.Nodes)-1]
+	assert.Equal(t, gnome1.Nodes[len(gnome1.Nodes)-1].Id, addedNode.Id)
+	assert.Equal(t, math.LinearActivation, addedNode.ActivationType, "wrong activation type")
+	assert.Equal(t, math.MaxAggregation, addedNode.AggregationType, "wrong aggregation type")
+}
+
 func TestGenome_mutateLinkWeights(t *testing.T) {
 	rand.Seed(42)
 	gnome1 := buildTestGenome(1)
-	res, err := gnome1.mutateLinkWeights(0.5, 1.0, gaussianMutator)
+	res, err := gnome1.mutateLinkWeights(0.5, 1.0, gaussianMutator, &neat.Options{})
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
@@ -151,6 +201,60 @@ func TestGenome_mutateLinkWeights(t *testing.T) {
 	}
 }
 
+func TestGenome_mutateLinkWeights_clamped(t *testing.T) {
+	rand.Seed(42)
+	gnome1 := buildTestGenome(1)
+	opts := &neat.Options{
+		WeightMutDistribution: neat.WeightDistributionGaussian,
+		WeightMutReplaceRate:  0.5,
+		WeightMin:             -2.0,
+		WeightMax:             2.0,
+	}
+	for i := 0; i < 10; i++ {
+		res, err := gnome1.mutateLinkWeights(10.0, 1.0, gaussianMutator, opts)
+		require.NoError(t, err, "failed to mutate")
+		require.True(t, res, "mutation failed")
+
+		for _, gn := range gnome1.Genes {
+			assert.True(t, gn.Link.ConnectionWeight >= -2.0 && gn.Link.ConnectionWeight <= 2.0,
+				"weight out of range: %s", gn)
+			assert.Equal(t, gn.Link.ConnectionWeight, gn.MutationNum)
+		}
+	}
+}
+
+func TestGenome_mutateLinkWeights_replaceFromInitialDistribution(t *testing.T) {
+	rand.Seed(42)
+	gnome1 := buildTestGenome(1)
+	opts := &neat.Options{
+		WeightMutReplaceRate: 1.0,
+		WeightInitPower:      0.01,
+	}
+	// all mutated weights should be replaced with ones drawn from the initial weights distribution
+	res, err := gnome1.mutateLinkWeights(10.0, 1.0, gaussianMutator, opts)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+	for _, gn := range gnome1.Genes {
+		assert.True(t, gn.Link.ConnectionWeight >= -0.01 && gn.Link.ConnectionWeight <= 0.01,
+			"weight not from initial distribution: %s", gn)
+	}
+}
+
+func TestGenome_mutateLinkWeights_zeroRate(t *testing.T) {
+	rand.Seed(42)
+	gnome1 := buildTestGenome(1)
+	opts := &neat.Options{
+		WeightMutReplaceRate: 1.0,
+	}
+	// with explicit replace rate and zero mutation rate no weights should be changed
+	res, err := gnome1.mutateLinkWeights(10.0, 0.0, gaussianMutator, opts)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+	for i, gn := range gnome1.Genes {
+		assert.Equal(t, float64(i)+1.5, gn.Link.ConnectionWeight, "Found mutated gene: %s", gn)
+	}
+}
+
 func TestGenome_mutateRandomTrait(t *testing.T) {
 	gnome1 := buildTestGenome(1)
 	// Configuration
@@ -222,6 +326,33 @@ func TestGenome_mutateNodeTrait(t *testing.T) {
 	assert.True(t, mutationFound, "No mutation found in nodes traits")
 }
 
+func TestGenome_mutateNodeAggregation(t *testing.T) {
+	gnome1 := buildTestGenome(1)
+	opts := &neat.Options{
+		NodeAggregators: []math.NodeAggregationType{math.MaxAggregation},
+	}
+
+	res, err := gnome1.mutateNodeAggregation(1, opts)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+
+	mutated := 0
+	for _, nd := range gnome1.Nodes {
+		if nd.AggregationType == math.MaxAggregation {
+			assert.True(t, nd.IsNeuron(), "sensor node aggregation mutated: %d", nd.Id)
+			mutated++
+		}
+	}
+	assert.Equal(t, 1, mutated, "wrong number of mutated nodes")
+
+	// check genome without neuron nodes
+	gnome2 := buildTestGenome(2)
+	gnome2.Nodes = gnome2.Nodes[:3]
+	res, err = gnome2.mutateNodeAggregation(1, opts)
+	require.NoError(t, err)
+	assert.False(t, res)
+}
+
 func TestGenome_mutateToggleEnable(t *testing.T) {
 	gnome1 := buildTestGenome(1)
 	// add extra connection gene from BIAS to OUT
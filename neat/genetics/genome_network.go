@@ -0,0 +1,171 @@
+package genetics
+
+import (
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"sort"
+)
+
+// NewGenomeFromNetwork creates the genome which phenotype is equivalent to the provided network, i.e., it does the
+// reverse of the Genesis. It allows using the hand-crafted network as a starting point of the evolution. The genome
+// gets the following:
+//   - the copies of the network nodes, and the copies of all distinct traits referenced by the network nodes and links.
+//     If the network has no traits, the default trait is added to be used by mutations;
+//   - the connection gene per each link of the network with innovation numbers assigned sequentially from 1 in order
+//     of the network nodes and their incoming links. The link is marked as recurrent if it was marked so in the network
+//     or if it closes a cycle in the network graph;
+//   - the MIMO control gene per each control node of the network with innovation numbers following the connection genes.
+//
+// The nodes of the genome are ordered by ID, thus the network sensors and outputs must be ordered by ID as well to
+// keep the order of the phenotype inputs and outputs. The provided network is not modified.
+func NewGenomeFromNetwork(net *network.Network, genomeId int) (*Genome, error) {
+	if err := checkNodesOrder(net.InputNodes(), "sensor"); err != nil {
+		return nil, err
+	}
+	if err := checkNodesOrder(net.Outputs, "output"); err != nil {
+		return nil, err
+	}
+
+	// collect traits
+	traitsMap := make(map[int]*neat.Trait)
+	collectTrait := func(trait *neat.Trait) {
+		if trait != nil {
+			if _, ok := traitsMap[trait.Id]; !ok {
+				traitsMap[trait.Id] = neat.NewTraitCopy(trait)
+			}
+		}
+	}
+	for _, node := range net.AllNodes() {
+		collectTrait(node.Trait)
+		for _, l := range node.Incoming {
+			collectTrait(l.Trait)
+		}
+	}
+	traits := make([]*neat.Trait, 0, len(traitsMap))
+	for _, trait := range traitsMap {
+		traits = append(traits, trait)
+	}
+	sort.Slice(traits, func(i, j int) bool {
+		return traits[i].Id < traits[j].Id
+	})
+	if len(traits) == 0 {
+		// Create a dummy trait to be used by mutations
+		trait := neat.NewTrait()
+		trait.Id = 1
+		trait.Params = make([]float64, neat.NumTraitParams)
+		traits = append(traits, trait)
+	}
+	traitCopy := func(trait *neat.Trait) *neat.Trait {
+		if trait == nil {
+			return nil
+		}
+		return traitsMap[trait.Id]
+	}
+
+	// copy nodes
+	gnome := NewGenome(genomeId, traits, make([]*network.NNode, 0, len(net.BaseNodes())), nil)
+	for _, node := range net.BaseNodes() {
+		if gnome.haveNode(node.Id) {
+			return nil, errors.Errorf("duplicate node ID: %d", node.Id)
+		}
+		gnome.nodeInsert(network.NewNNodeCopy(node, traitCopy(node.Trait)))
+	}
+
+	// create connection genes
+	recurrent := recurrentLinks(net)
+	innovation := int64(0)
+	gnome.Genes = make([]*Gene, 0, net.LinkCount())
+	for _, node := range net.BaseNodes() {
+		for _, l := range node.Incoming {
+			inNode, outNode := gnome.NodeWithId(l.InNode.Id), gnome.NodeWithId(l.OutNode.Id)
+			if inNode == nil {
+				return nil, errors.Errorf("incoming node: %d of the link to node: %d not found in the network",
+					l.InNode.Id, l.OutNode.Id)
+			}
+			innovation++
+			gene := NewGeneWithTrait(traitCopy(l.Trait), l.ConnectionWeight, inNode, outNode,
+				l.IsRecurrent || recurrent[l], innovation, l.ConnectionWeight)
+			gnome.Genes = append(gnome.Genes, gene)
+		}
+	}
+
+	// create control genes
+	for _, cn := range net.ControlNodes() {
+		if gnome.haveNode(cn.Id) {
+			return nil, errors.Errorf("duplicate control node ID: %d", cn.Id)
+		}
+		controlNode := network.NewNNodeCopy(cn, traitCopy(cn.Trait))
+		for _, l := range cn.Incoming {
+			inNode := gnome.NodeWithId(l.InNode.Id)
+			if inNode == nil {
+				return nil, errors.Errorf("incoming node: %d not found for control node: %d", l.InNode.Id, cn.Id)
+			}
+			controlNode.Incoming = append(controlNode.Incoming, network.NewLink(l.ConnectionWeight, inNode, controlNode, false))
+		}
+		for _, l := range cn.Outgoing {
+			outNode := gnome.NodeWithId(l.OutNode.Id)
+			if outNode == nil {
+				return nil, errors.Errorf("outgoing node: %d not found for control node: %d", l.OutNode.Id, cn.Id)
+			}
+			controlNode.Outgoing = append(controlNode.Outgoing, network.NewLink(l.ConnectionWeight, controlNode, outNode, false))
+		}
+		innovation++
+		gnome.ControlGenes = append(gnome.ControlGenes, NewMIMOGene(controlNode, innovation, 0, true))
+	}
+	return gnome, nil
+}
+
+// checkNodesOrder checks that provided nodes are ordered by ID
+func checkNodesOrder(nodes []*network.NNode, kind string) error {
+	for i := 1; i < len(nodes); i++ {
+		if nodes[i].Id <= nodes[i-1].Id {
+			return errors.Errorf("the %s nodes must be ordered by ID, found: %d after: %d", kind, nodes[i].Id, nodes[i-1].Id)
+		}
+	}
+	return nil
+}
+
+// recurrentLinks finds the links closing cycles in the network graph, i.e., the back edges found by the depth-first
+// search started from the sensors and continued from all not yet visited nodes.
+func recurrentLinks(net *network.Network) map[*network.Link]bool {
+	const (
+		notVisited = iota
+		inProgress
+		done
+	)
+	// collect outgoing links from the incoming links, which are always set for the network nodes
+	outgoing := make(map[*network.NNode][]*network.Link, len(net.BaseNodes()))
+	for _, node := range net.BaseNodes() {
+		for _, l := range node.Incoming {
+			outgoing[l.InNode] = append(outgoing[l.InNode], l)
+		}
+	}
+
+	state := make(map[*network.NNode]int, len(net.BaseNodes()))
+	recurrent := make(map[*network.Link]bool)
+	var visit func(node *network.NNode)
+	visit = func(node *network.NNode) {
+		state[node] = inProgress
+		for _, l := range outgoing[node] {
+			switch state[l.OutNode] {
+			case inProgress:
+				recurrent[l] = true
+			case notVisited:
+				visit(l.OutNode)
+			}
+		}
+		state[node] = done
+	}
+	for _, node := range net.InputNodes() {
+		if state[node] == notVisited {
+			visit(node)
+		}
+	}
+	for _, node := range net.BaseNodes() {
+		if state[node] == notVisited {
+			visit(node)
+		}
+	}
+	return recurrent
+}
@@ -0,0 +1,166 @@
+package genetics
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"testing"
+)
+
+// buildHandCraftedNetwork builds the recurrent network without traits
+func buildHandCraftedNetwork() *network.Network {
+	allNodes := []*network.NNode{
+		network.NewSensorNode(1, false),
+		network.NewSensorNode(2, false),
+		network.NewSensorNode(3, true),
+		network.NewNNode(4, network.OutputNeuron),
+		network.NewNNode(5, network.HiddenNeuron),
+	}
+	allNodes[4].ActivationType = math.TanhActivation
+	// HIDDEN 5
+	allNodes[4].ConnectFrom(allNodes[0], 1.5)
+	allNodes[4].ConnectFrom(allNodes[1], -2.0)
+	allNodes[4].ConnectFrom(allNodes[4], 0.5) // self-loop
+	allNodes[4].ConnectFrom(allNodes[3], 0.7) // from OUTPUT
+	// OUTPUT 4
+	allNodes[3].ConnectFrom(allNodes[4], 3.0)
+	allNodes[3].ConnectFrom(allNodes[2], -1.0)
+
+	return network.NewNetwork(allNodes[0:3], allNodes[3:4], allNodes, 1)
+}
+
+func activateSequence(t *testing.T, net *network.Network, inputs [][]float64) [][]float64 {
+	outputs := make([][]float64, len(inputs))
+	for i, in := range inputs {
+		err := net.LoadSensors(in)
+		require.NoError(t, err)
+		_, err = net.ForwardSteps(3)
+		require.NoError(t, err)
+		outputs[i] = net.ReadOutputs()
+	}
+	return outputs
+}
+
+var roundTripInputs = [][]float64{{0.5, 1.0}, {-1.0, 0.3}, {0.0, 0.0}, {2.0, -1.5}}
+
+func TestNewGenomeFromNetwork(t *testing.T) {
+	testCases := map[string]*Genome{
+		"plain":   buildTestGenome(1),
+		"modular": buildTestModularGenome(1),
+	}
+	for name, gnome := range testCases {
+		t.Run(name, func(t *testing.T) {
+			net, err := gnome.Genesis(1)
+			require.NoError(t, err)
+
+			newGnome, err := NewGenomeFromNetwork(net, 2)
+			require.NoError(t, err)
+			require.NotNil(t, newGnome)
+			assert.Equal(t, 2, newGnome.Id)
+			ok, err := newGnome.verify()
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			// check structure
+			require.Len(t, newGnome.Traits, len(gnome.Traits))
+			for _, tr := range gnome.Traits {
+				newTrait := TraitWithId(tr.Id, newGnome.Traits)
+				require.NotNil(t, newTrait, "trait %d not found", tr.Id)
+				assert.Equal(t, tr.Params, newTrait.Params)
+			}
+			require.Len(t, newGnome.Nodes, len(gnome.Nodes))
+			for i, node := range gnome.Nodes {
+				assert.Equal(t, node.Id, newGnome.Nodes[i].Id)
+				assert.Equal(t, node.NeuronType, newGnome.Nodes[i].NeuronType)
+				assert.Equal(t, node.ActivationType, newGnome.Nodes[i].ActivationType)
+			}
+			require.Len(t, newGnome.Genes, len(gnome.Genes))
+			for i, gene := range newGnome.Genes {
+				assert.EqualValues(t, i+1, gene.InnovationNum)
+				assert.True(t, gene.IsEnabled)
+				assert.False(t, gene.Link.IsRecurrent)
+				require.NotNil(t, gene.Link.Trait)
+				assert.Equal(t, gene.Link.Trait, TraitWithId(gene.Link.Trait.Id, newGnome.Traits))
+			}
+			require.Len(t, newGnome.ControlGenes, len(gnome.ControlGenes))
+			for i, cg := range newGnome.ControlGenes {
+				assert.EqualValues(t, len(newGnome.Genes)+i+1, cg.InnovationNum)
+				assert.Equal(t, gnome.ControlGenes[i].ControlNode.Id, cg.ControlNode.Id)
+				assert.Len(t, cg.ioNodes, len(gnome.ControlGenes[i].ioNodes))
+			}
+
+			// check round trip
+			newNet, err := newGnome.Genesis(2)
+			require.NoError(t, err)
+			assert.Equal(t, activateSequence(t, net, roundTripInputs), activateSequence(t, newNet, roundTripInputs))
+		})
+	}
+}
+
+func TestNewGenomeFromNetwork_handCrafted(t *testing.T) {
+	net := buildHandCraftedNetwork()
+	gnome, err := NewGenomeFromNetwork(net, 1)
+	require.NoError(t, err)
+
+	// the default trait is added
+	require.Len(t, gnome.Traits, 1)
+	assert.Len(t, gnome.Traits[0].Params, neat.NumTraitParams)
+
+	// the nodes are ordered by ID
+	ids := make([]int, len(gnome.Nodes))
+	for i, node := range gnome.Nodes {
+		ids[i] = node.Id
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+
+	// the links closing cycles are marked as recurrent
+	recurrent := make(map[[2]int]bool)
+	for _, gene := range gnome.Genes {
+		recurrent[[2]int{gene.Link.InNode.Id, gene.Link.OutNode.Id}] = gene.Link.IsRecurrent
+	}
+	expected := map[[2]int]bool{
+		{1, 5}: false,
+		{2, 5}: false,
+		{5, 5}: true,
+		{4, 5}: true,
+		{5, 4}: false,
+		{3, 4}: false,
+	}
+	assert.Equal(t, expected, recurrent)
+
+	// check round trip
+	newNet, err := gnome.Genesis(1)
+	require.NoError(t, err)
+	assert.Equal(t, activateSequence(t, net, roundTripInputs), activateSequence(t, newNet, roundTripInputs))
+}
+
+func TestNewGenomeFromNetwork_sensorsOrder(t *testing.T) {
+	allNodes := []*network.NNode{
+		network.NewSensorNode(2, false),
+		network.NewSensorNode(1, false),
+		network.NewNNode(3, network.OutputNeuron),
+	}
+	allNodes[2].ConnectFrom(allNodes[0], 1.0)
+	allNodes[2].ConnectFrom(allNodes[1], 1.0)
+	net := network.NewNetwork(allNodes[0:2], allNodes[2:3], allNodes, 1)
+
+	gnome, err := NewGenomeFromNetwork(net, 1)
+	assert.Error(t, err)
+	assert.Nil(t, gnome)
+}
+
+func TestNewGenomeFromNetwork_duplicateNode(t *testing.T) {
+	allNodes := []*network.NNode{
+		network.NewSensorNode(1, false),
+		network.NewNNode(2, network.OutputNeuron),
+		network.NewNNode(2, network.HiddenNeuron),
+	}
+	allNodes[1].ConnectFrom(allNodes[0], 1.0)
+	net := network.NewNetwork(allNodes[0:1], allNodes[1:2], allNodes, 1)
+
+	gnome, err := NewGenomeFromNetwork(net, 1)
+	assert.Error(t, err)
+	assert.Nil(t, gnome)
+}
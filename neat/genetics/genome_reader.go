@@ -167,8 +167,16 @@ func readPlainNetworkNode(r io.Reader, traits []*neat.Trait) (*network.NNode, er
 		node.NeuronType = network.NodeNeuronType(neuronType)
 	}
 
-	if len(parts) == 5 {
-		node.ActivationType, err = math.NodeActivators.ActivationTypeFromName(parts[4])
+	if len(parts) >= 5 {
+		if node.ActivationType, err = math.NodeActivators.ActivationTypeFromName(parts[4]); err != nil {
+			return nil, err
+		}
+	}
+	// the aggregation function is optional and defaults to sum
+	if len(parts) >= 6 {
+		if node.AggregationType, err = math.AggregationTypeFromName(parts[5]); err != nil {
+			return nil, err
+		}
 	}
 
 	return node, err
@@ -424,7 +432,13 @@ func readNNode(conf map[string]interface{}, traits []*neat.Trait) (*network.NNod
 		return nil, err
 	}
 	activation := conf["activation"].(string)
-	node.ActivationType, err = math.NodeActivators.ActivationTypeFromName(activation)
+	if node.ActivationType, err = math.NodeActivators.ActivationTypeFromName(activation); err != nil {
+		return nil, err
+	}
+	// the aggregation function is optional and defaults to sum
+	if aggregation, ok := conf["aggregation"].(string); ok {
+		node.AggregationType, err = math.AggregationTypeFromName(aggregation)
+	}
 	return node, err
 }
 
@@ -1,6 +1,8 @@
 package genetics
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -128,6 +130,52 @@ func TestReadGene_ReadPlainNNode(t *testing.T) {
 	assert.Equal(t, genNodeLabel, node.NeuronType, "wrong node placement label (neuron type) found")
 }
 
+func TestReadGene_ReadPlainNNode_customActivation(t *testing.T) {
+	aType, err := math.RegisterExpressionActivation("genome_test_square", "a0 * x ^ 2")
+	require.NoError(t, err)
+
+	trait := neat.NewTrait()
+	trait.Id = 10
+	nodeStr := fmt.Sprintf("%d %d %d %d %s", 4, 10, network.NeuronNode, network.HiddenNeuron, "genome_test_square")
+	node, err := readPlainNetworkNode(strings.NewReader(nodeStr), []*neat.Trait{trait})
+	require.NoError(t, err, "failed to read network node")
+	assert.Equal(t, aType, node.ActivationType)
+
+	// write and check that name is preserved
+	outBuf := bytes.NewBufferString("")
+	wr := plainGenomeWriter{w: bufio.NewWriter(outBuf)}
+	err = wr.writeNetworkNode(node)
+	require.NoError(t, err)
+	require.NoError(t, wr.w.Flush())
+	assert.Contains(t, outBuf.String(), "genome_test_square")
+}
+
+func TestReadGene_ReadPlainNNode_aggregation(t *testing.T) {
+	trait := neat.NewTrait()
+	trait.Id = 10
+	nodeStr := fmt.Sprintf("%d %d %d %d %s %s", 4, 10, network.NeuronNode, network.HiddenNeuron,
+		"SigmoidSteepenedActivation", "MedianAggregation")
+	node, err := readPlainNetworkNode(strings.NewReader(nodeStr), []*neat.Trait{trait})
+	require.NoError(t, err, "failed to read network node")
+	assert.Equal(t, math.SigmoidSteepenedActivation, node.ActivationType)
+	assert.Equal(t, math.MedianAggregation, node.AggregationType)
+
+	// write and check that aggregation is preserved
+	outBuf := bytes.NewBufferString("")
+	wr := plainGenomeWriter{w: bufio.NewWriter(outBuf)}
+	err = wr.writeNetworkNode(node)
+	require.NoError(t, err)
+	require.NoError(t, wr.w.Flush())
+	assert.Equal(t, nodeStr, outBuf.String())
+
+	// check unsupported aggregation
+	nodeStr = fmt.Sprintf("%d %d %d %d %s %s", 4, 10, network.NeuronNode, network.HiddenNeuron,
+		"SigmoidSteepenedActivation", "SoftmaxAggregation")
+	node, err = readPlainNetworkNode(strings.NewReader(nodeStr), []*neat.Trait{trait})
+	assert.EqualError(t, err, "unsupported aggregation type name: SoftmaxAggregation")
+	assert.Nil(t, node)
+}
+
 func TestReadGene_ReadPlainNNode_readError(t *testing.T) {
 	trait := neat.NewTrait()
 	trait.Id = 10
@@ -12,8 +12,9 @@ import (
 // This method mates this Genome with another Genome g. For every point in each Genome, where each Genome shares
 // the innovation number, the Gene is chosen randomly from either parent.  If one parent has an innovation absent in
 // the other, the baby may inherit the innovation if it is from the more fit parent.
-// The new Genome is given the id in the genomeId argument.
-func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 float64) (*Genome, error) {
+// The new Genome is given the id in the genomeId argument. The genes violating topology restrictions or complexity
+// caps defined by options are not inherited by the baby (applies to all mating methods).
+func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 float64, opts *neat.Options) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(g.Traits) != len(og.Traits) {
 		return nil, fmt.Errorf("genomes has different traits count, %d != %d", len(g.Traits), len(og.Traits))
@@ -125,6 +126,11 @@ func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 flo
 			}
 		}
 
+		// Check to see if the chosen gene violates topology restrictions or complexity caps
+		if !skip && !isGeneAllowed(chosenGene, newGenes, newNodes, opts) {
+			skip = true
+		}
+
 		// Now add the chosen gene to the baby
 		if !skip {
 			// Check for the nodes, add them if not in the baby Genome already
@@ -203,8 +209,8 @@ func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 flo
 }
 
 // This method mates like multipoint but instead of selecting one or the other when the innovation numbers match,
-// it averages their weights.
-func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2 float64) (*Genome, error) {
+// it averages their weights. The averaged weights are clamped according to provided options.
+func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2 float64, opts *neat.Options) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(g.Traits) != len(og.Traits) {
 		return nil, fmt.Errorf("genomes has different traits count, %d != %d", len(g.Traits), len(og.Traits))
@@ -286,7 +292,7 @@ func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2
 				} else {
 					avgGene.Link.Trait = p2gene.Link.Trait
 				}
-				avgGene.Link.ConnectionWeight = (p1gene.Link.ConnectionWeight + p2gene.Link.ConnectionWeight) / 2.0 // WEIGHTS AVERAGED HERE
+				avgGene.Link.ConnectionWeight = opts.ClampWeight((p1gene.Link.ConnectionWeight + p2gene.Link.ConnectionWeight) / 2.0) // WEIGHTS AVERAGED HERE
 
 				if rand.Float64() > 0.5 {
 					avgGene.Link.InNode = p1gene.Link.InNode
@@ -341,6 +347,11 @@ func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2
 			}
 		}
 
+		// Check to see if the chosen gene violates topology restrictions or complexity caps
+		if !skip && !isGeneAllowed(chosenGene, newGenes, newNodes, opts) {
+			skip = true
+		}
+
 		if !skip {
 			// Now add the chosen gene to the baby
 
@@ -418,7 +429,7 @@ func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2
 // This method is similar to a standard single point CROSSOVER operator. Traits are averaged as in the previous two
 // mating methods. A Gene is chosen in the smaller Genome for splitting. When the Gene is reached, it is averaged with
 // the matching Gene from the larger Genome, if one exists. Then every other Gene is taken from the larger Genome.
-func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
+func (g *Genome) mateSinglePoint(og *Genome, genomeId int, opts *neat.Options) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(g.Traits) != len(og.Traits) {
 		return nil, fmt.Errorf("genomes has different traits count, %d != %d", len(g.Traits), len(og.Traits))
@@ -509,7 +520,7 @@ func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
 					} else {
 						avgGene.Link.Trait = p2gene.Link.Trait
 					}
-					avgGene.Link.ConnectionWeight = (p1gene.Link.ConnectionWeight + p2gene.Link.ConnectionWeight) / 2.0 // WEIGHTS AVERAGED HERE
+					avgGene.Link.ConnectionWeight = opts.ClampWeight((p1gene.Link.ConnectionWeight + p2gene.Link.ConnectionWeight) / 2.0) // WEIGHTS AVERAGED HERE
 
 					if rand.Float64() > 0.5 {
 						avgGene.Link.InNode = p1gene.Link.InNode
@@ -570,6 +581,11 @@ func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
 			}
 		}
 
+		// Check to see if the chosen gene violates topology restrictions or complexity caps
+		if !skip && !isGeneAllowed(chosenGene, newGenes, newNodes, opts) {
+			skip = true
+		}
+
 		// Now add the chosen gene to the baby
 		if !skip {
 			// Check for the nodes, add them if not in the baby Genome already
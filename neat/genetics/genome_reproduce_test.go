@@ -3,6 +3,7 @@ package genetics
 import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/network"
 	"math/rand"
 	"testing"
@@ -16,7 +17,7 @@ func TestGenome_mateMultipoint(t *testing.T) {
 	gnome2 := buildTestGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2, &neat.Options{})
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -30,7 +31,7 @@ func TestGenome_mateMultipoint(t *testing.T) {
 		gnome1.Nodes[3], false), 4, 0, true)
 	gnome1.Genes = append(gnome1.Genes, gene)
 	fitness1, fitness2 = 15.0, 2.3
-	genomeChild, err = gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err = gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2, &neat.Options{})
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -47,7 +48,7 @@ func TestGenome_mateMultipointModular(t *testing.T) {
 	gnome2 := buildTestModularGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2, &neat.Options{})
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -65,7 +66,7 @@ func TestGenome_mateMultipointAvg(t *testing.T) {
 	gnome2 := buildTestGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2, &neat.Options{})
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -83,7 +84,7 @@ func TestGenome_mateMultipointAvg(t *testing.T) {
 	gnome2.Genes = append(gnome2.Genes, gene2)
 
 	fitness1, fitness2 = 15.0, 2.3
-	genomeChild, err = gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err = gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2, &neat.Options{})
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -92,6 +93,29 @@ func TestGenome_mateMultipointAvg(t *testing.T) {
 	assert.Len(t, genomeChild.Traits, 3, "wrong number of traits")
 }
 
+func TestGenome_mateMultipointAvg_clamped(t *testing.T) {
+	rand.Seed(42)
+	gnome1 := buildTestGenome(1)
+	gnome2 := buildTestGenome(2)
+	// the averages of weights are: 4.0, -4.0, and 0.75
+	for i, weights := range [][2]float64{{3.0, 5.0}, {-3.0, -5.0}, {1.0, 0.5}} {
+		gnome1.Genes[i].Link.ConnectionWeight = weights[0]
+		gnome2.Genes[i].Link.ConnectionWeight = weights[1]
+	}
+	opts := &neat.Options{
+		WeightMin: -2.0,
+		WeightMax: 2.0,
+	}
+	genomeChild, err := gnome1.mateMultipointAvg(gnome2, 3, 1.0, 1.0, opts)
+	require.NoError(t, err, "failed to mate")
+	require.NotNil(t, genomeChild, "Failed to create child genome")
+	require.Len(t, genomeChild.Genes, 3, "wrong number of genes")
+	expected := []float64{2.0, -2.0, 0.75}
+	for i, gn := range genomeChild.Genes {
+		assert.Equal(t, expected[i], gn.Link.ConnectionWeight, "wrong weight: %s", gn)
+	}
+}
+
 func TestGenome_mateMultipointAvgModular(t *testing.T) {
 	rand.Seed(42)
 	// Check equal sized gene pools
@@ -100,7 +124,7 @@ func TestGenome_mateMultipointAvgModular(t *testing.T) {
 	gnome2 := buildTestModularGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2, &neat.Options{})
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -117,7 +141,7 @@ func TestGenome_mateSinglePoint(t *testing.T) {
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestGenome(2)
 	genomeId := 3
-	genomeChild, err := gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err := gnome1.mateSinglePoint(gnome2, genomeId, &neat.Options{})
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -130,7 +154,7 @@ func TestGenome_mateSinglePoint(t *testing.T) {
 	gene := NewConnectionGene(network.NewLinkWithTrait(gnome1.Traits[2], 5.5, gnome1.Nodes[2],
 		gnome1.Nodes[3], false), 4, 0, false)
 	gnome1.Genes = append(gnome1.Genes, gene)
-	genomeChild, err = gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err = gnome1.mateSinglePoint(gnome2, genomeId, &neat.Options{})
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -144,7 +168,7 @@ func TestGenome_mateSinglePoint(t *testing.T) {
 	// append additional gene
 	gnome2.Genes = append(gnome2.Genes, NewConnectionGene(network.NewLinkWithTrait(gnome2.Traits[2], 5.5, gnome2.Nodes[1],
 		gnome2.Nodes[3], true), 4, 0, false))
-	genomeChild, err = gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err = gnome1.mateSinglePoint(gnome2, genomeId, &neat.Options{})
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -161,7 +185,7 @@ func TestGenome_mateSinglePointModular(t *testing.T) {
 	gnome2 := buildTestModularGenome(2)
 	genomeId := 3
 
-	genomeChild, err := gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err := gnome1.mateSinglePoint(gnome2, genomeId, &neat.Options{})
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -0,0 +1,181 @@
+package genetics
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+)
+
+// GenomeSimplifyReport describes the genes and nodes removed from the genome by simplification.
+type GenomeSimplifyReport struct {
+	// The report of the phenotype simplification
+	*network.SimplifyReport
+	// The disabled connection genes removed as far as they are not expressed in the phenotype
+	DisabledGenes []*Gene
+	// The disabled control genes removed as far as they are not expressed in the phenotype
+	DisabledControlGenes []*MIMOControlGene
+	// The connection genes added to connect neighbours of the folded linear nodes
+	AddedGenes []*Gene
+}
+
+// Stringer
+func (r *GenomeSimplifyReport) String() string {
+	return fmt.Sprintf("%s\tdisabled genes: %d\n\tdisabled control genes: %d\n\tadded genes: %d\n",
+		r.SimplifyReport, len(r.DisabledGenes), len(r.DisabledControlGenes), len(r.AddedGenes))
+}
+
+// Simplify returns the simplified copy of this genome which phenotype produces the same outputs as the phenotype of
+// this genome, and the report describing removed structure. The disabled genes are removed, and the rest of the genome
+// is simplified as described by network.Network Simplify. The genes of the merged links hold the summed weights. The
+// genes connecting neighbours of the folded linear nodes get the innovation numbers of the matching link innovations
+// known to the provided innovations observer, or the new global innovation numbers otherwise.
+func (g *Genome) Simplify(innovations InnovationsObserver) (*Genome, *GenomeSimplifyReport, error) {
+	phenotype := g.Phenotype
+	net, err := g.Genesis(g.Id)
+	// restore the phenotype of this genome
+	g.Phenotype = phenotype
+	if err != nil {
+		return nil, nil, err
+	}
+	simplified, netReport := net.Simplify()
+	report := &GenomeSimplifyReport{SimplifyReport: netReport}
+
+	// duplicate the traits
+	traits := make([]*neat.Trait, len(g.Traits))
+	for i, tr := range g.Traits {
+		traits[i] = neat.NewTraitCopy(tr)
+	}
+	traitCopy := func(trait *neat.Trait) *neat.Trait {
+		if trait == nil {
+			return nil
+		}
+		return TraitWithId(trait.Id, traits)
+	}
+
+	// copy the nodes remaining in the simplified phenotype
+	remaining := make(map[int]bool, simplified.NodeCount())
+	for _, node := range simplified.BaseNodes() {
+		remaining[node.Id] = true
+	}
+	nodes := make([]*network.NNode, 0, len(remaining))
+	nodeIdMap := make(map[int]*network.NNode, len(remaining))
+	for _, node := range g.Nodes {
+		if remaining[node.Id] {
+			nodeCopy := network.NewNNodeCopy(node, traitCopy(node.Trait))
+			nodes = append(nodes, nodeCopy)
+			nodeIdMap[node.Id] = nodeCopy
+		}
+	}
+
+	// collect the links of the simplified phenotype. The parallel links connecting the same nodes are kept for
+	// the nodes with other than sum aggregation, they are matched with genes in order of appearance.
+	type linkKey struct {
+		inId, outId int
+		recurrent   bool
+	}
+	links := make(map[linkKey][]*network.Link)
+	linksOrder := make([]linkKey, 0)
+	for _, node := range simplified.BaseNodes() {
+		for _, l := range node.Incoming {
+			key := linkKey{inId: l.InNode.Id, outId: l.OutNode.Id, recurrent: l.IsRecurrent}
+			links[key] = append(links[key], l)
+			linksOrder = append(linksOrder, key)
+		}
+	}
+	nextLink := func(key linkKey) (*network.Link, bool) {
+		keyLinks := links[key]
+		if len(keyLinks) == 0 {
+			return nil, false
+		}
+		links[key] = keyLinks[1:]
+		return keyLinks[0], true
+	}
+
+	// copy the genes of the remaining links with updated weights
+	genes := make([]*Gene, 0, len(links))
+	for _, gn := range g.Genes {
+		if !gn.IsEnabled {
+			report.DisabledGenes = append(report.DisabledGenes, gn)
+			continue
+		}
+		key := linkKey{inId: gn.Link.InNode.Id, outId: gn.Link.OutNode.Id, recurrent: gn.Link.IsRecurrent}
+		l, ok := nextLink(key)
+		if !ok {
+			// removed or merged
+			continue
+		}
+		link := network.NewLinkWithTrait(traitCopy(gn.Link.Trait), l.ConnectionWeight,
+			nodeIdMap[key.inId], nodeIdMap[key.outId], gn.Link.IsRecurrent)
+		genes = append(genes, NewConnectionGene(link, gn.InnovationNum, gn.MutationNum, true))
+	}
+
+	// copy the control genes of the remaining control nodes
+	remainingControl := make(map[int]bool, len(simplified.ControlNodes()))
+	for _, cn := range simplified.ControlNodes() {
+		remainingControl[cn.Id] = true
+	}
+	controlGenes := make([]*MIMOControlGene, 0, len(remainingControl))
+	for _, cg := range g.ControlGenes {
+		if !cg.IsEnabled {
+			report.DisabledControlGenes = append(report.DisabledControlGenes, cg)
+			continue
+		}
+		if !remainingControl[cg.ControlNode.Id] {
+			continue
+		}
+		controlNode := network.NewNNodeCopy(cg.ControlNode, traitCopy(cg.ControlNode.Trait))
+		for _, l := range cg.ControlNode.Incoming {
+			inNode, ok := nodeIdMap[l.InNode.Id]
+			if !ok {
+				return nil, nil, errors.Errorf("incoming node: %d not found for control node: %d",
+					l.InNode.Id, controlNode.Id)
+			}
+			controlNode.Incoming = append(controlNode.Incoming, network.NewLinkCopy(l, inNode, controlNode))
+		}
+		for _, l := range cg.ControlNode.Outgoing {
+			outNode, ok := nodeIdMap[l.OutNode.Id]
+			if !ok {
+				return nil, nil, errors.Errorf("outgoing node: %d not found for control node: %d",
+					l.OutNode.Id, controlNode.Id)
+			}
+			controlNode.Outgoing = append(controlNode.Outgoing, network.NewLinkCopy(l, controlNode, outNode))
+		}
+		controlGenes = append(controlGenes, NewMIMOGeneCopy(cg, controlNode))
+	}
+
+	// add genes for the new links connecting neighbours of the folded nodes
+	for _, key := range linksOrder {
+		l, ok := nextLink(key)
+		if !ok {
+			continue
+		}
+		// Check to see if this innovation already occurred in the population
+		var innovationNum int64
+		if inn, found := findLinkInnovation(innovations, key.inId, key.outId, key.recurrent); found {
+			innovationNum = inn.InnovationNum
+		} else {
+			innovationNum = innovations.NextInnovationNumber()
+			traitNum := 0
+			if l.Trait != nil {
+				for i, tr := range traits {
+					if tr.Id == l.Trait.Id {
+						traitNum = i
+					}
+				}
+			}
+			innovations.StoreInnovation(*NewInnovationForRecurrentLink(key.inId, key.outId, innovationNum,
+				l.ConnectionWeight, traitNum, key.recurrent))
+		}
+		link := network.NewLinkWithTrait(traitCopy(l.Trait), l.ConnectionWeight,
+			nodeIdMap[key.inId], nodeIdMap[key.outId], key.recurrent)
+		gene := NewConnectionGene(link, innovationNum, l.ConnectionWeight, true)
+		genes = geneInsert(genes, gene)
+		report.AddedGenes = append(report.AddedGenes, gene)
+	}
+
+	if len(controlGenes) == 0 {
+		return NewGenome(g.Id, traits, nodes, genes), report, nil
+	}
+	return NewModularGenome(g.Id, traits, nodes, genes, controlGenes), report, nil
+}
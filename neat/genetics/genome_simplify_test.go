@@ -0,0 +1,198 @@
+package genetics
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"testing"
+)
+
+const redundantGenomeStr = `genomestart 1
+trait 1 0.1 0 0 0 0 0 0 0
+trait 2 0.2 0 0 0 0 0 0 0
+node 1 0 1 1 SigmoidSteepenedActivation
+node 2 0 1 1 SigmoidSteepenedActivation
+node 3 0 1 3 SigmoidSteepenedActivation
+node 4 0 0 2 SigmoidSteepenedActivation
+node 5 1 0 0 SigmoidSteepenedActivation
+node 6 0 0 0 LinearActivation
+node 7 0 0 0 SigmoidSteepenedActivation
+node 8 0 0 0 SigmoidSteepenedActivation
+gene 1 1 5 1.5 false 1 1.5 true
+gene 1 2 5 -0.5 false 2 -0.5 true
+gene 2 5 6 2.0 false 3 2.0 true
+gene 2 6 4 1.2 false 4 1.2 true
+gene 1 3 4 0.3 false 5 0.3 true
+gene 1 1 7 1.0 false 6 1.0 true
+gene 1 2 4 0.8 false 7 0.8 false
+gene 1 8 4 3.0 false 8 3.0 true
+genomeend 1`
+
+func readGenomeForSimplifyTest(t *testing.T, str string) *Genome {
+	reader, err := NewGenomeReader(bytes.NewBufferString(str), PlainGenomeEncoding)
+	require.NoError(t, err)
+	genome, err := reader.Read()
+	require.NoError(t, err)
+	return genome
+}
+
+func activatePhenotypeForSimplifyTest(t *testing.T, net *network.Network, inputs []float64) []float64 {
+	require.NoError(t, net.LoadSensors(inputs))
+	depth, err := net.MaxActivationDepth()
+	require.NoError(t, err)
+	_, err = net.ForwardSteps(depth)
+	require.NoError(t, err)
+	return net.ReadOutputs()
+}
+
+func TestGenome_Simplify(t *testing.T) {
+	genome := readGenomeForSimplifyTest(t, redundantGenomeStr)
+	phenotype, err := genome.Genesis(genome.Id)
+	require.NoError(t, err)
+
+	innovations := NewInnovationsStore(100)
+	simplified, report, err := genome.Simplify(innovations)
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Same(t, phenotype, genome.Phenotype, "phenotype of original genome must be preserved")
+	assert.Len(t, genome.Genes, 8, "original genome must not be modified")
+
+	// check report
+	assert.Len(t, report.DisabledGenes, 1)
+	assert.EqualValues(t, 7, report.DisabledGenes[0].InnovationNum)
+	assert.Len(t, report.RemovedNodes, 2)
+	assert.Len(t, report.FoldedNodes, 1)
+	require.Len(t, report.AddedGenes, 1)
+	assert.EqualValues(t, 101, report.AddedGenes[0].InnovationNum)
+	inn, found := innovations.FindLinkInnovation(5, 4, false)
+	require.True(t, found, "innovation of added gene must be stored")
+	assert.EqualValues(t, 101, inn.InnovationNum)
+
+	// check structure
+	nodeIds := make([]int, len(simplified.Nodes))
+	for i, n := range simplified.Nodes {
+		nodeIds[i] = n.Id
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, nodeIds)
+	require.Len(t, simplified.Genes, 4)
+	innovationNums := make([]int64, len(simplified.Genes))
+	for i, gn := range simplified.Genes {
+		innovationNums[i] = gn.InnovationNum
+		assert.True(t, gn.IsEnabled)
+	}
+	assert.Equal(t, []int64{1, 2, 5, 101}, innovationNums)
+	added := simplified.Genes[3]
+	assert.Equal(t, 5, added.Link.InNode.Id)
+	assert.Equal(t, 4, added.Link.OutNode.Id)
+	assert.InDelta(t, 2.4, added.Link.ConnectionWeight, 1e-12)
+	require.NotNil(t, added.Link.Trait)
+	assert.Equal(t, 2, added.Link.Trait.Id)
+	require.NotNil(t, simplified.Nodes[4].Trait)
+	assert.Equal(t, 1, simplified.Nodes[4].Trait.Id)
+
+	// check outputs
+	simplifiedPhenotype, err := simplified.Genesis(simplified.Id)
+	require.NoError(t, err)
+	for _, in := range [][]float64{{0.5, 1.1}, {0.0, 0.0}, {-1.0, 2.0}} {
+		_, err = phenotype.Flush()
+		require.NoError(t, err)
+		expected := activatePhenotypeForSimplifyTest(t, phenotype, in)
+		actual := activatePhenotypeForSimplifyTest(t, simplifiedPhenotype, in)
+		assert.InDeltaSlice(t, expected, actual, 1e-12, "wrong outputs for: %v", in)
+	}
+
+	// the same innovation number is assigned when simplifying the same structure again
+	simplified, report, err = genome.Simplify(innovations)
+	require.NoError(t, err)
+	require.Len(t, report.AddedGenes, 1)
+	assert.EqualValues(t, 101, report.AddedGenes[0].InnovationNum)
+	assert.EqualValues(t, 101, innovations.LastInnovationNumber())
+
+	// the genes stay sorted when the added gene reuses lower innovation number
+	innovations = NewInnovationsStore(100)
+	innovations.StoreInnovation(*NewInnovationForLink(5, 4, 3, 2.4, 0))
+	simplified, report, err = genome.Simplify(innovations)
+	require.NoError(t, err)
+	require.Len(t, report.AddedGenes, 1)
+	assert.EqualValues(t, 3, report.AddedGenes[0].InnovationNum)
+	innovationNums = make([]int64, len(simplified.Genes))
+	for i, gn := range simplified.Genes {
+		innovationNums[i] = gn.InnovationNum
+	}
+	assert.Equal(t, []int64{1, 2, 3, 5}, innovationNums)
+}
+
+func TestGenome_Simplify_recurrent(t *testing.T) {
+	genomeStr := `genomestart 1
+trait 1 0.1 0 0 0 0 0 0 0
+node 1 0 1 1 SigmoidSteepenedActivation
+node 2 0 1 3 SigmoidSteepenedActivation
+node 3 0 0 0 SigmoidSteepenedActivation
+node 4 0 0 2 LinearActivation MaxAggregation
+gene 1 1 3 1.0 false 1 1.0 true
+gene 1 3 4 2.0 true 2 2.0 true
+gene 1 3 4 -0.5 false 3 -0.5 true
+gene 1 4 3 0.7 true 4 0.7 true
+genomeend 1`
+	genome := readGenomeForSimplifyTest(t, genomeStr)
+
+	simplified, report, err := genome.Simplify(NewInnovationsStore(4))
+	require.NoError(t, err)
+	assert.True(t, report.IsEmpty(), report.String())
+	require.Len(t, simplified.Genes, len(genome.Genes))
+	for i, gn := range simplified.Genes {
+		expected := genome.Genes[i]
+		assert.Equal(t, expected.InnovationNum, gn.InnovationNum)
+		assert.Equal(t, expected.Link.IsRecurrent, gn.Link.IsRecurrent, "wrong recurrent flag at: %d", i)
+		assert.Equal(t, expected.Link.ConnectionWeight, gn.Link.ConnectionWeight, "wrong weight at: %d", i)
+	}
+}
+
+func TestGenome_Simplify_aggregation(t *testing.T) {
+	genomeStr := `genomestart 1
+trait 1 0.1 0 0 0 0 0 0 0
+node 1 0 1 1 SigmoidSteepenedActivation
+node 2 0 1 3 SigmoidSteepenedActivation
+node 3 0 0 0 LinearActivation
+node 4 0 0 2 LinearActivation MaxAggregation
+gene 1 1 3 2.0 false 1 2.0 true
+gene 1 1 4 1.0 false 2 1.0 true
+gene 1 1 4 -1.0 false 3 -1.0 true
+gene 1 3 4 0.5 false 4 0.5 true
+genomeend 1`
+	genome := readGenomeForSimplifyTest(t, genomeStr)
+	phenotype, err := genome.Genesis(genome.Id)
+	require.NoError(t, err)
+
+	simplified, report, err := genome.Simplify(NewInnovationsStore(4))
+	require.NoError(t, err)
+	assert.True(t, report.IsEmpty(), report.String())
+	require.Len(t, simplified.Genes, len(genome.Genes))
+	for i, gn := range simplified.Genes {
+		assert.Equal(t, genome.Genes[i].InnovationNum, gn.InnovationNum)
+		assert.Equal(t, genome.Genes[i].Link.ConnectionWeight, gn.Link.ConnectionWeight, "wrong weight at: %d", i)
+	}
+
+	// check outputs
+	simplifiedPhenotype, err := simplified.Genesis(simplified.Id)
+	require.NoError(t, err)
+	for _, in := range [][]float64{{0.5, 1.0}, {-2.0, 1.0}} {
+		_, err = phenotype.Flush()
+		require.NoError(t, err)
+		expected := activatePhenotypeForSimplifyTest(t, phenotype, in)
+		actual := activatePhenotypeForSimplifyTest(t, simplifiedPhenotype, in)
+		assert.InDeltaSlice(t, expected, actual, 1e-12, "wrong outputs for: %v", in)
+	}
+}
+
+func TestGenome_Simplify_modular(t *testing.T) {
+	genome := buildTestModularGenome(1)
+
+	simplified, report, err := genome.Simplify(NewInnovationsStore(0))
+	require.NoError(t, err)
+	assert.True(t, report.IsEmpty(), report.String())
+	assert.Len(t, simplified.Nodes, len(genome.Nodes))
+	assert.Len(t, simplified.Genes, len(genome.Genes))
+	assert.Len(t, simplified.ControlGenes, len(genome.ControlGenes))
+}
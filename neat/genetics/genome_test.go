@@ -6,6 +6,7 @@ import (
 	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/math"
 	"github.com/yaricom/goNEAT/v4/neat/network"
+	gomath "math"
 	"math/rand"
 	"testing"
 )
@@ -112,6 +113,37 @@ func TestGenome_Genesis(t *testing.T) {
 	assert.Equal(t, len(gnome.Genes), net.LinkCount(), "wrong links count")
 }
 
+func TestGenome_Genesis_traitParams(t *testing.T) {
+	gnome := buildTestGenome(1)
+	outNode := gnome.Nodes[3]
+	outNode.Trait = gnome.Traits[1]
+	outNode.ActivationType = math.SigmoidParametricActivation
+
+	net, err := gnome.Genesis(10)
+	require.NoError(t, err, "genesis failed")
+
+	for _, node := range net.BaseNodes() {
+		if node.Id == outNode.Id {
+			assert.Equal(t, outNode.Trait.Params, node.Params)
+		} else {
+			assert.Nil(t, node.Params, "node without trait must have no parameters: %d", node.Id)
+		}
+	}
+
+	// the parameters are copied and trait mutations do not affect already built network
+	outNode.Trait.Params[0] = 1.0
+	assert.Equal(t, 0.3, net.Outputs[0].Params[0])
+
+	// check that parameters are used for activation: 1 / (1 + exp(-4.924273 * 1.3 * x))
+	err = net.LoadSensors([]float64{0.5, 0.5})
+	require.NoError(t, err)
+	_, err = net.Activate()
+	require.NoError(t, err)
+	signal := 0.5*1.5 + 0.5*2.5 + 1.0*3.5
+	expected := 1.0 / (1.0 + gomath.Exp(-4.924273*1.3*signal))
+	assert.InDelta(t, expected, net.ReadOutputs()[0], 1e-12)
+}
+
 func TestGenome_GenesisModular(t *testing.T) {
 	gnome := buildTestModularGenome(1)
 	netId := 10
@@ -111,6 +111,13 @@ func (wr *plainGenomeWriter) writeNetworkNode(n *network.NNode) error {
 		_, err = fmt.Fprintf(wr.w, "%d %d %d %d %s", n.Id, traitId, n.NodeType(),
 			n.NeuronType, actStr)
 	}
+	if err == nil && n.AggregationType != math.SumAggregation {
+		// the sum aggregation is default and omitted to keep the format backward compatible
+		var aggStr string
+		if aggStr, err = math.AggregationNameFromType(n.AggregationType); err == nil {
+			_, err = fmt.Fprintf(wr.w, " %s", aggStr)
+		}
+	}
 	return err
 }
 
@@ -259,7 +266,12 @@ func (wr *yamlGenomeWriter) encodeNetworkNode(node *network.NNode) (nMap map[str
 		nMap["trait_id"] = 0
 	}
 	nMap["type"] = network.NeuronTypeName(node.NeuronType)
-	nMap["activation"], err = math.NodeActivators.ActivationNameFromType(node.ActivationType)
+	if nMap["activation"], err = math.NodeActivators.ActivationNameFromType(node.ActivationType); err != nil {
+		return nil, err
+	}
+	if node.AggregationType != math.SumAggregation {
+		nMap["aggregation"], err = math.AggregationNameFromType(node.AggregationType)
+	}
 	return nMap, err
 }
 
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/math"
 	"github.com/yaricom/goNEAT/v4/neat/network"
 	"strings"
 	"testing"
@@ -141,6 +142,8 @@ func TestPlainGenomeWriter_WriteGenome_writeError(t *testing.T) {
 
 func TestYamlGenomeWriter_WriteGenome(t *testing.T) {
 	gnome := buildTestModularGenome(1)
+	// set non default aggregation function to the output node
+	gnome.Nodes[4].AggregationType = math.MaxAggregation
 
 	// encode genome
 	outBuf := bytes.NewBufferString("")
@@ -175,6 +178,7 @@ func TestYamlGenomeWriter_WriteGenome(t *testing.T) {
 		nd := gnomeEnc.Nodes[i]
 		assert.Equal(t, n.Id, nd.Id, "wrong node ID at: %d", i)
 		assert.Equal(t, n.ActivationType, nd.ActivationType, "wrong node activation at: %d", i)
+		assert.Equal(t, n.AggregationType, nd.AggregationType, "wrong node aggregation at: %d", i)
 		assert.Equal(t, n.NeuronType, nd.NeuronType, "wrong node neuron type at: %d", i)
 	}
 
@@ -1,5 +1,7 @@
 package genetics
 
+import "github.com/yaricom/goNEAT/v4/neat/math"
+
 // InnovationsObserver the definition of component able to manage records of innovations
 type InnovationsObserver interface {
 	// StoreInnovation is to store specific innovation
@@ -32,6 +34,10 @@ type Innovation struct {
 	NewTraitNum int
 	// If a new node was created, this is its node_id
 	NewNodeId int
+	// If a new node was created, this is its activation function
+	NewNodeActivation math.NodeActivationType
+	// If a new node was created, this is its aggregation function
+	NewNodeAggregation math.NodeAggregationType
 
 	// If a new node was created, this is the innovation number of the gene's link it is being stuck inside
 	OldInnovNum int64
@@ -43,16 +49,19 @@ type Innovation struct {
 	innovationType innovationType
 }
 
-// NewInnovationForNode is a constructor for the new node case
+// NewInnovationForNode is a constructor for the new node case. The new node has default activation and aggregation
+// functions.
 func NewInnovationForNode(nodeInId, nodeOutId int, innovationNum1, innovationNum2 int64, newNodeId int, oldInnovNum int64) *Innovation {
 	return &Innovation{
-		innovationType: newNodeInnType,
-		InNodeId:       nodeInId,
-		OutNodeId:      nodeOutId,
-		InnovationNum:  innovationNum1,
-		InnovationNum2: innovationNum2,
-		NewNodeId:      newNodeId,
-		OldInnovNum:    oldInnovNum,
+		innovationType:     newNodeInnType,
+		InNodeId:           nodeInId,
+		OutNodeId:          nodeOutId,
+		InnovationNum:      innovationNum1,
+		InnovationNum2:     innovationNum2,
+		NewNodeId:          newNodeId,
+		OldInnovNum:        oldInnovNum,
+		NewNodeActivation:  math.SigmoidSteepenedActivation,
+		NewNodeAggregation: math.SumAggregation,
 	}
 }
 
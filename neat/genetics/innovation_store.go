@@ -0,0 +1,267 @@
+package genetics
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// InnovationsFinder is an optional extension of the InnovationsObserver which provides indexed lookup of the
+// already occurred innovations. When the InnovationsObserver passed to the mutators implements it, the lookup is
+// done in constant time instead of linear scan over all known innovations.
+type InnovationsFinder interface {
+	// FindLinkInnovation is to find the innovation of new link between given nodes
+	FindLinkInnovation(inNodeId, outNodeId int, recurrent bool) (*Innovation, bool)
+	// FindNodeInnovation is to find the innovation of new node inserted into the link with given innovation number
+	// between given nodes
+	FindNodeInnovation(inNodeId, outNodeId int, oldInnovNum int64) (*Innovation, bool)
+}
+
+// innovationKey is the key to index innovations by. The node innovations are additionally distinguished by the
+// innovation number of the split gene and link innovations by their recurrence.
+type innovationKey struct {
+	innovationType innovationType
+	inNodeId       int
+	outNodeId      int
+	isRecurrent    bool
+	oldInnovNum    int64
+}
+
+func keyForInnovation(innovation *Innovation) innovationKey {
+	key := innovationKey{
+		innovationType: innovation.innovationType,
+		inNodeId:       innovation.InNodeId,
+		outNodeId:      innovation.OutNodeId,
+	}
+	if innovation.innovationType == newNodeInnType {
+		key.oldInnovNum = innovation.OldInnovNum
+	} else {
+		key.isRecurrent = innovation.IsRecurrent
+	}
+	return key
+}
+
+// InnovationsStore is the run-wide database of innovations. It implements both InnovationsObserver and
+// InnovationsFinder and is safe for concurrent use. Unlike the per-generation list of innovations, the store can be
+// kept for the whole evolutionary run, so that the same structural mutation gets the same innovation number
+// whenever it occurs. The store can be serialized with Write and restored with ReadInnovationsStore.
+type InnovationsStore struct {
+	// The next innovation number
+	nextInnovNum int64
+	// The stored innovations in order of arrival
+	innovations []Innovation
+	// The index of innovations in the list by their key
+	index map[innovationKey]int
+	// The mutex to guard against concurrent modifications
+	mutex sync.RWMutex
+}
+
+// NewInnovationsStore creates new empty innovations store. The first innovation number returned by
+// NextInnovationNumber will be lastInnovNum + 1.
+func NewInnovationsStore(lastInnovNum int64) *InnovationsStore {
+	return &InnovationsStore{
+		nextInnovNum: lastInnovNum,
+		innovations:  make([]Innovation, 0),
+		index:        make(map[innovationKey]int),
+	}
+}
+
+// StoreInnovation stores given innovation. If innovation with the same key already stored, the first one is kept
+// in the index.
+func (s *InnovationsStore) StoreInnovation(innovation Innovation) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	key := keyForInnovation(&innovation)
+	if _, ok := s.index[key]; !ok {
+		s.index[key] = len(s.innovations)
+	}
+	s.innovations = append(s.innovations, innovation)
+}
+
+// Innovations returns copy of the list of stored innovations
+func (s *InnovationsStore) Innovations() []Innovation {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	innovations := make([]Innovation, len(s.innovations))
+	copy(innovations, s.innovations)
+	return innovations
+}
+
+// NextInnovationNumber returns the next unique global innovation number
+func (s *InnovationsStore) NextInnovationNumber() int64 {
+	return atomic.AddInt64(&s.nextInnovNum, 1)
+}
+
+// LastInnovationNumber returns the last innovation number issued by this store
+func (s *InnovationsStore) LastInnovationNumber() int64 {
+	return atomic.LoadInt64(&s.nextInnovNum)
+}
+
+// FindLinkInnovation is to find the innovation of new link between given nodes
+func (s *InnovationsStore) FindLinkInnovation(inNodeId, outNodeId int, recurrent bool) (*Innovation, bool) {
+	return s.find(innovationKey{
+		innovationType: newLinkInnType,
+		inNodeId:       inNodeId,
+		outNodeId:      outNodeId,
+		isRecurrent:    recurrent,
+	})
+}
+
+// FindNodeInnovation is to find the innovation of new node inserted into the link with given innovation number
+// between given nodes
+func (s *InnovationsStore) FindNodeInnovation(inNodeId, outNodeId int, oldInnovNum int64) (*Innovation, bool) {
+	return s.find(innovationKey{
+		innovationType: newNodeInnType,
+		inNodeId:       inNodeId,
+		outNodeId:      outNodeId,
+		oldInnovNum:    oldInnovNum,
+	})
+}
+
+// Reset removes all stored innovations, but keeps the innovation number counter
+func (s *InnovationsStore) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.innovations = make([]Innovation, 0)
+	s.index = make(map[innovationKey]int)
+}
+
+// Size returns the number of stored innovations
+func (s *InnovationsStore) Size() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.innovations)
+}
+
+func (s *InnovationsStore) find(key innovationKey) (*Innovation, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if idx, ok := s.index[key]; ok {
+		innovation := s.innovations[idx]
+		return &innovation, true
+	}
+	return nil, false
+}
+
+// updateLastInnovationNumber is to make sure that innovation numbers issued by this store will be greater than
+// the provided one
+func (s *InnovationsStore) updateLastInnovationNumber(lastInnovNum int64) {
+	for {
+		current := atomic.LoadInt64(&s.nextInnovNum)
+		if current >= lastInnovNum || atomic.CompareAndSwapInt64(&s.nextInnovNum, current, lastInnovNum) {
+			return
+		}
+	}
+}
+
+// Write is to write this store into the provided writer using plain text encoding
+func (s *InnovationsStore) Write(w io.Writer) error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if _, err := fmt.Fprintf(w, "innovationsstart %d\n", atomic.LoadInt64(&s.nextInnovNum)); err != nil {
+		return err
+	}
+	for _, inn := range s.innovations {
+		if _, err := fmt.Fprintf(w, "innovation %d %d %d %d %d %g %d %d %d %t %d %d\n",
+			inn.innovationType, inn.InNodeId, inn.OutNodeId, inn.InnovationNum, inn.InnovationNum2,
+			inn.NewWeight, inn.NewTraitNum, inn.NewNodeId, inn.OldInnovNum, inn.IsRecurrent,
+			inn.NewNodeActivation, inn.NewNodeAggregation); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "innovationsend %d\n", len(s.innovations))
+	return err
+}
+
+// ReadInnovationsStore reads innovations store from the provided reader. The data is expected to be in the format
+// produced by InnovationsStore.Write
+func ReadInnovationsStore(r io.Reader) (*InnovationsStore, error) {
+	var store *InnovationsStore
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanLines)
+	for scanner.Scan() {
+		line := scanner.Text()
+		parts := strings.SplitN(line, " ", 2)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("line: [%s] can not be split when reading innovations", line)
+		}
+		switch parts[0] {
+		case "innovationsstart":
+			var lastInnovNum int64
+			if _, err := fmt.Sscanf(parts[1], "%d", &lastInnovNum); err != nil {
+				return nil, errors.Wrapf(err, "failed to read last innovation number from: %s", line)
+			}
+			store = NewInnovationsStore(lastInnovNum)
+		case "innovation":
+			if store == nil {
+				return nil, errors.New("innovation found before the start of innovations block")
+			}
+			inn := Innovation{}
+			if _, err := fmt.Sscanf(parts[1], "%d %d %d %d %d %g %d %d %d %t",
+				&inn.innovationType, &inn.InNodeId, &inn.OutNodeId, &inn.InnovationNum, &inn.InnovationNum2,
+				&inn.NewWeight, &inn.NewTraitNum, &inn.NewNodeId, &inn.OldInnovNum, &inn.IsRecurrent); err != nil {
+				return nil, errors.Wrapf(err, "failed to read innovation from: %s", line)
+			}
+			// the functions of the new node are optional to support innovations written without them
+			if fields := strings.Fields(parts[1]); len(fields) > 10 {
+				if _, err := fmt.Sscanf(strings.Join(fields[10:], " "), "%d %d",
+					&inn.NewNodeActivation, &inn.NewNodeAggregation); err != nil {
+					return nil, errors.Wrapf(err, "failed to read new node functions of innovation from: %s", line)
+				}
+			} else if inn.innovationType == newNodeInnType {
+				inn.NewNodeActivation = math.SigmoidSteepenedActivation
+			}
+			store.StoreInnovation(inn)
+		case "innovationsend":
+			if store == nil {
+				return nil, errors.New("end of innovations block found before its start")
+			}
+			return store, nil
+		default:
+			return nil, fmt.Errorf("unexpected line: [%s] found when reading innovations", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("innovations block is not terminated")
+}
+
+// findLinkInnovation is to find the link innovation using indexed lookup if supported by observer or by linear scan
+// otherwise
+func findLinkInnovation(innovations InnovationsObserver, inNodeId, outNodeId int, recurrent bool) (*Innovation, bool) {
+	if finder, ok := innovations.(InnovationsFinder); ok {
+		return finder.FindLinkInnovation(inNodeId, outNodeId, recurrent)
+	}
+	for _, inn := range innovations.Innovations() {
+		if inn.innovationType == newLinkInnType &&
+			inn.InNodeId == inNodeId &&
+			inn.OutNodeId == outNodeId &&
+			inn.IsRecurrent == recurrent {
+			return &inn, true
+		}
+	}
+	return nil, false
+}
+
+// findNodeInnovation is to find the node innovation using indexed lookup if supported by observer or by linear scan
+// otherwise
+func findNodeInnovation(innovations InnovationsObserver, inNodeId, outNodeId int, oldInnovNum int64) (*Innovation, bool) {
+	if finder, ok := innovations.(InnovationsFinder); ok {
+		return finder.FindNodeInnovation(inNodeId, outNodeId, oldInnovNum)
+	}
+	for _, inn := range innovations.Innovations() {
+		if inn.innovationType == newNodeInnType &&
+			inn.InNodeId == inNodeId &&
+			inn.OutNodeId == outNodeId &&
+			inn.OldInnovNum == oldInnovNum {
+			return &inn, true
+		}
+	}
+	return nil, false
+}
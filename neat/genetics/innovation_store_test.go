@@ -0,0 +1,158 @@
+package genetics
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"strings"
+	"testing"
+)
+
+func TestInnovationsStore_FindLinkInnovation(t *testing.T) {
+	store := NewInnovationsStore(10)
+	innovNum := store.NextInnovationNumber()
+	assert.EqualValues(t, 11, innovNum)
+
+	store.StoreInnovation(*NewInnovationForRecurrentLink(1, 2, innovNum, 0.5, 1, false))
+	store.StoreInnovation(*NewInnovationForRecurrentLink(1, 2, store.NextInnovationNumber(), 1.5, 2, true))
+
+	inn, found := store.FindLinkInnovation(1, 2, false)
+	require.True(t, found)
+	assert.EqualValues(t, 11, inn.InnovationNum)
+	assert.Equal(t, 0.5, inn.NewWeight)
+
+	inn, found = store.FindLinkInnovation(1, 2, true)
+	require.True(t, found)
+	assert.EqualValues(t, 12, inn.InnovationNum)
+	assert.True(t, inn.IsRecurrent)
+
+	_, found = store.FindLinkInnovation(2, 1, false)
+	assert.False(t, found)
+
+	// node innovation between the same nodes must not be returned
+	_, found = store.FindNodeInnovation(1, 2, 0)
+	assert.False(t, found)
+}
+
+func TestInnovationsStore_FindNodeInnovation(t *testing.T) {
+	store := NewInnovationsStore(0)
+	store.StoreInnovation(*NewInnovationForNode(1, 2, 3, 4, 5, 1))
+	store.StoreInnovation(*NewInnovationForNode(1, 2, 6, 7, 8, 2))
+
+	inn, found := store.FindNodeInnovation(1, 2, 2)
+	require.True(t, found)
+	assert.Equal(t, 8, inn.NewNodeId)
+	assert.EqualValues(t, 6, inn.InnovationNum)
+	assert.EqualValues(t, 7, inn.InnovationNum2)
+
+	_, found = store.FindNodeInnovation(1, 2, 3)
+	assert.False(t, found)
+	_, found = store.FindLinkInnovation(1, 2, false)
+	assert.False(t, found)
+}
+
+func TestInnovationsStore_Reset(t *testing.T) {
+	store := NewInnovationsStore(0)
+	store.StoreInnovation(*NewInnovationForLink(1, 2, store.NextInnovationNumber(), 0.5, 1))
+	assert.Equal(t, 1, store.Size())
+
+	store.Reset()
+	assert.Equal(t, 0, store.Size())
+	assert.Len(t, store.Innovations(), 0)
+	_, found := store.FindLinkInnovation(1, 2, false)
+	assert.False(t, found)
+	// the numbering must continue
+	assert.EqualValues(t, 2, store.NextInnovationNumber())
+}
+
+func TestInnovationsStore_WriteRead(t *testing.T) {
+	store := NewInnovationsStore(0)
+	store.StoreInnovation(*NewInnovationForLink(1, 2, store.NextInnovationNumber(), -0.5, 1))
+	store.StoreInnovation(*NewInnovationForRecurrentLink(3, 3, store.NextInnovationNumber(), 2.25, 2, true))
+	nodeInnovation := NewInnovationForNode(1, 2, store.NextInnovationNumber(), store.NextInnovationNumber(), 5, 1)
+	nodeInnovation.NewNodeActivation = math.LinearActivation
+	nodeInnovation.NewNodeAggregation = math.MaxAggregation
+	store.StoreInnovation(*nodeInnovation)
+
+	outBuf := bytes.NewBufferString("")
+	err := store.Write(outBuf)
+	require.NoError(t, err)
+
+	restored, err := ReadInnovationsStore(outBuf)
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	assert.Equal(t, store.Innovations(), restored.Innovations())
+	assert.Equal(t, store.LastInnovationNumber(), restored.LastInnovationNumber())
+
+	inn, found := restored.FindNodeInnovation(1, 2, 1)
+	require.True(t, found)
+	assert.Equal(t, 5, inn.NewNodeId)
+	assert.Equal(t, math.LinearActivation, inn.NewNodeActivation)
+	assert.Equal(t, math.MaxAggregation, inn.NewNodeAggregation)
+	assert.EqualValues(t, 5, restored.NextInnovationNumber())
+}
+
+func TestReadInnovationsStore_withoutNodeFunctions(t *testing.T) {
+	data := "innovationsstart 4\ninnovation 1 1 2 3 4 0 0 5 1 false\ninnovationsend 1\n"
+	store, err := ReadInnovationsStore(strings.NewReader(data))
+	require.NoError(t, err)
+	inn, found := store.FindNodeInnovation(1, 2, 1)
+	require.True(t, found)
+	assert.Equal(t, 5, inn.NewNodeId)
+	assert.Equal(t, math.SigmoidSteepenedActivation, inn.NewNodeActivation)
+	assert.Equal(t, math.SumAggregation, inn.NewNodeAggregation)
+}
+
+func TestInnovationsStore_Write_writeError(t *testing.T) {
+	errorWriter := ErrorWriter(1)
+	store := NewInnovationsStore(0)
+	err := store.Write(&errorWriter)
+	assert.EqualError(t, err, alwaysErrorText)
+}
+
+func TestReadInnovationsStore_error(t *testing.T) {
+	testCases := map[string]string{
+		"not terminated":           "innovationsstart 1\n",
+		"no start":                 "innovation 2 1 2 1 0 0.5 1 0 0 false\n",
+		"wrong line":               "innovationsstart 1\ngene 1 1 4 1.5 false 1 0 true\n",
+		"malformed record":         "innovationsstart 1\ninnovation 2 1 two\ninnovationsend 1\n",
+		"malformed node functions": "innovationsstart 1\ninnovation 1 1 2 3 4 0 0 5 1 false linear\ninnovationsend 1\n",
+	}
+	for name, data := range testCases {
+		t.Run(name, func(t *testing.T) {
+			store, err := ReadInnovationsStore(strings.NewReader(data))
+			assert.Error(t, err)
+			assert.Nil(t, store)
+		})
+	}
+}
+
+func TestPopulation_ReadInnovations(t *testing.T) {
+	conf := neat.Options{
+		CompatThreshold: 0.5,
+	}
+	pop, err := ReadPopulation(strings.NewReader(popStr), &conf)
+	require.NoError(t, err, "failed to create population")
+	// the last gene innovation in population is 3
+	assert.EqualValues(t, 3, pop.innovations.LastInnovationNumber())
+
+	store := NewInnovationsStore(20)
+	store.StoreInnovation(*NewInnovationForLink(1, 2, store.NextInnovationNumber(), 0.5, 1))
+	storeBuf := bytes.NewBufferString("")
+	err = store.Write(storeBuf)
+	require.NoError(t, err)
+
+	err = pop.ReadInnovations(storeBuf)
+	require.NoError(t, err)
+	inn, found := pop.FindLinkInnovation(1, 2, false)
+	require.True(t, found)
+	assert.EqualValues(t, 21, inn.InnovationNum)
+	assert.EqualValues(t, 22, pop.NextInnovationNumber())
+
+	outBuf := bytes.NewBufferString("")
+	err = pop.WriteInnovations(outBuf)
+	require.NoError(t, err)
+	assert.Equal(t, "innovationsstart 22\ninnovation 2 1 2 21 0 0.5 1 0 0 false 0 0\ninnovationsend 1\n", outBuf.String())
+}
@@ -24,6 +24,9 @@ type Organism struct {
 	Error float64
 	// Win marker (if needed for a particular task)
 	IsWinner bool
+	// The flag to indicate that Fitness is the estimated value rather than the result of the true evaluation,
+	// e.g., predicted by the surrogate model
+	FitnessEstimated bool
 
 	// The Organism's genotype
 	Genotype *Genome
@@ -56,6 +59,8 @@ type Organism struct {
 	isPopulationChampion bool
 	// Marks the duplicate child of a champion (for tracking purposes)
 	isPopulationChampionChild bool
+	// Marks one of the best in population to be copied unmodified into the next generation
+	isPopulationElite bool
 
 	// DEBUG variable - highest fitness of champ
 	highestFitness float64
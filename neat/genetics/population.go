@@ -35,10 +35,16 @@ type Population struct {
 	Variance    float64
 	StandardDev float64
 
-	// For holding the genetic innovations of the newest generation
-	innovations []Innovation
-	// The next innovation number for population
-	nextInnovNum int64
+	// The outcome of the elitism policy applied during the last epoch
+	EliteReport ElitismReport
+
+	// The best genome found so far and its fitness, tracked when neat.Options.KeepBestEver is set
+	bestEverGenome  *Genome
+	bestEverFitness float64
+
+	// For holding the genetic innovations. By default, only innovations of the newest generation are kept,
+	// unless neat.Options.PersistentInnovations is set.
+	innovations *InnovationsStore
 	// The next ID for new node in population
 	nextNodeId int32
 
@@ -54,11 +60,15 @@ type reproductionResult struct {
 	speciesId    int    // the ID of species used for reproduction
 }
 
-// NewPopulation constructs off of a single spawning Genome
+// NewPopulation constructs off of a single spawning Genome. The spawning Genome must satisfy topology restrictions
+// and complexity caps defined by the options.
 func NewPopulation(g *Genome, opts *neat.Options) (*Population, error) {
 	if opts.PopSize <= 0 {
 		return nil, fmt.Errorf("wrong population size in the context: %d", opts.PopSize)
 	}
+	if err := g.validateConstraints(opts); err != nil {
+		return nil, errors.Wrap(err, "invalid seed genome")
+	}
 
 	pop := newPopulation()
 	err := pop.spawn(g, opts)
@@ -82,6 +92,9 @@ func NewPopulationRandom(in, out, maxHidden int, recurrent bool, linkProb float6
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create random population")
 		}
+		if err = gen.validateConstraints(opts); err != nil {
+			return nil, errors.Wrap(err, "invalid random genome")
+		}
 		org, err := NewOrganism(0.0, gen, 1)
 		if err != nil {
 			return nil, err
@@ -89,7 +102,7 @@ func NewPopulationRandom(in, out, maxHidden int, recurrent bool, linkProb float6
 		pop.Organisms = append(pop.Organisms, org)
 	}
 	pop.nextNodeId = int32(in + out + maxHidden + 1)
-	pop.nextInnovNum = int64((in+out+maxHidden)*(in+out+maxHidden) + 1)
+	pop.innovations = NewInnovationsStore(int64((in+out+maxHidden)*(in+out+maxHidden) + 1))
 
 	err := pop.speciate(opts.NeatContext(), pop.Organisms)
 	if err != nil {
@@ -99,6 +112,86 @@ func NewPopulationRandom(in, out, maxHidden int, recurrent bool, linkProb float6
 	return pop, nil
 }
 
+// NewPopulationFromGenomes creates population from the provided genomes, e.g., the ones saved during previous
+// evolutionary run. The genomes are copied, the new population is speciated, and its node and innovation counters
+// are set past the maximal ones found among the genomes, so that evolution can be resumed. Each genome must
+// satisfy topology restrictions and complexity caps defined by the options. If the number of genomes differs from
+// the population size defined by the options, the excess genomes are dropped, or the population is filled up with
+// copies of the genomes with mutated link weights.
+func NewPopulationFromGenomes(genomes []*Genome, opts *neat.Options) (*Population, error) {
+	if len(genomes) == 0 {
+		return nil, errors.New("no genomes provided to create population")
+	}
+	seeds := make([]*Genome, len(genomes))
+	lastGenomeId := 0
+	for i, g := range genomes {
+		if err := g.validateConstraints(opts); err != nil {
+			return nil, errors.Wrap(err, "invalid seed genome")
+		}
+		gnome, err := g.duplicate(g.Id)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to copy seed genome: %d", g.Id)
+		}
+		seeds[i] = gnome
+		if g.Id > lastGenomeId {
+			lastGenomeId = g.Id
+		}
+	}
+	if opts.PopSize > 0 && len(seeds) > opts.PopSize {
+		neat.WarnLog(fmt.Sprintf("POPULATION: The number of seed genomes: %d exceeds population size: %d, "+
+			"the excess genomes are dropped", len(seeds), opts.PopSize))
+		seeds = seeds[:opts.PopSize]
+	} else if opts.PopSize > 0 && len(seeds) < opts.PopSize {
+		neat.WarnLog(fmt.Sprintf("POPULATION: The number of seed genomes: %d is less than population size: %d, "+
+			"the population is filled up with mutated copies of the seed genomes", len(seeds), opts.PopSize))
+		seedsNum := len(seeds)
+		for count := 0; len(seeds) < opts.PopSize; count++ {
+			// make duplicate of the seed genome with introduced initial mutations
+			lastGenomeId++
+			newGenome, err := seeds[count%seedsNum].duplicate(lastGenomeId)
+			if err != nil {
+				return nil, err
+			}
+			if _, err = newGenome.mutateLinkWeights(1.0, 1.0, gaussianMutator, opts); err != nil {
+				return nil, err
+			}
+			seeds = append(seeds, newGenome)
+		}
+	}
+	return newPopulationFromGenomes(seeds, opts)
+}
+
+// newPopulationFromGenomes creates population which owns the provided genomes as is
+func newPopulationFromGenomes(genomes []*Genome, opts *neat.Options) (*Population, error) {
+	if len(genomes) == 0 {
+		return nil, errors.New("no genomes provided to create population")
+	}
+	pop := newPopulation()
+	lastNodeId, lastInnovNum := 0, int64(0)
+	for _, g := range genomes {
+		org, err := NewOrganism(0.0, g, 1)
+		if err != nil {
+			return nil, err
+		}
+		pop.Organisms = append(pop.Organisms, org)
+
+		if nodeId := g.maxNodeId(); nodeId > lastNodeId {
+			lastNodeId = nodeId
+		}
+		if innovNum := g.maxInnovationNum(); innovNum > lastInnovNum {
+			lastInnovNum = innovNum
+		}
+	}
+	// Keep a record of the innovation and node number we are on
+	pop.nextNodeId = int32(lastNodeId + 1)
+	pop.innovations.updateLastInnovationNumber(lastInnovNum)
+
+	if err := pop.speciate(opts.NeatContext(), pop.Organisms); err != nil {
+		return nil, err
+	}
+	return pop, nil
+}
+
 // Verify is to run verification on all Genomes in this Population (Debugging)
 func (p *Population) Verify() (bool, error) {
 	res := true
@@ -120,6 +213,7 @@ func newPopulation() *Population {
 		EpochsHighestLastChanged: 0,
 		Species:                  make([]*Species, 0),
 		Organisms:                make([]*Organism, 0),
+		innovations:              NewInnovationsStore(0),
 		mutex:                    &sync.Mutex{},
 	}
 }
@@ -129,30 +223,60 @@ func (p *Population) NextNodeId() int {
 }
 
 func (p *Population) NextInnovationNumber() int64 {
-	return atomic.AddInt64(&p.nextInnovNum, 1)
+	return p.innovations.NextInnovationNumber()
 }
 
 func (p *Population) StoreInnovation(innovation Innovation) {
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-	p.innovations = append(p.innovations, innovation)
+	p.innovations.StoreInnovation(innovation)
 }
 
 func (p *Population) Innovations() []Innovation {
+	return p.innovations.Innovations()
+}
+
+func (p *Population) FindLinkInnovation(inNodeId, outNodeId int, recurrent bool) (*Innovation, bool) {
+	return p.innovations.FindLinkInnovation(inNodeId, outNodeId, recurrent)
+}
+
+func (p *Population) FindNodeInnovation(inNodeId, outNodeId int, oldInnovNum int64) (*Innovation, bool) {
+	return p.innovations.FindNodeInnovation(inNodeId, outNodeId, oldInnovNum)
+}
+
+// InnovationsStore returns the store holding innovations of this population
+func (p *Population) InnovationsStore() *InnovationsStore {
 	return p.innovations
 }
 
 // spawn creates a population from Genome g. The new Population will have the same topology as g
-// with link weights slightly perturbed from g's
+// with link weights slightly perturbed from g's. In the FS-NEAT mode, the links from input nodes of g are
+// replaced with one randomly selected input to output link for each new genome.
 func (p *Population) spawn(g *Genome, opts *neat.Options) (err error) {
+	// Keep a record of the innovation and node number we are on
+	if nextNodeId, err := g.getLastNodeId(); err != nil {
+		return err
+	} else {
+		p.nextNodeId = int32(nextNodeId + 1)
+	}
+	if nextInnovNum, err := g.getNextGeneInnovNum(); err != nil {
+		return err
+	} else {
+		// to compensate +1 in gene next innovation
+		p.innovations.updateLastInnovationNumber(nextInnovNum - 1)
+	}
+
 	for count := 0; count < opts.PopSize; count++ {
 		// make genome duplicate for new organism
 		newGenome, err := g.duplicate(count)
 		if err != nil {
 			return err
 		}
+		if opts.FeatureSelection {
+			if err = newGenome.selectFeature(p, opts); err != nil {
+				return err
+			}
+		}
 		// introduce initial mutations
-		if _, err = newGenome.mutateLinkWeights(1.0, 1.0, gaussianMutator); err != nil {
+		if _, err = newGenome.mutateLinkWeights(1.0, 1.0, gaussianMutator, opts); err != nil {
 			return err
 		}
 		// create organism for new genome
@@ -162,18 +286,6 @@ func (p *Population) spawn(g *Genome, opts *neat.Options) (err error) {
 			p.Organisms = append(p.Organisms, newOrganism)
 		}
 	}
-	// Keep a record of the innovation and node number we are on
-	if nextNodeId, err := g.getLastNodeId(); err != nil {
-		return err
-	} else {
-		p.nextNodeId = int32(nextNodeId + 1)
-	}
-	if p.nextInnovNum, err = g.getNextGeneInnovNum(); err != nil {
-		return err
-	} else {
-		// to compensate +1 in gene next innovation
-		p.nextInnovNum -= 1
-	}
 
 	// Separate the new Population into species
 	err = p.speciate(opts.NeatContext(), p.Organisms)
@@ -181,33 +293,6 @@ func (p *Population) spawn(g *Genome, opts *neat.Options) (err error) {
 	return err
 }
 
-// Check to see if the best species died somehow. We don't want this to happen!!!
-// N.B. the mutated offspring of best species may be added to other more compatible species and as result
-// the best species from previous generation will be removed, but their offspring still be alive.
-// Returns error if best species died.
-func (p *Population) checkBestSpeciesAlive(bestSpeciesId int, bestSpeciesReproduced bool) error {
-	bestOk := false
-	var bestSpMaxFitness float64
-	for _, currSpecies := range p.Species {
-		if neat.LogLevel == neat.LogLevelDebug {
-			neat.DebugLog(fmt.Sprintf("POPULATION: %d <> %d\n", currSpecies.Id, bestSpeciesId))
-		}
-
-		if currSpecies.Id == bestSpeciesId {
-			bestOk = true
-			bestSpMaxFitness = currSpecies.MaxFitnessEver
-			break
-		}
-	}
-	if !bestOk && !bestSpeciesReproduced {
-		return errors.New("best species died without offspring")
-	} else if neat.LogLevel == neat.LogLevelDebug {
-		neat.DebugLog(fmt.Sprintf("POPULATION: The best survived species Id: %d, max fitness ever: %f",
-			bestSpeciesId, bestSpMaxFitness))
-	}
-	return nil
-}
-
 // speciate separates given organisms into species of this population by checking compatibilities against a threshold.
 // Any organism that is not compatible with the first organism in any existing species becomes a new species.
 func (p *Population) speciate(ctx context.Context, organisms []*Organism) error {
@@ -244,7 +329,7 @@ func (p *Population) speciate(ctx context.Context, organisms []*Organism) error
 				compOrg := currSpecies.firstOrganism()
 				// compare current organism with first organism in current specie
 				if compOrg != nil {
-					currCompat := currOrg.Genotype.compatibility(compOrg.Genotype, opts)
+					currCompat := currOrg.Genotype.Compatibility(compOrg.Genotype, opts)
 					if currCompat < opts.CompatThreshold && currCompat < bestCompatValue {
 						bestCompatible = currSpecies
 						bestCompatValue = currCompat
@@ -22,6 +22,8 @@ type SequentialPopulationEpochExecutor struct {
 	sortedSpecies         []*Species
 	bestSpeciesReproduced bool
 	bestSpeciesId         int
+	// the flag to indicate that the best ever genome should be re-inserted after reproduction
+	reinsertBestEver bool
 }
 
 func (s *SequentialPopulationEpochExecutor) NextEpoch(ctx context.Context, generation int, population *Population) error {
@@ -109,8 +111,15 @@ func (s *SequentialPopulationEpochExecutor) prepareForReproduction(ctx context.C
 		p.giveBabiesToTheBest(s.sortedSpecies, opts)
 	}
 
+	// Mark elites to be preserved according to the elitism policy
+	reinsertBestEver, err := p.prepareElitism(s.sortedSpecies, opts)
+	if err != nil {
+		return err
+	}
+	s.reinsertBestEver = reinsertBestEver
+
 	// Kill off all Organisms marked for death. The remainder will be allowed to reproduce.
-	err := p.purgeOrganisms()
+	err = p.purgeOrganisms()
 	return err
 }
 
@@ -140,6 +149,12 @@ func (s *SequentialPopulationEpochExecutor) reproduce(ctx context.Context, gener
 		babies = append(babies, repBabies...)
 	}
 
+	// complete elitism policy
+	babies, err := p.completeElitism(babies, s.reinsertBestEver, generation)
+	if err != nil {
+		return err
+	}
+
 	// sanity check - make sure that population size keep the same
 	if len(babies) != opts.PopSize {
 		return fmt.Errorf("progeny size after reproduction cycle dimished, expected: [%d], but got: [%d]",
@@ -147,7 +162,7 @@ func (s *SequentialPopulationEpochExecutor) reproduce(ctx context.Context, gener
 	}
 
 	// speciate fresh progeny
-	err := p.speciate(ctx, babies)
+	err = p.speciate(ctx, babies)
 
 	neat.DebugLog("POPULATION: >>>>> Reproduction Complete")
 
@@ -155,7 +170,12 @@ func (s *SequentialPopulationEpochExecutor) reproduce(ctx context.Context, gener
 }
 
 // finalizeReproduction is to finalizeReproduction reproduction cycle
-func (s *SequentialPopulationEpochExecutor) finalizeReproduction(_ context.Context, pop *Population) error {
+func (s *SequentialPopulationEpochExecutor) finalizeReproduction(ctx context.Context, pop *Population) error {
+	opts, found := neat.FromContext(ctx)
+	if !found {
+		return neat.ErrNEATOptionsNotFound
+	}
+
 	// Destroy and remove the old generation from the organisms and species
 	err := pop.purgeOldGeneration(s.bestSpeciesId)
 	if err != nil {
@@ -166,11 +186,13 @@ func (s *SequentialPopulationEpochExecutor) finalizeReproduction(_ context.Conte
 	// As this happens, create master organism list for the new generation.
 	pop.purgeOrAgeSpecies()
 
-	// Remove the innovations of the current generation
-	pop.innovations = make([]Innovation, 0)
+	// Remove the innovations of the current generation unless they should be kept for the whole run
+	if !opts.PersistentInnovations {
+		pop.innovations.Reset()
+	}
 
-	// Check to see if the best species died somehow. We don't want this to happen!!!
-	err = pop.checkBestSpeciesAlive(s.bestSpeciesId, s.bestSpeciesReproduced)
+	// Check to see if the best species or elites died somehow. We don't want this to happen!!!
+	err = pop.checkElitism(s.bestSpeciesId, s.bestSpeciesReproduced, opts)
 
 	// DEBUG: Checking the top organism's duplicate in the next gen
 	// This prints the champ's child to the screen
@@ -283,6 +305,12 @@ func (p *ParallelPopulationEpochExecutor) reproduce(ctx context.Context, generat
 		}
 	}
 
+	// complete elitism policy
+	babies, err := pop.completeElitism(babies, p.sequential.reinsertBestEver, generation)
+	if err != nil {
+		return err
+	}
+
 	// sanity check - make sure that population size keep the same
 	if len(babies) != opts.PopSize {
 		return fmt.Errorf("progeny size after reproduction cycle dimished, expected: [%d], but got: [%d]",
@@ -290,7 +318,7 @@ func (p *ParallelPopulationEpochExecutor) reproduce(ctx context.Context, generat
 	}
 
 	// speciate fresh progeny
-	err := pop.speciate(ctx, babies)
+	err = pop.speciate(ctx, babies)
 
 	neat.DebugLog("POPULATION: >>>>> Reproduction Complete")
 
@@ -64,3 +64,44 @@ func TestPopulationEpochExecutor_NextEpoch(t *testing.T) {
 	err = parallelExecutorNextEpoch(pop, conf)
 	assert.NoError(t, err, "failed to run parallel epoch executor")
 }
+
+func TestPopulationEpochExecutor_NextEpoch_persistentInnovations(t *testing.T) {
+	rand.Seed(42)
+	in, out, maxHidden, n := 3, 2, 15, 3
+	linkProb := 0.8
+	conf := &neat.Options{
+		CompatThreshold:    0.5,
+		DropOffAge:         1,
+		PopSize:            30,
+		MutateAddNodeProb:  0.5,
+		MutateAddLinkProb:  0.5,
+		NewLinkTries:       10,
+		NodeActivators:     []math.NodeActivationType{math.GaussianBipolarActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	gen, err := newGenomeRand(1, in, out, n, maxHidden, false, linkProb, conf)
+	require.NoError(t, err, "failed to create random genome")
+
+	pop, err := NewPopulation(gen, conf)
+	require.NoError(t, err, "failed to create population")
+
+	// innovations of generation are discarded by default
+	ex := SequentialPopulationEpochExecutor{}
+	err = ex.NextEpoch(conf.NeatContext(), 1, pop)
+	require.NoError(t, err)
+	assert.Equal(t, 0, pop.InnovationsStore().Size())
+
+	// innovations are accumulated over generations
+	conf.PersistentInnovations = true
+	sizes := make([]int, 0)
+	for i := 2; i < 5; i++ {
+		err = ex.NextEpoch(conf.NeatContext(), i, pop)
+		require.NoError(t, err)
+		sizes = append(sizes, pop.InnovationsStore().Size())
+	}
+	assert.True(t, sizes[0] > 0, "no innovations stored")
+	for i := 1; i < len(sizes); i++ {
+		assert.True(t, sizes[i] >= sizes[i-1], "innovations must not be discarded")
+	}
+}
@@ -4,21 +4,89 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"github.com/pkg/errors"
 	"github.com/yaricom/goNEAT/v4/neat"
 	"io"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 )
 
-// ReadPopulation reads population from provided reader
-func ReadPopulation(ir io.Reader, options *neat.Options) (pop *Population, err error) {
-	pop = newPopulation()
+// ReadPopulation reads population from provided reader. The population is speciated and the node and innovation
+// counters are recovered from the read genomes, so that evolution can be continued from it. The genomes are loaded
+// exactly as written, use NewPopulationFromGenomes to validate them and to adjust the population size.
+func ReadPopulation(ir io.Reader, options *neat.Options) (*Population, error) {
+	genomes, err := readPopulationGenomes(ir)
+	if err != nil {
+		return nil, err
+	}
+	return newPopulationFromGenomes(genomes, options)
+}
+
+// ReadPopulationGenomes reads genomes of the population stored at the given path. The path can point either to the
+// population file produced by Population.Write or to the directory with genome files, one genome per file. In
+// the latter case, only files with genome file extensions (see isGenomeFileName) are read in lexical order, and
+// the encoding of each genome file is resolved from its name.
+func ReadPopulationGenomes(path string) ([]*Genome, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		popFile, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = popFile.Close() }()
+		return readPopulationGenomes(popFile)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	genomes := make([]*Genome, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !isGenomeFileName(entry.Name()) {
+			continue
+		}
+		genome, err := readGenomeFile(filepath.Join(path, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read genome from file: %s", entry.Name())
+		}
+		genomes = append(genomes, genome)
+	}
+	if len(genomes) == 0 {
+		return nil, fmt.Errorf("no genome files found in directory: %s", path)
+	}
+	return genomes, nil
+}
+
+// readGenomeFile reads genome from the file at the given path
+func readGenomeFile(path string) (*Genome, error) {
+	genomeFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = genomeFile.Close() }()
+	reader, err := NewGenomeReader(genomeFile, genomeEncodingFromFileName(genomeFile.Name()))
+	if err != nil {
+		return nil, err
+	}
+	return reader.Read()
+}
+
+// readPopulationGenomes reads all genomes from population data encoded in plain text format
+func readPopulationGenomes(ir io.Reader) ([]*Genome, error) {
+	genomes := make([]*Genome, 0)
 
 	// Loop until file is finished, parsing each line
 	scanner := bufio.NewScanner(ir)
 	scanner.Split(bufio.ScanLines)
 	var outBuff *bytes.Buffer
 	var idCheck int
+	var err error
 	for scanner.Scan() {
 		line := scanner.Text()
 		parts := strings.SplitN(line, " ", 2)
@@ -33,6 +101,9 @@ func ReadPopulation(ir io.Reader, options *neat.Options) (pop *Population, err e
 				return nil, err
 			}
 		case "genomeend":
+			if outBuff == nil {
+				return nil, fmt.Errorf("line: [%s] found before the start of genome", line)
+			}
 			if _, err = fmt.Fprintf(outBuff, "genomeend %d", idCheck); err != nil {
 				return nil, err
 			}
@@ -40,28 +111,8 @@ func ReadPopulation(ir io.Reader, options *neat.Options) (pop *Population, err e
 			if err != nil {
 				return nil, err
 			}
-			// add new organism for read genome
-			if newOrganism, err := NewOrganism(0.0, newGenome, 1); err != nil {
-				return nil, err
-			} else {
-				pop.Organisms = append(pop.Organisms, newOrganism)
-			}
-
-			if lastNodeId, err := newGenome.getLastNodeId(); err == nil {
-				if pop.nextNodeId < int32(lastNodeId) {
-					pop.nextNodeId = int32(lastNodeId + 1)
-				}
-			} else {
-				return nil, err
-			}
+			genomes = append(genomes, newGenome)
 
-			if lastGeneInnovNum, err := newGenome.getNextGeneInnovNum(); err == nil {
-				if pop.nextInnovNum < lastGeneInnovNum {
-					pop.nextInnovNum = lastGeneInnovNum
-				}
-			} else {
-				return nil, err
-			}
 			// clear buffer
 			outBuff = nil
 			idCheck = -1
@@ -70,6 +121,9 @@ func ReadPopulation(ir io.Reader, options *neat.Options) (pop *Population, err e
 			// read all comments and print it
 			neat.InfoLog(line)
 		default:
+			if outBuff == nil {
+				return nil, fmt.Errorf("line: [%s] found outside of genome", line)
+			}
 			// write line to buffer
 			if _, err = fmt.Fprintln(outBuff, line); err != nil {
 				return nil, err
@@ -80,11 +134,25 @@ func ReadPopulation(ir io.Reader, options *neat.Options) (pop *Population, err e
 	if err = scanner.Err(); err != nil {
 		return nil, err
 	}
+	return genomes, nil
+}
 
-	if err = pop.speciate(options.NeatContext(), pop.Organisms); err != nil {
-		return nil, err
+// ReadInnovations reads the innovations store previously saved with WriteInnovations and makes it the innovations
+// store of this population. This allows continuing the same innovation numbering for population restored
+// with ReadPopulation.
+func (p *Population) ReadInnovations(r io.Reader) error {
+	store, err := ReadInnovationsStore(r)
+	if err != nil {
+		return err
 	}
-	return pop, nil
+	store.updateLastInnovationNumber(p.innovations.LastInnovationNumber())
+	p.innovations = store
+	return nil
+}
+
+// WriteInnovations writes the innovations store of this population
+func (p *Population) WriteInnovations(w io.Writer) error {
+	return p.innovations.Write(w)
 }
 
 // Writes given population to a writer
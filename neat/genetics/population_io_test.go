@@ -3,9 +3,14 @@ package genetics
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -93,3 +98,184 @@ func TestPopulation_Write_writeError(t *testing.T) { // first create population
 	err = pop.Write(&errorWriter)
 	assert.EqualError(t, err, alwaysErrorText)
 }
+
+func TestReadPopulation_recoverCounters(t *testing.T) {
+	// the node and gene with maximal IDs are not the last ones
+	genomeStr := "genomestart 1\n" +
+		"trait 1 0.1 0 0 0 0 0 0 0\n" +
+		"node 1 0 1 1\n" +
+		"node 2 0 1 1\n" +
+		"node 3 0 1 3\n" +
+		"node 12 0 0 0\n" +
+		"node 4 0 0 2\n" +
+		"gene 1 1 12 1.5 false 1 0 true\n" +
+		"gene 1 12 4 1.5 false 25 0 true\n" +
+		"gene 1 2 4 2.5 false 2 0 true\n" +
+		"gene 1 3 4 3.5 false 3 0 true\n" +
+		"genomeend 1\n"
+	conf := neat.Options{
+		CompatThreshold: 0.5,
+	}
+	pop, err := ReadPopulation(strings.NewReader(popStr+genomeStr), &conf)
+	require.NoError(t, err, "failed to create population")
+	require.Len(t, pop.Organisms, 3, "wrong population size")
+	assert.True(t, len(pop.Species) > 0, "population must be speciated")
+	assert.EqualValues(t, 13, pop.nextNodeId, "wrong next node ID")
+	assert.EqualValues(t, 26, pop.NextInnovationNumber(), "wrong next innovation number")
+}
+
+func TestReadPopulation_malformed(t *testing.T) {
+	conf := neat.Options{
+		CompatThreshold: 0.5,
+	}
+	pop, err := ReadPopulation(strings.NewReader("node 1 0 1 1\n"), &conf)
+	assert.Error(t, err)
+	assert.Nil(t, pop)
+
+	pop, err = ReadPopulation(strings.NewReader("/* no genomes */\n"), &conf)
+	assert.Error(t, err)
+	assert.Nil(t, pop)
+}
+
+func TestNewPopulationFromGenomes(t *testing.T) {
+	conf := neat.Options{
+		CompatThreshold: 0.5,
+		PopSize:         2,
+	}
+	genomes := []*Genome{buildTestGenome(1), buildTestGenome(2)}
+	pop, err := NewPopulationFromGenomes(genomes, &conf)
+	require.NoError(t, err, "failed to create population")
+	require.Len(t, pop.Organisms, 2, "wrong population size")
+	require.Len(t, pop.Species, 1, "wrong species number")
+	assert.EqualValues(t, 5, pop.nextNodeId, "wrong next node ID")
+	assert.EqualValues(t, 3, pop.innovations.LastInnovationNumber(), "wrong last innovation number")
+
+	// check that genomes are copied
+	for i, org := range pop.Organisms {
+		assert.False(t, org.Genotype == genomes[i], "genome must be copied")
+		assert.Equal(t, genomes[i].Id, org.Genotype.Id)
+	}
+
+	// constraints violation
+	conf.MaxNodes = 3
+	pop, err = NewPopulationFromGenomes(genomes, &conf)
+	assert.Error(t, err)
+	assert.Nil(t, pop)
+
+	// read population is not validated
+	pop, err = ReadPopulation(strings.NewReader(popStr), &conf)
+	require.NoError(t, err, "failed to read population")
+	assert.Len(t, pop.Organisms, 2)
+
+	// no genomes
+	pop, err = NewPopulationFromGenomes(nil, &conf)
+	assert.Error(t, err)
+	assert.Nil(t, pop)
+}
+
+func TestNewPopulationFromGenomes_adjustSize(t *testing.T) {
+	rand.Seed(42)
+	in, out, maxHidden, n := 3, 2, 15, 3
+	conf := &neat.Options{
+		CompatThreshold:    0.5,
+		DropOffAge:         10,
+		SurvivalThresh:     0.2,
+		PopSize:            10,
+		MutateOnlyProb:     0.5,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	gen, err := newGenomeRand(1, in, out, n, maxHidden, false, 0.8, conf)
+	require.NoError(t, err, "failed to create random genome")
+	genomes := make([]*Genome, 6)
+	for i := range genomes {
+		genomes[i], err = gen.duplicate(i + 1)
+		require.NoError(t, err)
+	}
+
+	// the population is filled up to the population size
+	pop, err := NewPopulationFromGenomes(genomes, conf)
+	require.NoError(t, err, "failed to create population")
+	require.Len(t, pop.Organisms, conf.PopSize, "wrong population size")
+	ids := make(map[int]bool)
+	for _, org := range pop.Organisms {
+		assert.False(t, ids[org.Genotype.Id], "genome ID is not unique: %d", org.Genotype.Id)
+		ids[org.Genotype.Id] = true
+	}
+
+	// run one epoch on the seeded population
+	for _, org := range pop.Organisms {
+		org.Fitness = rand.Float64() * 10.0
+	}
+	ex := SequentialPopulationEpochExecutor{}
+	err = ex.NextEpoch(conf.NeatContext(), 1, pop)
+	require.NoError(t, err)
+	assert.Len(t, pop.Organisms, conf.PopSize)
+
+	// read population is not resized
+	buf := bytes.NewBufferString("")
+	for _, g := range genomes {
+		require.NoError(t, g.Write(buf))
+	}
+	pop, err = ReadPopulation(buf, conf)
+	require.NoError(t, err, "failed to read population")
+	assert.Len(t, pop.Organisms, len(genomes))
+
+	// the excess genomes are dropped
+	conf.PopSize = 4
+	pop, err = NewPopulationFromGenomes(genomes, conf)
+	require.NoError(t, err, "failed to create population")
+	require.Len(t, pop.Organisms, conf.PopSize, "wrong population size")
+	for i, org := range pop.Organisms {
+		assert.Equal(t, genomes[i].Id, org.Genotype.Id)
+	}
+}
+
+func TestReadPopulationGenomes(t *testing.T) {
+	dir := t.TempDir()
+
+	// population file
+	popPath := filepath.Join(dir, "population")
+	err := os.WriteFile(popPath, []byte(popStr), os.ModePerm)
+	require.NoError(t, err)
+	genomes, err := ReadPopulationGenomes(popPath)
+	require.NoError(t, err, "failed to read population file")
+	require.Len(t, genomes, 2)
+	assert.Equal(t, 1, genomes[0].Id)
+	assert.Equal(t, 2, genomes[1].Id)
+
+	// directory with genomes
+	genomesDir := filepath.Join(dir, "genomes")
+	err = os.Mkdir(genomesDir, os.ModePerm)
+	require.NoError(t, err)
+	for _, id := range []int{3, 4} {
+		buf := bytes.NewBufferString("")
+		err = buildTestGenome(id).Write(buf)
+		require.NoError(t, err)
+		err = os.WriteFile(filepath.Join(genomesDir, fmt.Sprintf("genome_%d", id)), buf.Bytes(), os.ModePerm)
+		require.NoError(t, err)
+	}
+	// the files which are not genomes are skipped
+	for _, name := range []string{"gen_1.innovations", "genome_3-4.dot", "genome_3-4.cyjs"} {
+		err = os.WriteFile(filepath.Join(genomesDir, name), []byte("not a genome"), os.ModePerm)
+		require.NoError(t, err)
+	}
+	genomes, err = ReadPopulationGenomes(genomesDir)
+	require.NoError(t, err, "failed to read genomes directory")
+	require.Len(t, genomes, 2)
+	assert.Equal(t, 3, genomes[0].Id)
+	assert.Equal(t, 4, genomes[1].Id)
+
+	// empty directory
+	emptyDir := filepath.Join(dir, "empty")
+	err = os.Mkdir(emptyDir, os.ModePerm)
+	require.NoError(t, err)
+	genomes, err = ReadPopulationGenomes(emptyDir)
+	assert.Error(t, err)
+	assert.Nil(t, genomes)
+
+	// missing path
+	genomes, err = ReadPopulationGenomes(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+	assert.Nil(t, genomes)
+}
@@ -25,7 +25,7 @@ func TestNewPopulationRandom(t *testing.T) {
 	require.NotNil(t, pop, "population expected")
 	require.Len(t, pop.Organisms, conf.PopSize, "wrong population size")
 	assert.EqualValues(t, 11, pop.nextNodeId, "wrong next node ID")
-	assert.EqualValues(t, 101, pop.nextInnovNum, "wrong next innovation number")
+	assert.EqualValues(t, 101, pop.innovations.nextInnovNum, "wrong next innovation number")
 	assert.True(t, len(pop.Species) > 0, "population has no species")
 
 	for i, org := range pop.Organisms {
@@ -58,7 +58,7 @@ func TestNewPopulation(t *testing.T) {
 
 	nextGeneInnovNum, err := gen.getNextGeneInnovNum()
 	require.NoError(t, err, "failed to get next gene innovation number")
-	assert.Equal(t, nextGeneInnovNum-1, pop.nextInnovNum, "wrong next innovation number in population")
+	assert.Equal(t, nextGeneInnovNum-1, pop.innovations.nextInnovNum, "wrong next innovation number in population")
 	require.Len(t, pop.Species, 1, "wrong species number")
 
 	for i, org := range pop.Organisms {
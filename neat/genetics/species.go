@@ -34,6 +34,11 @@ type Species struct {
 
 	// Flag used for search optimization
 	IsChecked bool
+
+	// The number of elite organisms expected to be copied unmodified during the last reproduction cycle
+	elitesExpected int
+	// The number of elite organisms actually copied unmodified during the last reproduction cycle
+	elitesPreserved int
 }
 
 // NewSpecies Construct new species with specified ID
@@ -160,6 +165,11 @@ func (s *Species) adjustFitness(opts *neat.Options) {
 	// Adding 1.0 ensures that at least one will survive
 	numParents := int(math.Floor(opts.SurvivalThresh*float64(len(s.Organisms)) + 1.0))
 
+	// The elites of species should survive to be copied into the next generation
+	if opts.SpeciesElitism > numParents {
+		numParents = opts.SpeciesElitism
+	}
+
 	// Mark for death those who are ranked too low to be parents
 	s.Organisms[0].isChampion = true // Mark the champ as such
 	for c := numParents; c < len(s.Organisms); c++ {
@@ -248,6 +258,20 @@ func (s *Species) findChampion() *Organism {
 	return s.Organisms[0]
 }
 
+// Returns the organisms of this species to be copied unmodified into the next generation. These are the top
+// neat.Options.SpeciesElitism organisms and the population-wide elites belonging to this species. If species elitism
+// is not set, the champion is copied only when the species expects more than five offspring.
+func (s *Species) findElites(opts *neat.Options) []*Organism {
+	elites := make([]*Organism, 0)
+	for i, org := range s.Organisms {
+		if i < opts.SpeciesElitism || org.isPopulationElite ||
+			(i == 0 && opts.SpeciesElitism == 0 && s.ExpectedOffspring > 5) {
+			elites = append(elites, org)
+		}
+	}
+	return elites
+}
+
 // Perform mating and mutation to form next generation. The sorted_species is ordered to have best species in the beginning.
 // Returns list of baby organisms as a result of reproduction of all organisms in this species.
 func (s *Species) reproduce(ctx context.Context, generation int, pop *Population, sortedSpecies []*Species) ([]*Organism, error) {
@@ -268,8 +292,11 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 	// The species babies
 	babies := make([]*Organism, 0)
 
-	// Flag the preservation of the champion
-	champCloneDone := false
+	// The elites to be copied unmodified and the index of the next one to be copied
+	elites := s.findElites(opts)
+	eliteIndex := 0
+	// Flag that the exact duplicate of the super champion was created
+	superChampCloneDone := false
 
 	// Create the designated number of offspring for the Species one at a time
 	for count := 0; count < s.ExpectedOffspring; count++ {
@@ -310,7 +337,7 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 			if theChamp.superChampOffspring > 1 {
 				if rand.Float64() < 0.8 || opts.MutateAddLinkProb == 0.0 {
 					// Make sure no links get added when the system has link adding disabled
-					if _, err = newGenome.mutateLinkWeights(opts.WeightMutPower, 1.0, gaussianMutator); err != nil {
+					if _, err = newGenome.mutateLinkWeights(opts.WeightMutPower, 1.0, gaussianMutator, opts); err != nil {
 						return nil, err
 					}
 				} else {
@@ -333,20 +360,21 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 					baby.isPopulationChampionChild = true
 					baby.highestFitness = mom.originalFitness
 				}
+				superChampCloneDone = true
 			}
 
 			theChamp.superChampOffspring--
-		} else if !champCloneDone && s.ExpectedOffspring > 5 {
-			neat.DebugLog("SPECIES: Clone species champion")
+		} else if eliteIndex < len(elites) {
+			neat.DebugLog("SPECIES: Clone species elite")
 
-			// If we have a Species champion, just clone it
-			mom := theChamp // Mom is the champ
+			// If we have a Species elite, just clone it
+			mom := elites[eliteIndex]
 			newGenome, err := mom.Genotype.duplicate(count)
 			if err != nil {
 				return nil, err
 			}
 			// Baby is just like mommy
-			champCloneDone = true
+			eliteIndex++
 
 			// Create the new baby organism
 			baby, err = NewOrganism(0.0, newGenome, generation)
@@ -446,7 +474,7 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 				neat.DebugLog("SPECIES: ------> mateMultipoint")
 
 				// mate multipoint baby
-				newGenome, err = mom.Genotype.mateMultipoint(dad.Genotype, count, mom.originalFitness, dad.originalFitness)
+				newGenome, err = mom.Genotype.mateMultipoint(dad.Genotype, count, mom.originalFitness, dad.originalFitness, opts)
 				if err != nil {
 					return nil, err
 				}
@@ -454,14 +482,14 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 				neat.DebugLog("SPECIES: ------> mateMultipointAvg")
 
 				// mate multipoint_avg baby
-				newGenome, err = mom.Genotype.mateMultipointAvg(dad.Genotype, count, mom.originalFitness, dad.originalFitness)
+				newGenome, err = mom.Genotype.mateMultipointAvg(dad.Genotype, count, mom.originalFitness, dad.originalFitness, opts)
 				if err != nil {
 					return nil, err
 				}
 			} else {
 				neat.DebugLog("SPECIES: ------> mateSinglePoint")
 
-				newGenome, err = mom.Genotype.mateSinglePoint(dad.Genotype, count)
+				newGenome, err = mom.Genotype.mateSinglePoint(dad.Genotype, count, opts)
 				if err != nil {
 					return nil, err
 				}
@@ -473,7 +501,7 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 			// This is done randomly or if the mom and dad are the same organism
 			if rand.Float64() > opts.MateOnlyProb ||
 				dad.Genotype.Id == mom.Genotype.Id ||
-				dad.Genotype.compatibility(mom.Genotype, opts) == 0.0 {
+				dad.Genotype.Compatibility(mom.Genotype, opts) == 0.0 {
 				neat.DebugLog("SPECIES: ------> Mutate baby genome:")
 
 				// Do the mutation depending on probabilities of  various mutations
@@ -521,6 +549,13 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 		babies = append(babies, baby)
 
 	} // end for count := 0
+
+	// Store the elitism outcome. The champion is considered preserved also if the exact duplicate
+	// of the super champion was created.
+	s.elitesExpected, s.elitesPreserved = len(elites), eliteIndex
+	if eliteIndex == 0 && superChampCloneDone && len(elites) > 0 && elites[0] == theChamp {
+		s.elitesPreserved = 1
+	}
 	return babies, nil
 }
 
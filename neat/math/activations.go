@@ -3,6 +3,9 @@ package math
 import (
 	"fmt"
 	"math"
+	"strings"
+	"sync"
+	"unicode"
 )
 
 // NodeActivationType defines the type of activation function to use for the neuron node
@@ -38,8 +41,17 @@ const (
 	MultiplyModuleActivation
 	MaxModuleActivation
 	MinModuleActivation
+
+	// The parametric activators with shape controlled by the neuron's trait parameters
+	SigmoidParametricActivation
+	GaussianParametricActivation
+	LeakyReLUParametricActivation
 )
 
+// FirstCustomActivation The first activation type to be assigned to custom activation functions registered with
+// NodeActivatorsFactory.RegisterCustom
+const FirstCustomActivation = LeakyReLUParametricActivation + 1
+
 // ActivationFunction The neuron node activation function type
 type ActivationFunction func(float64, []float64) float64
 
@@ -59,6 +71,9 @@ type NodeActivatorsFactory struct {
 	// The forward and inverse maps of activator type and function name
 	forward map[NodeActivationType]string
 	inverse map[string]NodeActivationType
+
+	// The mutex to guard registration of activators against concurrent use of the factory
+	mutex sync.RWMutex
 }
 
 // NewNodeActivatorsFactory Returns node activator factory initialized with default activation functions
@@ -97,13 +112,21 @@ func NewNodeActivatorsFactory() *NodeActivatorsFactory {
 	af.RegisterModule(MaxModuleActivation, maxModule, "MaxModuleActivation")
 	af.RegisterModule(MinModuleActivation, minModule, "MinModuleActivation")
 
+	// parametric activators
+	af.Register(SigmoidParametricActivation, parametricSigmoid, "SigmoidParametricActivation")
+	af.Register(GaussianParametricActivation, parametricGaussian, "GaussianParametricActivation")
+	af.Register(LeakyReLUParametricActivation, parametricLeakyReLU, "LeakyReLUParametricActivation")
+
 	return af
 }
 
 // ActivateByType is to calculate activation value for give input and auxiliary parameters using activation function with specified type.
 // Will return error and -math.Inf activation if unsupported activation type requested.
 func (a *NodeActivatorsFactory) ActivateByType(input float64, auxParams []float64, aType NodeActivationType) (float64, error) {
-	if fn, ok := a.activators[aType]; ok {
+	a.mutex.RLock()
+	fn, ok := a.activators[aType]
+	a.mutex.RUnlock()
+	if ok {
 		return fn(input, auxParams), nil
 	} else {
 		return math.Inf(-1), fmt.Errorf("unknown neuron activation type: %d", aType)
@@ -113,7 +136,10 @@ func (a *NodeActivatorsFactory) ActivateByType(input float64, auxParams []float6
 // ActivateModuleByType will apply corresponding module activation function to the input values and returns appropriate output values.
 // Will panic if unsupported activation function requested
 func (a *NodeActivatorsFactory) ActivateModuleByType(inputs []float64, auxParams []float64, aType NodeActivationType) ([]float64, error) {
-	if fn, ok := a.moduleActivators[aType]; ok {
+	a.mutex.RLock()
+	fn, ok := a.moduleActivators[aType]
+	a.mutex.RUnlock()
+	if ok {
 		return fn(inputs, auxParams), nil
 	} else {
 		return nil, fmt.Errorf("unknown module activation type: %d", aType)
@@ -122,6 +148,13 @@ func (a *NodeActivatorsFactory) ActivateModuleByType(inputs []float64, auxParams
 
 // Register Registers given neuron activation function with provided type and name into the factory
 func (a *NodeActivatorsFactory) Register(aType NodeActivationType, aFunc ActivationFunction, fName string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.register(aType, aFunc, fName)
+}
+
+// register stores given neuron activation function with provided type and name, the caller must hold the lock
+func (a *NodeActivatorsFactory) register(aType NodeActivationType, aFunc ActivationFunction, fName string) {
 	// store function
 	a.activators[aType] = aFunc
 	// store name<->type bi-directional mapping
@@ -131,6 +164,8 @@ func (a *NodeActivatorsFactory) Register(aType NodeActivationType, aFunc Activat
 
 // RegisterModule Registers given neuron module activation function with provided type and name into the factory
 func (a *NodeActivatorsFactory) RegisterModule(aType NodeActivationType, aFunc ModuleActivationFunction, fName string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
 	// store function
 	a.moduleActivators[aType] = aFunc
 	// store name<->type bi-directional mapping
@@ -138,8 +173,36 @@ func (a *NodeActivatorsFactory) RegisterModule(aType NodeActivationType, aFunc M
 	a.inverse[fName] = aType
 }
 
+// RegisterCustom Registers given custom neuron activation function with provided name into the factory and returns the
+// activation type assigned to it. The new activation type is allocated after the built-in activation types, while the
+// function registered with the same name before is replaced keeping its type. The name of function must not contain
+// whitespaces and must not be the name of built-in activation function.
+func (a *NodeActivatorsFactory) RegisterCustom(aFunc ActivationFunction, fName string) (NodeActivationType, error) {
+	if len(fName) == 0 || strings.IndexFunc(fName, unicode.IsSpace) >= 0 {
+		return 0, fmt.Errorf("invalid activation function name: %q", fName)
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if aType, ok := a.inverse[fName]; ok {
+		if aType < FirstCustomActivation {
+			return 0, fmt.Errorf("can not override built-in activation function: %s", fName)
+		}
+		a.activators[aType] = aFunc
+		return aType, nil
+	}
+	for aType := FirstCustomActivation; aType < math.MaxUint8; aType++ {
+		if _, ok := a.forward[aType]; !ok {
+			a.register(aType, aFunc, fName)
+			return aType, nil
+		}
+	}
+	return 0, fmt.Errorf("no free activation types left to register activation function: %s", fName)
+}
+
 // ActivationTypeFromName Parse node activation type name and return corresponding activation type
 func (a *NodeActivatorsFactory) ActivationTypeFromName(name string) (NodeActivationType, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
 	if t, ok := a.inverse[name]; ok {
 		return t, nil
 	} else {
@@ -149,6 +212,8 @@ func (a *NodeActivatorsFactory) ActivationTypeFromName(name string) (NodeActivat
 
 // ActivationNameFromType Returns activation function name from given type
 func (a *NodeActivatorsFactory) ActivationNameFromType(aType NodeActivationType) (string, error) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
 	if n, ok := a.forward[aType]; ok {
 		return n, nil
 	} else {
@@ -305,3 +370,37 @@ var (
 		return []float64{minVal}
 	}
 )
+
+// The parametric activation functions. The auxiliary parameters are taken from the neuron's trait, which parameters
+// are non-negative and evolve with trait mutations. With all parameters set to zero, the parametric sigmoid behaves
+// as the steepened sigmoid, the parametric gaussian as the plain gaussian, and the parametric leaky ReLU as the ReLU.
+var (
+	// The sigmoid with evolvable slope and offset: 1 / (1 + exp(-(4.924273 * (1 + a0) * x + a1 - a2)))
+	parametricSigmoid = func(input float64, auxParams []float64) float64 {
+		slope := 4.924273 * (1.0 + auxParam(auxParams, 0))
+		offset := auxParam(auxParams, 1) - auxParam(auxParams, 2)
+		return 1.0 / (1.0 + math.Exp(-(slope*input + offset)))
+	}
+	// The Gaussian with evolvable width: exp(-(x / w)^2), where w = (1 + a0) / (1 + a1)
+	parametricGaussian = func(input float64, auxParams []float64) float64 {
+		width := (1.0 + auxParam(auxParams, 0)) / (1.0 + auxParam(auxParams, 1))
+		scaled := input / width
+		return math.Exp(-scaled * scaled)
+	}
+	// The leaky ReLU with evolvable leak: x > 0 ? x : leak * x, where leak = a0 clamped to [0, 1]
+	parametricLeakyReLU = func(input float64, auxParams []float64) float64 {
+		if input > 0 {
+			return input
+		}
+		leak := math.Min(math.Max(auxParam(auxParams, 0), 0.0), 1.0)
+		return leak * input
+	}
+)
+
+// auxParam returns the auxiliary parameter at the given index or zero if it is not provided
+func auxParam(auxParams []float64, index int) float64 {
+	if index < len(auxParams) {
+		return auxParams[index]
+	}
+	return 0.0
+}
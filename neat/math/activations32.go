@@ -0,0 +1,310 @@
+package math
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// ActivationFunction32 The neuron node activation function type with single precision values
+type ActivationFunction32 func(float32, []float32) float32
+
+// ModuleActivationFunction32 The neurons module activation function type with single precision values
+type ModuleActivationFunction32 func([]float32, []float32) []float32
+
+// NodeActivators32 The default single precision node activators factory reference
+var NodeActivators32 = NewNodeActivatorsFactory32()
+
+// NodeActivatorsFactory32 The factory to provide appropriate single precision neuron node activation function. It
+// mirrors the NodeActivatorsFactory for use by the solvers storing signals as float32 values. The activation types and
+// their names are shared with the NodeActivatorsFactory.
+type NodeActivatorsFactory32 struct {
+	// The map of registered neuron node activators by type
+	activators map[NodeActivationType]ActivationFunction32
+	// The map of registered neuron module activators by type
+	moduleActivators map[NodeActivationType]ModuleActivationFunction32
+
+	// The mutex to guard registration of activators against concurrent use of the factory
+	mutex sync.RWMutex
+}
+
+// NewNodeActivatorsFactory32 Returns single precision node activator factory initialized with default activation functions
+func NewNodeActivatorsFactory32() *NodeActivatorsFactory32 {
+	af := &NodeActivatorsFactory32{
+		activators:       make(map[NodeActivationType]ActivationFunction32),
+		moduleActivators: make(map[NodeActivationType]ModuleActivationFunction32),
+	}
+	// Register neuron node activators
+	af.Register(SigmoidPlainActivation, plainSigmoid32)
+	af.Register(SigmoidReducedActivation, reducedSigmoid32)
+	af.Register(SigmoidSteepenedActivation, steepenedSigmoid32)
+	af.Register(SigmoidBipolarActivation, bipolarSigmoid32)
+	af.Register(SigmoidApproximationActivation, approximationSigmoid32)
+	af.Register(SigmoidSteepenedApproximationActivation, approximationSteepenedSigmoid32)
+	af.Register(SigmoidInverseAbsoluteActivation, inverseAbsoluteSigmoid32)
+	af.Register(SigmoidLeftShiftedActivation, leftShiftedSigmoid32)
+	af.Register(SigmoidLeftShiftedSteepenedActivation, leftShiftedSteepenedSigmoid32)
+	af.Register(SigmoidRightShiftedSteepenedActivation, rightShiftedSteepenedSigmoid32)
+
+	af.Register(TanhActivation, hyperbolicTangent32)
+	af.Register(GaussianBipolarActivation, bipolarGaussian32)
+	af.Register(GaussianActivation, gaussian32)
+	af.Register(LinearActivation, linear32)
+	af.Register(LinearAbsActivation, absoluteLinear32)
+	af.Register(LinearClippedActivation, clippedLinear32)
+	af.Register(NullActivation, nullFunctor32)
+	af.Register(SignActivation, signFunction32)
+	af.Register(SineActivation, sineFunction32)
+	af.Register(StepActivation, stepFunction32)
+
+	// register neuron modules activators
+	af.RegisterModule(MultiplyModuleActivation, multiplyModule32)
+	af.RegisterModule(MaxModuleActivation, maxModule32)
+	af.RegisterModule(MinModuleActivation, minModule32)
+
+	// parametric activators
+	af.Register(SigmoidParametricActivation, parametricSigmoid32)
+	af.Register(GaussianParametricActivation, parametricGaussian32)
+	af.Register(LeakyReLUParametricActivation, parametricLeakyReLU32)
+
+	return af
+}
+
+// ActivateByType is to calculate activation value for give input and auxiliary parameters using activation function with specified type.
+// Will return error and -math.Inf activation if unsupported activation type requested.
+func (a *NodeActivatorsFactory32) ActivateByType(input float32, auxParams []float32, aType NodeActivationType) (float32, error) {
+	a.mutex.RLock()
+	fn, ok := a.activators[aType]
+	a.mutex.RUnlock()
+	if ok {
+		return fn(input, auxParams), nil
+	} else {
+		return float32(math.Inf(-1)), fmt.Errorf("unknown neuron activation type: %d", aType)
+	}
+}
+
+// ActivateModuleByType will apply corresponding module activation function to the input values and returns appropriate output values.
+// Will return error if unsupported activation function requested
+func (a *NodeActivatorsFactory32) ActivateModuleByType(inputs []float32, auxParams []float32, aType NodeActivationType) ([]float32, error) {
+	a.mutex.RLock()
+	fn, ok := a.moduleActivators[aType]
+	a.mutex.RUnlock()
+	if ok {
+		return fn(inputs, auxParams), nil
+	} else {
+		return nil, fmt.Errorf("unknown module activation type: %d", aType)
+	}
+}
+
+// Register Registers given neuron activation function with provided type into the factory
+func (a *NodeActivatorsFactory32) Register(aType NodeActivationType, aFunc ActivationFunction32) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.activators[aType] = aFunc
+}
+
+// RegisterModule Registers given neuron module activation function with provided type into the factory
+func (a *NodeActivatorsFactory32) RegisterModule(aType NodeActivationType, aFunc ModuleActivationFunction32) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.moduleActivators[aType] = aFunc
+}
+
+// The transcendental functions are evaluated with double precision and rounded to single precision result, which is
+// the best possible accuracy of the single precision value.
+func exp32(x float32) float32 {
+	return float32(math.Exp(float64(x)))
+}
+
+// The sigmoid activation functions
+var (
+	// The plain sigmoid
+	plainSigmoid32 = func(input float32, auxParams []float32) float32 {
+		return 1 / (1 + exp32(-input))
+	}
+	// The reduced sigmoid
+	reducedSigmoid32 = func(input float32, auxParams []float32) float32 {
+		return 1 / (1 + exp32(-0.5*input))
+	}
+	// The steepened sigmoid
+	steepenedSigmoid32 = func(input float32, auxParams []float32) float32 {
+		return 1.0 / (1.0 + exp32(-4.924273*input))
+	}
+	// The bipolar sigmoid activation function xrange->[-1,1] yrange->[-1,1]
+	bipolarSigmoid32 = func(input float32, auxParams []float32) float32 {
+		return (2.0 / (1.0 + exp32(-4.924273*input))) - 1.0
+	}
+	// The approximation sigmoid with squashing range [-4.0; 4.0]
+	approximationSigmoid32 = func(input float32, auxParams []float32) float32 {
+		var four, one32nd float32 = 4.0, 0.03125
+		if input < -4.0 {
+			return 0.0
+		} else if input < 0.0 {
+			return (input + four) * (input + four) * one32nd
+		} else if input < 4.0 {
+			return 1.0 - (input-four)*(input-four)*one32nd
+		} else {
+			return 1.0
+		}
+	}
+	// The steepened approximation sigmoid with squashing range [-1.0; 1.0]
+	approximationSteepenedSigmoid32 = func(input float32, auxParams []float32) float32 {
+		var one, oneHalf float32 = 1.0, 0.5
+		if input < -1.0 {
+			return 0.0
+		} else if input < 0.0 {
+			return (input + one) * (input + one) * oneHalf
+		} else if input < 1.0 {
+			return 1.0 - (input-one)*(input-one)*oneHalf
+		} else {
+			return 1.0
+		}
+	}
+	// The inverse absolute sigmoid
+	inverseAbsoluteSigmoid32 = func(input float32, auxParams []float32) float32 {
+		return 0.5 + (input/(1.0+float32(math.Abs(float64(input)))))*0.5
+	}
+
+	// The left/right shifted sigmoid
+	leftShiftedSigmoid32 = func(input float32, auxParams []float32) float32 {
+		return 1.0 / (1.0 + exp32(-input-2.4621365))
+	}
+	leftShiftedSteepenedSigmoid32 = func(input float32, auxParams []float32) float32 {
+		return 1.0 / (1.0 + exp32(-(4.924273*input + 2.4621365)))
+	}
+	rightShiftedSteepenedSigmoid32 = func(input float32, auxParams []float32) float32 {
+		return 1.0 / (1.0 + exp32(-(4.924273*input - 2.4621365)))
+	}
+)
+
+// The other activation functions
+var (
+	// The hyperbolic tangent
+	hyperbolicTangent32 = func(input float32, auxParams []float32) float32 {
+		return float32(math.Tanh(0.9 * float64(input)))
+	}
+	// The bipolar Gaussian activator xrange->[-1,1] yrange->[-1,1]
+	bipolarGaussian32 = func(input float32, auxParams []float32) float32 {
+		x := input * 2.5
+		return 2.0*exp32(-x*x) - 1.0
+	}
+	// The Gaussian activator xrange->[-1,1] yrange->[0,1]
+	gaussian32 = func(input float32, auxParams []float32) float32 {
+		return exp32(-input * input)
+	}
+	// The absolute linear
+	absoluteLinear32 = func(input float32, auxParams []float32) float32 {
+		return float32(math.Abs(float64(input)))
+	}
+	// Linear activation function with clipping. By 'clipping' we mean the output value is linear between
+	/// x = -1 and x = 1. Below -1 and above +1 the output is clipped at -1 and +1 respectively
+	clippedLinear32 = func(input float32, auxParams []float32) float32 {
+		if input < -1.0 {
+			return -1.0
+		}
+		if input > 1.0 {
+			return 1.0
+		}
+		return input
+	}
+	// The linear activation
+	linear32 = func(input float32, auxParams []float32) float32 {
+		return input
+	}
+	// The null activator
+	nullFunctor32 = func(input float32, auxParams []float32) float32 {
+		return 0.0
+	}
+	// The sign activator
+	signFunction32 = func(input float32, auxParams []float32) float32 {
+		if input != input || input == 0.0 {
+			// NaN or zero
+			return 0.0
+		} else if math.Signbit(float64(input)) {
+			return -1.0
+		} else {
+			return 1.0
+		}
+	}
+	// The sine periodic activation with doubled period
+	sineFunction32 = func(input float32, auxParams []float32) float32 {
+		return float32(math.Sin(2.0 * float64(input)))
+	}
+	// The step function x<0 ? 0.0 : 1.0
+	stepFunction32 = func(input float32, auxParams []float32) float32 {
+		if math.Signbit(float64(input)) {
+			return 0.0
+		} else {
+			return 1.0
+		}
+	}
+)
+
+// The modular activators
+var (
+	// Multiplies input values and returns multiplication result
+	multiplyModule32 = func(inputs []float32, auxParams []float32) []float32 {
+		var ret float32 = 1.0
+		for _, v := range inputs {
+			ret *= v
+		}
+		return []float32{ret}
+	}
+	// Finds maximal value among inputs and return it
+	maxModule32 = func(inputs []float32, auxParams []float32) []float32 {
+		maxVal := float32(math.MinInt64)
+		for _, v := range inputs {
+			if v > maxVal {
+				maxVal = v
+			}
+		}
+		return []float32{maxVal}
+	}
+	// Finds minimal value among inputs and returns it
+	minModule32 = func(inputs []float32, auxParams []float32) []float32 {
+		var minVal float32 = math.MaxFloat32
+		for _, v := range inputs {
+			if v < minVal {
+				minVal = v
+			}
+		}
+		return []float32{minVal}
+	}
+)
+
+// The parametric activation functions
+var (
+	// The sigmoid with evolvable slope and offset
+	parametricSigmoid32 = func(input float32, auxParams []float32) float32 {
+		slope := 4.924273 * (1.0 + auxParam32(auxParams, 0))
+		offset := auxParam32(auxParams, 1) - auxParam32(auxParams, 2)
+		return 1.0 / (1.0 + exp32(-(slope*input + offset)))
+	}
+	// The Gaussian with evolvable width
+	parametricGaussian32 = func(input float32, auxParams []float32) float32 {
+		width := (1.0 + auxParam32(auxParams, 0)) / (1.0 + auxParam32(auxParams, 1))
+		scaled := input / width
+		return exp32(-scaled * scaled)
+	}
+	// The leaky ReLU with evolvable leak
+	parametricLeakyReLU32 = func(input float32, auxParams []float32) float32 {
+		if input > 0 {
+			return input
+		}
+		leak := auxParam32(auxParams, 0)
+		if leak > 1.0 {
+			leak = 1.0
+		} else if leak < 0.0 {
+			leak = 0.0
+		}
+		return leak * input
+	}
+)
+
+// auxParam32 returns the auxiliary parameter at the given index or zero if it is not provided
+func auxParam32(auxParams []float32, index int) float32 {
+	if index < len(auxParams) {
+		return auxParams[index]
+	}
+	return 0.0
+}
@@ -0,0 +1,64 @@
+package math
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+// The maximal allowed relative error of the single precision activation compared to the double precision activation
+const activation32Tolerance = 1e-6
+
+func TestNodeActivatorsFactory32_ActivateByType(t *testing.T) {
+	inputs := []float64{-10, -4.5, -2, -1, -0.75, -0.3, -1e-3, 0, 1e-3, 0.3, 0.75, 1, 2, 4.5, 10}
+	for aType := SigmoidPlainActivation; aType <= StepActivation; aType++ {
+		name, err := NodeActivators.ActivationNameFromType(aType)
+		require.NoError(t, err)
+		for _, input := range inputs {
+			expected, err := NodeActivators.ActivateByType(float64(float32(input)), nil, aType)
+			require.NoError(t, err, name)
+			actual, err := NodeActivators32.ActivateByType(float32(input), nil, aType)
+			require.NoError(t, err, name)
+			assert.InDelta(t, expected, float64(actual), activation32Tolerance*math.Max(1, math.Abs(expected)),
+				"wrong activation of %s at: %f", name, input)
+		}
+	}
+}
+
+func TestNodeActivatorsFactory32_ActivateByType_unsupported(t *testing.T) {
+	res, err := NodeActivators32.ActivateByType(1, nil, MultiplyModuleActivation)
+	assert.EqualError(t, err, "unknown neuron activation type: 21")
+	assert.True(t, math.IsInf(float64(res), -1))
+}
+
+func TestNodeActivatorsFactory32_ActivateModuleByType(t *testing.T) {
+	inputs := []float64{1.5, -2.25, 0.5, 3}
+	inputs32 := make([]float32, len(inputs))
+	for i, v := range inputs {
+		inputs32[i] = float32(v)
+	}
+	for aType := MultiplyModuleActivation; aType <= MinModuleActivation; aType++ {
+		expected, err := NodeActivators.ActivateModuleByType(inputs, nil, aType)
+		require.NoError(t, err)
+		actual, err := NodeActivators32.ActivateModuleByType(inputs32, nil, aType)
+		require.NoError(t, err)
+		require.Len(t, actual, len(expected))
+		for i := range expected {
+			assert.InDelta(t, expected[i], float64(actual[i]), activation32Tolerance*math.Max(1, math.Abs(expected[i])))
+		}
+	}
+
+	_, err := NodeActivators32.ActivateModuleByType(inputs32, nil, SigmoidPlainActivation)
+	assert.EqualError(t, err, "unknown module activation type: 1")
+}
+
+func TestNodeActivatorsFactory32_Register(t *testing.T) {
+	factory := NewNodeActivatorsFactory32()
+	factory.Register(SigmoidPlainActivation, func(input float32, _ []float32) float32 {
+		return 2 * input
+	})
+	res, err := factory.ActivateByType(1.5, nil, SigmoidPlainActivation)
+	require.NoError(t, err)
+	assert.Equal(t, float32(3), res)
+}
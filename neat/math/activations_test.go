@@ -0,0 +1,92 @@
+package math
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"testing"
+)
+
+func TestNodeActivatorsFactory_parametricDefaults(t *testing.T) {
+	// with zero or missing parameters the parametric activations are equal to their plain counterparts
+	defaults := map[NodeActivationType]NodeActivationType{
+		SigmoidParametricActivation:  SigmoidSteepenedActivation,
+		GaussianParametricActivation: GaussianActivation,
+	}
+	inputs := []float64{-2, -0.5, 0, 0.3, 1.5}
+	for aType, plainType := range defaults {
+		for _, input := range inputs {
+			expected, err := NodeActivators.ActivateByType(input, nil, plainType)
+			require.NoError(t, err)
+			for _, params := range [][]float64{nil, make([]float64, 8)} {
+				actual, err := NodeActivators.ActivateByType(input, params, aType)
+				require.NoError(t, err)
+				assert.InDelta(t, expected, actual, 1e-12, "wrong activation of type: %d at: %f", aType, input)
+			}
+		}
+	}
+
+	// the leaky ReLU without parameters is ReLU
+	for _, input := range inputs {
+		actual, err := NodeActivators.ActivateByType(input, nil, LeakyReLUParametricActivation)
+		require.NoError(t, err)
+		assert.Equal(t, math.Max(input, 0), actual, "wrong ReLU activation at: %f", input)
+	}
+}
+
+func TestNodeActivatorsFactory_parametric(t *testing.T) {
+	testCases := []struct {
+		name     string
+		aType    NodeActivationType
+		input    float64
+		params   []float64
+		expected float64
+	}{
+		{name: "sigmoid slope", aType: SigmoidParametricActivation, input: 0.5, params: []float64{1},
+			expected: 1 / (1 + math.Exp(-4.924273*2*0.5))},
+		{name: "sigmoid positive offset", aType: SigmoidParametricActivation, input: 0, params: []float64{0, 2},
+			expected: 1 / (1 + math.Exp(-2))},
+		{name: "sigmoid negative offset", aType: SigmoidParametricActivation, input: 0, params: []float64{0, 0.5, 2},
+			expected: 1 / (1 + math.Exp(1.5))},
+		{name: "gaussian wide", aType: GaussianParametricActivation, input: 1, params: []float64{1},
+			expected: math.Exp(-0.25)},
+		{name: "gaussian narrow", aType: GaussianParametricActivation, input: 1, params: []float64{0, 1},
+			expected: math.Exp(-4)},
+		{name: "leaky ReLU positive", aType: LeakyReLUParametricActivation, input: 2, params: []float64{0.1},
+			expected: 2},
+		{name: "leaky ReLU negative", aType: LeakyReLUParametricActivation, input: -2, params: []float64{0.1},
+			expected: -0.2},
+		{name: "leaky ReLU clamped leak", aType: LeakyReLUParametricActivation, input: -2, params: []float64{3},
+			expected: -2},
+	}
+	for _, tc := range testCases {
+		actual, err := NodeActivators.ActivateByType(tc.input, tc.params, tc.aType)
+		require.NoError(t, err, tc.name)
+		assert.InDelta(t, tc.expected, actual, 1e-12, tc.name)
+
+		params32 := make([]float32, len(tc.params))
+		for i, p := range tc.params {
+			params32[i] = float32(p)
+		}
+		actual32, err := NodeActivators32.ActivateByType(float32(tc.input), params32, tc.aType)
+		require.NoError(t, err, tc.name)
+		assert.InDelta(t, tc.expected, float64(actual32), activation32Tolerance*math.Max(1, math.Abs(tc.expected)), tc.name)
+	}
+}
+
+func TestNodeActivatorsFactory_parametricNames(t *testing.T) {
+	names := map[NodeActivationType]string{
+		SigmoidParametricActivation:   "SigmoidParametricActivation",
+		GaussianParametricActivation:  "GaussianParametricActivation",
+		LeakyReLUParametricActivation: "LeakyReLUParametricActivation",
+	}
+	for aType, name := range names {
+		actualName, err := NodeActivators.ActivationNameFromType(aType)
+		require.NoError(t, err)
+		assert.Equal(t, name, actualName)
+
+		actualType, err := NodeActivators.ActivationTypeFromName(name)
+		require.NoError(t, err)
+		assert.Equal(t, aType, actualType)
+	}
+}
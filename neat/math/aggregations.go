@@ -0,0 +1,131 @@
+package math
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NodeAggregationType defines the type of function to aggregate the weighted input signals of the neuron node into
+// the single value passed to the activation function
+type NodeAggregationType byte
+
+// The neuron aggregation function types. The zero value is the sum, which is the default aggregation of the neurons.
+const (
+	SumAggregation NodeAggregationType = iota
+	ProductAggregation
+	MaxAggregation
+	MinAggregation
+	MeanAggregation
+	MedianAggregation
+	MaxAbsAggregation
+)
+
+// The names of the aggregation functions by type
+var aggregationNames = map[NodeAggregationType]string{
+	SumAggregation:     "SumAggregation",
+	ProductAggregation: "ProductAggregation",
+	MaxAggregation:     "MaxAggregation",
+	MinAggregation:     "MinAggregation",
+	MeanAggregation:    "MeanAggregation",
+	MedianAggregation:  "MedianAggregation",
+	MaxAbsAggregation:  "MaxAbsAggregation",
+}
+
+// AggregationNameFromType Returns aggregation function name from given type
+func AggregationNameFromType(aType NodeAggregationType) (string, error) {
+	if name, ok := aggregationNames[aType]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("unsupported aggregation type: %d", aType)
+}
+
+// AggregationTypeFromName Returns aggregation function type from given name
+func AggregationTypeFromName(name string) (NodeAggregationType, error) {
+	for aType, aName := range aggregationNames {
+		if aName == name {
+			return aType, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported aggregation type name: %s", name)
+}
+
+// Aggregate is to aggregate given input signals using aggregation function with specified type. The empty inputs
+// are aggregated into zero by any function. Will return error if unsupported aggregation type requested.
+func Aggregate(aType NodeAggregationType, inputs []float64) (float64, error) {
+	if len(inputs) == 0 {
+		if _, ok := aggregationNames[aType]; !ok {
+			return 0, fmt.Errorf("unknown neuron aggregation type: %d", aType)
+		}
+		return 0, nil
+	}
+	switch aType {
+	case SumAggregation:
+		sum := 0.0
+		for _, v := range inputs {
+			sum += v
+		}
+		return sum, nil
+	case ProductAggregation:
+		product := 1.0
+		for _, v := range inputs {
+			product *= v
+		}
+		return product, nil
+	case MaxAggregation:
+		maxVal := inputs[0]
+		for _, v := range inputs[1:] {
+			maxVal = math.Max(maxVal, v)
+		}
+		return maxVal, nil
+	case MinAggregation:
+		minVal := inputs[0]
+		for _, v := range inputs[1:] {
+			minVal = math.Min(minVal, v)
+		}
+		return minVal, nil
+	case MeanAggregation:
+		sum := 0.0
+		for _, v := range inputs {
+			sum += v
+		}
+		return sum / float64(len(inputs)), nil
+	case MedianAggregation:
+		sorted := make([]float64, len(inputs))
+		copy(sorted, inputs)
+		sort.Float64s(sorted)
+		middle := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			return (sorted[middle-1] + sorted[middle]) / 2, nil
+		}
+		return sorted[middle], nil
+	case MaxAbsAggregation:
+		// the input with the maximal absolute value keeping its sign
+		maxAbs := inputs[0]
+		for _, v := range inputs[1:] {
+			if math.Abs(v) > math.Abs(maxAbs) {
+				maxAbs = v
+			}
+		}
+		return maxAbs, nil
+	default:
+		return 0, fmt.Errorf("unknown neuron aggregation type: %d", aType)
+	}
+}
+
+// Aggregate32 is the single precision variant of the Aggregate
+func Aggregate32(aType NodeAggregationType, inputs []float32) (float32, error) {
+	if aType == SumAggregation {
+		var sum float32
+		for _, v := range inputs {
+			sum += v
+		}
+		return sum, nil
+	}
+	inputs64 := make([]float64, len(inputs))
+	for i, v := range inputs {
+		inputs64[i] = float64(v)
+	}
+	res, err := Aggregate(aType, inputs64)
+	return float32(res), err
+}
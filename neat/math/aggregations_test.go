@@ -0,0 +1,69 @@
+package math
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	inputs := []float64{1.5, -4, 0.5, 3}
+	testCases := map[NodeAggregationType]float64{
+		SumAggregation:     1,
+		ProductAggregation: -9,
+		MaxAggregation:     3,
+		MinAggregation:     -4,
+		MeanAggregation:    0.25,
+		MedianAggregation:  1,
+		MaxAbsAggregation:  -4,
+	}
+	for aType, expected := range testCases {
+		res, err := Aggregate(aType, inputs)
+		require.NoError(t, err)
+		assert.Equal(t, expected, res, "wrong aggregation: %d", aType)
+
+		// empty inputs aggregated into zero
+		res, err = Aggregate(aType, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, res, "wrong empty aggregation: %d", aType)
+
+		inputs32 := make([]float32, len(inputs))
+		for i, v := range inputs {
+			inputs32[i] = float32(v)
+		}
+		res32, err := Aggregate32(aType, inputs32)
+		require.NoError(t, err)
+		assert.Equal(t, float32(expected), res32, "wrong single precision aggregation: %d", aType)
+	}
+	// the inputs are not reordered
+	assert.Equal(t, []float64{1.5, -4, 0.5, 3}, inputs)
+
+	// the median of odd number of inputs
+	res, err := Aggregate(MedianAggregation, []float64{5, -1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, res)
+}
+
+func TestAggregate_unsupported(t *testing.T) {
+	_, err := Aggregate(MaxAbsAggregation+1, []float64{1})
+	assert.EqualError(t, err, "unknown neuron aggregation type: 7")
+	_, err = Aggregate(MaxAbsAggregation+1, nil)
+	assert.EqualError(t, err, "unknown neuron aggregation type: 7")
+	_, err = Aggregate32(MaxAbsAggregation+1, []float32{1})
+	assert.EqualError(t, err, "unknown neuron aggregation type: 7")
+}
+
+func TestAggregationTypeFromName(t *testing.T) {
+	for aType := SumAggregation; aType <= MaxAbsAggregation; aType++ {
+		name, err := AggregationNameFromType(aType)
+		require.NoError(t, err)
+		actual, err := AggregationTypeFromName(name)
+		require.NoError(t, err)
+		assert.Equal(t, aType, actual)
+	}
+
+	_, err := AggregationNameFromType(MaxAbsAggregation + 1)
+	assert.EqualError(t, err, "unsupported aggregation type: 7")
+	_, err = AggregationTypeFromName("SoftmaxAggregation")
+	assert.EqualError(t, err, "unsupported aggregation type name: SoftmaxAggregation")
+}
@@ -0,0 +1,419 @@
+package math
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxExpressionLength The maximal allowed length of the expression source
+	MaxExpressionLength = 1024
+	// maxExpressionDepth The maximal allowed nesting depth of the expression
+	maxExpressionDepth = 64
+	// NumExpressionAuxParams The number of auxiliary parameters available in expression as variables a0..a7
+	NumExpressionAuxParams = 8
+)
+
+// Expression is the compiled arithmetic expression of single variable x and auxiliary parameters a0..a7 to be used
+// as activation function. The expression is parsed by the small safe parser supporting only:
+//   - the decimal numbers, and the constants pi and e;
+//   - the variable x, which is the input of the activation function;
+//   - the variables a0..a7, which are the auxiliary parameters of the activation function, e.g., the parameters of
+//     the node's trait. The missing parameters are evaluated as zero;
+//   - the binary operators +, -, *, /, and ^ (power), the unary minus and plus, and the parentheses;
+//   - the functions of single argument: abs, exp, log, sqrt, sin, cos, tan, tanh, sinh, cosh, atan, floor, ceil,
+//     sign, and sigmoid, and the functions of two arguments: min, max, and pow.
+//
+// The evaluation of the expression has no side effects and always terminates.
+type Expression struct {
+	// The source of the expression
+	source string
+	// The compiled expression
+	eval exprFunc
+}
+
+// exprFunc is the compiled expression node
+type exprFunc func(x float64, auxParams []float64) float64
+
+// ParseExpression parses provided expression source and returns compiled expression or error if source is invalid.
+func ParseExpression(source string) (*Expression, error) {
+	if len(source) > MaxExpressionLength {
+		return nil, fmt.Errorf("the expression is too long: %d, maximal allowed: %d", len(source), MaxExpressionLength)
+	}
+	tokens, err := tokenizeExpression(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &expressionParser{tokens: tokens}
+	eval, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected token: %q at: %d", tok.text, tok.pos)
+	}
+	return &Expression{source: source, eval: eval}, nil
+}
+
+// Eval evaluates the expression with provided value of x and auxiliary parameters
+func (e *Expression) Eval(x float64, auxParams []float64) float64 {
+	return e.eval(x, auxParams)
+}
+
+// ActivationFunction returns the activation function evaluating this expression
+func (e *Expression) ActivationFunction() ActivationFunction {
+	return e.Eval
+}
+
+// ActivationFunction32 returns the single precision activation function evaluating this expression
+func (e *Expression) ActivationFunction32() ActivationFunction32 {
+	return func(input float32, auxParams []float32) float32 {
+		var params []float64
+		if len(auxParams) > 0 {
+			params = make([]float64, len(auxParams))
+			for i, p := range auxParams {
+				params[i] = float64(p)
+			}
+		}
+		return float32(e.eval(float64(input), params))
+	}
+}
+
+// String returns the source of the expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+// RegisterExpressionActivation parses provided expression and registers it as the activation function with given name
+// in the default NodeActivators and NodeActivators32 factories. Returns the activation type assigned to the function.
+// See NodeActivatorsFactory.RegisterCustom for details.
+func RegisterExpressionActivation(name, source string) (NodeActivationType, error) {
+	expr, err := ParseExpression(source)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse expression of activation function: %s, reason: %w", name, err)
+	}
+	aType, err := NodeActivators.RegisterCustom(expr.ActivationFunction(), name)
+	if err != nil {
+		return 0, err
+	}
+	NodeActivators32.Register(aType, expr.ActivationFunction32())
+	return aType, nil
+}
+
+// The functions supported by expressions
+var (
+	expressionFunctions = map[string]func(float64) float64{
+		"abs":   math.Abs,
+		"exp":   math.Exp,
+		"log":   math.Log,
+		"sqrt":  math.Sqrt,
+		"sin":   math.Sin,
+		"cos":   math.Cos,
+		"tan":   math.Tan,
+		"tanh":  math.Tanh,
+		"sinh":  math.Sinh,
+		"cosh":  math.Cosh,
+		"atan":  math.Atan,
+		"floor": math.Floor,
+		"ceil":  math.Ceil,
+		"sign": func(v float64) float64 {
+			return signFunction(v, nil)
+		},
+		"sigmoid": func(v float64) float64 {
+			return plainSigmoid(v, nil)
+		},
+	}
+	expressionBinaryFunctions = map[string]func(float64, float64) float64{
+		"min": math.Min,
+		"max": math.Max,
+		"pow": math.Pow,
+	}
+	expressionConstants = map[string]float64{
+		"pi": math.Pi,
+		"e":  math.E,
+	}
+)
+
+type tokenKind byte
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+)
+
+type expressionToken struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+// tokenizeExpression splits the expression source into tokens
+func tokenizeExpression(source string) ([]expressionToken, error) {
+	tokens := make([]expressionToken, 0)
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case isDigit(source[i]) || c == '.':
+			start := i
+			for i < len(source) && (isDigit(source[i]) || source[i] == '.') {
+				i++
+			}
+			// exponent part
+			if i < len(source) && (source[i] == 'e' || source[i] == 'E') {
+				j := i + 1
+				if j < len(source) && (source[j] == '+' || source[j] == '-') {
+					j++
+				}
+				if j < len(source) && isDigit(source[j]) {
+					for j < len(source) && isDigit(source[j]) {
+						j++
+					}
+					i = j
+				}
+			}
+			value, err := strconv.ParseFloat(source[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number: %q at: %d", source[start:i], start)
+			}
+			tokens = append(tokens, expressionToken{kind: tokenNumber, text: source[start:i], value: value, pos: start})
+		case isLetter(source[i]):
+			start := i
+			for i < len(source) && (isLetter(source[i]) || isDigit(source[i])) {
+				i++
+			}
+			tokens = append(tokens, expressionToken{kind: tokenIdent, text: source[start:i], pos: start})
+		case strings.ContainsRune("+-*/^(),", c):
+			tokens = append(tokens, expressionToken{kind: tokenOperator, text: string(c), pos: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character: %q at: %d", c, i)
+		}
+	}
+	return append(tokens, expressionToken{kind: tokenEnd, pos: len(source)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// expressionParser is the recursive descent parser of expressions which compiles them into the tree of closures
+type expressionParser struct {
+	tokens []expressionToken
+	pos    int
+	depth  int
+}
+
+func (p *expressionParser) peek() expressionToken {
+	return p.tokens[p.pos]
+}
+
+func (p *expressionParser) next() expressionToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEnd {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *expressionParser) isOperator(op string) bool {
+	tok := p.peek()
+	return tok.kind == tokenOperator && tok.text == op
+}
+
+func (p *expressionParser) expect(op string) error {
+	if tok := p.next(); tok.kind != tokenOperator || tok.text != op {
+		return fmt.Errorf("expected: %q at: %d, found: %q", op, tok.pos, tok.text)
+	}
+	return nil
+}
+
+// parseSum parses: product (('+'|'-') product)*
+func (p *expressionParser) parseSum() (exprFunc, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxExpressionDepth {
+		return nil, fmt.Errorf("the expression nesting is too deep at: %d", p.peek().pos)
+	}
+
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+") || p.isOperator("-") {
+		op := p.next().text
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "+" {
+			left = func(x float64, a []float64) float64 { return l(x, a) + right(x, a) }
+		} else {
+			left = func(x float64, a []float64) float64 { return l(x, a) - right(x, a) }
+		}
+	}
+	return left, nil
+}
+
+// parseProduct parses: unary (('*'|'/') unary)*
+func (p *expressionParser) parseProduct() (exprFunc, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*") || p.isOperator("/") {
+		op := p.next().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "*" {
+			left = func(x float64, a []float64) float64 { return l(x, a) * right(x, a) }
+		} else {
+			left = func(x float64, a []float64) float64 { return l(x, a) / right(x, a) }
+		}
+	}
+	return left, nil
+}
+
+// parseUnary parses: ('-'|'+') unary | power
+func (p *expressionParser) parseUnary() (exprFunc, error) {
+	if p.isOperator("-") || p.isOperator("+") {
+		op := p.next().text
+		p.depth++
+		defer func() { p.depth-- }()
+		if p.depth > maxExpressionDepth {
+			return nil, fmt.Errorf("the expression nesting is too deep at: %d", p.peek().pos)
+		}
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "-" {
+			return func(x float64, a []float64) float64 { return -operand(x, a) }, nil
+		}
+		return operand, nil
+	}
+	return p.parsePower()
+}
+
+// parsePower parses: primary ('^' unary)?, the power is right associative and has higher precedence than unary minus
+func (p *expressionParser) parsePower() (exprFunc, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOperator("^") {
+		p.next()
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(x float64, a []float64) float64 { return math.Pow(base(x, a), exponent(x, a)) }, nil
+	}
+	return base, nil
+}
+
+// parsePrimary parses: number | variable | constant | function '(' arguments ')' | '(' sum ')'
+func (p *expressionParser) parsePrimary() (exprFunc, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value := tok.value
+		return func(float64, []float64) float64 { return value }, nil
+	case tokenIdent:
+		if p.isOperator("(") {
+			return p.parseFunction(tok)
+		}
+		return parseVariable(tok)
+	case tokenOperator:
+		if tok.text == "(" {
+			expr, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect(")"); err != nil {
+				return nil, err
+			}
+			return expr, nil
+		}
+		return nil, fmt.Errorf("unexpected operator: %q at: %d", tok.text, tok.pos)
+	default:
+		return nil, fmt.Errorf("unexpected end of expression at: %d", tok.pos)
+	}
+}
+
+// parseFunction parses the arguments of the function call and returns compiled call
+func (p *expressionParser) parseFunction(name expressionToken) (exprFunc, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	args := make([]exprFunc, 0, 2)
+	if !p.isOperator(")") {
+		for {
+			arg, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if !p.isOperator(",") {
+				break
+			}
+			p.next()
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	if fn, ok := expressionFunctions[name.text]; ok {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("function: %s expects 1 argument, found: %d at: %d", name.text, len(args), name.pos)
+		}
+		arg := args[0]
+		return func(x float64, a []float64) float64 { return fn(arg(x, a)) }, nil
+	}
+	if fn, ok := expressionBinaryFunctions[name.text]; ok {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("function: %s expects 2 arguments, found: %d at: %d", name.text, len(args), name.pos)
+		}
+		arg0, arg1 := args[0], args[1]
+		return func(x float64, a []float64) float64 { return fn(arg0(x, a), arg1(x, a)) }, nil
+	}
+	return nil, fmt.Errorf("unknown function: %s at: %d", name.text, name.pos)
+}
+
+// parseVariable returns compiled variable or constant
+func parseVariable(tok expressionToken) (exprFunc, error) {
+	if tok.text == "x" {
+		return func(x float64, _ []float64) float64 { return x }, nil
+	}
+	if value, ok := expressionConstants[tok.text]; ok {
+		return func(float64, []float64) float64 { return value }, nil
+	}
+	if len(tok.text) == 2 && tok.text[0] == 'a' && isDigit(tok.text[1]) {
+		index := int(tok.text[1] - '0')
+		if index < NumExpressionAuxParams {
+			return func(_ float64, a []float64) float64 {
+				if index < len(a) {
+					return a[index]
+				}
+				return 0
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown variable: %s at: %d", tok.text, tok.pos)
+}
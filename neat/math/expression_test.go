@@ -0,0 +1,179 @@
+package math
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	aux := []float64{0.5, 2, -1}
+	testCases := []struct {
+		source   string
+		x        float64
+		expected float64
+	}{
+		{source: "x", x: 1.5, expected: 1.5},
+		{source: "42", x: 1.5, expected: 42},
+		{source: "1.5e2 + .5", expected: 150.5},
+		{source: "2e-1", expected: 0.2},
+		{source: "x / (1 + exp(-x))", x: 2, expected: 2 / (1 + math.Exp(-2))},
+		{source: "1 + 2 * 3 - 4 / 2", expected: 5},
+		{source: "(1 + 2) * 3", expected: 9},
+		{source: "2 ^ 3 ^ 2", expected: 512},
+		{source: "-x ^ 2", x: 3, expected: -9},
+		{source: "2 ^ -1", expected: 0.5},
+		{source: "--x", x: 3, expected: 3},
+		{source: "+x", x: 3, expected: 3},
+		{source: "a0 * x + a1", x: 4, expected: 4},
+		{source: "a2 + a7", expected: -1},
+		{source: "max(a0 * x, x)", x: -2, expected: -1},
+		{source: "min(x, 0) + pow(2, 10)", x: 3, expected: 1024},
+		{source: "sigmoid(0) + sign(-3) + abs(-2)", expected: 1.5},
+		{source: "sqrt(16) + log(e) + floor(1.7) + ceil(1.2)", expected: 8},
+		{source: "sin(pi / 2) + cos(0) + tan(0) + atan(0)", expected: 2},
+		{source: "tanh(0) + sinh(0) + cosh(0)", expected: 1},
+		{source: "exp(-(x * a1) ^ 2)", x: 0.5, expected: math.Exp(-1)},
+	}
+	for _, tc := range testCases {
+		expr, err := ParseExpression(tc.source)
+		require.NoError(t, err, tc.source)
+		assert.InDelta(t, tc.expected, expr.Eval(tc.x, aux), 1e-12, tc.source)
+		assert.Equal(t, tc.source, expr.String())
+	}
+}
+
+func TestParseExpression_error(t *testing.T) {
+	testCases := map[string]string{
+		"":                         "unexpected end of expression at: 0",
+		"x +":                      "unexpected end of expression at: 3",
+		"x y":                      "unexpected token: \"y\" at: 2",
+		"(x + 1":                   "expected: \")\" at: 6, found: \"\"",
+		"x $ 2":                    "unexpected character: '$' at: 2",
+		"1.2.3":                    "invalid number: \"1.2.3\" at: 0",
+		"y + 1":                    "unknown variable: y at: 0",
+		"a8":                       "unknown variable: a8 at: 0",
+		"foo(x)":                   "unknown function: foo at: 0",
+		"exp(x, 1)":                "function: exp expects 1 argument, found: 2 at: 0",
+		"max(x)":                   "function: max expects 2 arguments, found: 1 at: 0",
+		"*x":                       "unexpected operator: \"*\" at: 0",
+		strings.Repeat("(", 65):    "the expression nesting is too deep at: 64",
+		strings.Repeat("-", 70):    "the expression nesting is too deep at: 64",
+		strings.Repeat("x+", 1000): "the expression is too long: 2000, maximal allowed: 1024",
+	}
+	for source, expected := range testCases {
+		expr, err := ParseExpression(source)
+		assert.EqualError(t, err, expected, source)
+		assert.Nil(t, expr)
+	}
+}
+
+func TestExpression_ActivationFunction32(t *testing.T) {
+	expr, err := ParseExpression("a0 * x")
+	require.NoError(t, err)
+	fn := expr.ActivationFunction32()
+	assert.Equal(t, float32(3), fn(1.5, []float32{2}))
+	assert.Equal(t, float32(0), fn(1.5, nil))
+}
+
+func TestNodeActivatorsFactory_RegisterCustom(t *testing.T) {
+	factory := NewNodeActivatorsFactory()
+	square := func(input float64, _ []float64) float64 { return input * input }
+	aType, err := factory.RegisterCustom(square, "square")
+	require.NoError(t, err)
+	assert.Equal(t, FirstCustomActivation, aType)
+
+	res, err := factory.ActivateByType(3, nil, aType)
+	require.NoError(t, err)
+	assert.Equal(t, 9.0, res)
+	name, err := factory.ActivationNameFromType(aType)
+	require.NoError(t, err)
+	assert.Equal(t, "square", name)
+
+	// the next type allocated
+	cube := func(input float64, _ []float64) float64 { return input * input * input }
+	cubeType, err := factory.RegisterCustom(cube, "cube")
+	require.NoError(t, err)
+	assert.Equal(t, FirstCustomActivation+1, cubeType)
+
+	// the same name replaces function and keeps type
+	replacedType, err := factory.RegisterCustom(cube, "square")
+	require.NoError(t, err)
+	assert.Equal(t, aType, replacedType)
+	res, err = factory.ActivateByType(3, nil, aType)
+	require.NoError(t, err)
+	assert.Equal(t, 27.0, res)
+}
+
+func TestNodeActivatorsFactory_RegisterCustom_error(t *testing.T) {
+	factory := NewNodeActivatorsFactory()
+	fn := func(input float64, _ []float64) float64 { return input }
+
+	_, err := factory.RegisterCustom(fn, "SigmoidPlainActivation")
+	assert.EqualError(t, err, "can not override built-in activation function: SigmoidPlainActivation")
+	_, err = factory.RegisterCustom(fn, "")
+	assert.EqualError(t, err, "invalid activation function name: \"\"")
+	_, err = factory.RegisterCustom(fn, "my func")
+	assert.EqualError(t, err, "invalid activation function name: \"my func\"")
+
+	// exhaust all types
+	for i := int(FirstCustomActivation); i < math.MaxUint8; i++ {
+		_, err = factory.RegisterCustom(fn, "fn"+string(rune('A'+i%26))+string(rune('A'+i/26)))
+		require.NoError(t, err)
+	}
+	_, err = factory.RegisterCustom(fn, "one_more")
+	assert.EqualError(t, err, "no free activation types left to register activation function: one_more")
+}
+
+func TestRegisterExpressionActivation(t *testing.T) {
+	aType, err := RegisterExpressionActivation("test_swish", "x * sigmoid(a0 * x)")
+	require.NoError(t, err)
+
+	res, err := NodeActivators.ActivateByType(2, []float64{1}, aType)
+	require.NoError(t, err)
+	assert.InDelta(t, 2/(1+math.Exp(-2)), res, 1e-12)
+
+	res32, err := NodeActivators32.ActivateByType(2, []float32{1}, aType)
+	require.NoError(t, err)
+	assert.InDelta(t, 2/(1+math.Exp(-2)), float64(res32), 1e-6)
+
+	byName, err := NodeActivators.ActivationTypeFromName("test_swish")
+	require.NoError(t, err)
+	assert.Equal(t, aType, byName)
+
+	_, err = RegisterExpressionActivation("test_invalid", "x +")
+	assert.EqualError(t, err,
+		"failed to parse expression of activation function: test_invalid, reason: unexpected end of expression at: 3")
+}
+
+func TestRegisterExpressionActivation_concurrent(t *testing.T) {
+	aType, err := RegisterExpressionActivation("test_concurrent", "x")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, err := RegisterExpressionActivation("test_concurrent", "x")
+				assert.NoError(t, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				res, err := NodeActivators.ActivateByType(2, nil, aType)
+				assert.NoError(t, err)
+				assert.Equal(t, 2.0, res)
+				res32, err := NodeActivators32.ActivateByType(2, nil, aType)
+				assert.NoError(t, err)
+				assert.Equal(t, float32(2.0), res32)
+			}
+		}()
+	}
+	wg.Wait()
+}
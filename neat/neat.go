@@ -7,11 +7,15 @@ import (
 	"fmt"
 	"github.com/pkg/errors"
 	"github.com/yaricom/goNEAT/v4/neat/math"
+	gomath "math"
+	"math/rand"
 )
 
 var (
 	ErrNoActivatorsRegistered                = errors.New("no node activators registered with NEAT options, please assign at least one to NodeActivators")
 	ErrActivatorsProbabilitiesNumberMismatch = errors.New("number of node activator probabilities doesn't match number of activators")
+	// ErrAggregatorsProbabilitiesNumberMismatch is returned when number of node aggregator probabilities doesn't match number of aggregators
+	ErrAggregatorsProbabilitiesNumberMismatch = errors.New("number of node aggregator probabilities doesn't match number of aggregators")
 )
 
 // GenomeCompatibilityMethod defines the method to calculate genomes compatibility
@@ -46,6 +50,29 @@ func (e EpochExecutorType) Validate() error {
 	return nil
 }
 
+// WeightDistribution defines the random distribution used to draw values of the connection weights
+type WeightDistribution string
+
+const (
+	// WeightDistributionUniform the values drawn uniformly from the range [-power, power]
+	WeightDistributionUniform WeightDistribution = "uniform"
+	// WeightDistributionGaussian the values drawn from the normal distribution with zero mean and power as sigma
+	WeightDistributionGaussian WeightDistribution = "gaussian"
+	// WeightDistributionFanIn the values drawn from the normal distribution with zero mean and sigma equal to power
+	// divided by square root of the number of incoming connections of the target node. Applicable only for
+	// the weights initialization.
+	WeightDistributionFanIn WeightDistribution = "fan_in"
+)
+
+// Validate is to check if this weight distribution is supported by algorithm. The empty value is allowed and
+// treated as WeightDistributionUniform.
+func (w WeightDistribution) Validate() error {
+	if w != "" && w != WeightDistributionUniform && w != WeightDistributionGaussian && w != WeightDistributionFanIn {
+		return errors.Errorf("unsupported weight distribution: [%s]", w)
+	}
+	return nil
+}
+
 // Options The NEAT algorithm options.
 type Options struct {
 	// Probability of mutating a single trait param
@@ -65,6 +92,9 @@ type Options struct {
 	DisjointCoeff float64 `yaml:"disjoint_coeff"`
 	ExcessCoeff   float64 `yaml:"excess_coeff"`
 	MutdiffCoeff  float64 `yaml:"mutdiff_coeff"`
+	// The importance of difference in aggregation functions of the neuron nodes with the same ID. It is added
+	// to the compatibility as: aggregation_diff_coeff * number of nodes with different aggregation functions.
+	AggregationDiffCoeff float64 `yaml:"aggregation_diff_coeff"`
 
 	// This global tells compatibility threshold under which
 	// two Genomes are considered the same species
@@ -90,6 +120,8 @@ type Options struct {
 	MutateAddLinkProb      float64 `yaml:"mutate_add_link_prob"`
 	// probability of mutation involving disconnected inputs connection
 	MutateConnectSensors float64 `yaml:"mutate_connect_sensors"`
+	// The probability of mutation of the neuron node aggregation function
+	MutateNodeAggregationProb float64 `yaml:"mutate_node_aggregation_prob"`
 
 	// Probabilities of a mate being outside species
 	InterspeciesMateRate  float64 `yaml:"interspecies_mate_rate"`
@@ -102,6 +134,36 @@ type Options struct {
 	// Probability of forcing selection of ONLY links that are naturally recurrent
 	RecurOnlyProb float64 `yaml:"recur_only_prob"`
 
+	// If true, no recurrent links (including self-loops) are allowed to be introduced by mutations and crossover
+	DisallowRecurrentLinks bool `yaml:"disallow_recurrent_links"`
+	// If true, no self-loop links (node connected to itself) are allowed to be introduced by mutations and crossover
+	DisallowSelfLinks bool `yaml:"disallow_self_links"`
+	// The maximal number of nodes in genome. Mutations and crossover will not grow genome above it. Zero means no limit.
+	MaxNodes int `yaml:"max_nodes"`
+	// The maximal number of links (genes) in genome. Mutations and crossover will not grow genome above it.
+	// Zero means no limit.
+	MaxLinks int `yaml:"max_links"`
+
+	// If true, the FS-NEAT feature selection mode is used: each genome of initial population starts with exactly one
+	// link from randomly selected input to randomly selected output, and other inputs get connected through evolution.
+	FeatureSelection bool `yaml:"feature_selection"`
+
+	// The distribution to draw initial weights of new links from (uniform, gaussian, fan_in). Default is uniform.
+	WeightInitDistribution WeightDistribution `yaml:"weight_init_distribution"`
+	// The scale of initial weights distribution. If zero, the scale used historically by specific
+	// genome constructor or mutator is applied.
+	WeightInitPower float64 `yaml:"weight_init_power"`
+	// The distribution to draw weight perturbations from (uniform, gaussian). The WeightMutPower is used as
+	// the range of uniform distribution or as sigma of gaussian. Default is uniform.
+	WeightMutDistribution WeightDistribution `yaml:"weight_mut_distribution"`
+	// The fraction of the mutated link weights to be replaced with new random values rather than perturbed.
+	// If zero, the original NEAT heuristic biased to replacing weights at the tail of genome is used.
+	WeightMutReplaceRate float64 `yaml:"weight_mut_replace_rate"`
+	// The minimal and maximal values of the link weights. The weights are clamped to this range
+	// only if WeightMax is greater than WeightMin.
+	WeightMin float64 `yaml:"weight_min"`
+	WeightMax float64 `yaml:"weight_max"`
+
 	// Size of population
 	PopSize int `yaml:"pop_size"`
 	// Age when Species starts to be penalized
@@ -115,6 +177,21 @@ type Options struct {
 	// The number of babies to stolen off to the champions
 	BabiesStolen int `yaml:"babies_stolen"`
 
+	// The number of the best organisms of each species to be copied unmodified into the next generation. If zero,
+	// the species champion is copied only when the species expects more than five offspring.
+	SpeciesElitism int `yaml:"species_elitism"`
+	// The number of the best organisms of the whole population to be copied unmodified into the next generation
+	PopulationElitism int `yaml:"population_elitism"`
+	// If true, the best genome found so far during the run is re-inserted into the population when it is lost
+	KeepBestEver bool `yaml:"keep_best_ever"`
+	// If true, the loss of elite organisms or of the best species during reproduction causes an error.
+	// Otherwise, the loss is only reported.
+	ElitismStrict bool `yaml:"elitism_strict"`
+
+	// If true, the innovations will be kept for the whole run rather than only for the current generation.
+	// Thus, the same structural innovation occurred in different generations will get the same innovation number.
+	PersistentInnovations bool `yaml:"persistent_innovations"`
+
 	// The number of runs to average over in an experiment
 	NumRuns int `yaml:"num_runs"`
 
@@ -133,11 +210,32 @@ type Options struct {
 
 	// NodeActivatorsWithProbs the list of supported node activation with probability of each one
 	NodeActivatorsWithProbs []string `yaml:"node_activators"`
+	// CustomActivators the list of custom activation functions defined by expressions. They are registered with the
+	// default node activators factory when options loaded and can be referenced by name in the NodeActivatorsWithProbs
+	// and in the genome files.
+	CustomActivators []CustomActivator `yaml:"custom_activators"`
+
+	// The neuron nodes aggregation functions list to choose from
+	NodeAggregators []math.NodeAggregationType `yaml:"-"`
+	// The probabilities of selection of the specific node aggregation function
+	NodeAggregatorsProb []float64 `yaml:"-"`
+
+	// NodeAggregatorsWithProbs the list of supported node aggregation functions with probability of each one
+	NodeAggregatorsWithProbs []string `yaml:"node_aggregators"`
 
 	// LogLevel the log output details level
 	LogLevel string `yaml:"log_level"`
 }
 
+// CustomActivator is the definition of custom activation function by expression. See math.Expression for the syntax
+// of expression.
+type CustomActivator struct {
+	// The unique name of activation function
+	Name string `yaml:"name"`
+	// The expression of activation function of the input x and auxiliary parameters a0..a7 of the node
+	Expr string `yaml:"expr"`
+}
+
 // RandomNodeActivationType Returns next random node activation type among registered with this context
 func (c *Options) RandomNodeActivationType() (math.NodeActivationType, error) {
 	if len(c.NodeActivators) == 0 {
@@ -159,6 +257,69 @@ func (c *Options) RandomNodeActivationType() (math.NodeActivationType, error) {
 	return c.NodeActivators[index], nil
 }
 
+// RandomNodeAggregationType Returns next random node aggregation type among registered with this context. If no
+// aggregators registered the default sum aggregation is returned.
+func (c *Options) RandomNodeAggregationType() (math.NodeAggregationType, error) {
+	// quick check for the most cases
+	if len(c.NodeAggregators) == 0 {
+		return math.SumAggregation, nil
+	}
+	if len(c.NodeAggregators) == 1 {
+		return c.NodeAggregators[0], nil
+	}
+
+	// find random aggregator
+	if len(c.NodeAggregators) != len(c.NodeAggregatorsProb) {
+		return 0, ErrAggregatorsProbabilitiesNumberMismatch
+	}
+	index := math.SingleRouletteThrow(c.NodeAggregatorsProb)
+	if index < 0 || index >= len(c.NodeAggregators) {
+		return 0, fmt.Errorf("unexpected error when trying to find random node aggregator, aggregator index: %d", index)
+	}
+	return c.NodeAggregators[index], nil
+}
+
+// RandomInitialWeight returns new random weight for the link drawn from the configured initial weights
+// distribution. The fanIn is the number of incoming connections of the link's target node. The defaultPower
+// is used as distribution scale if WeightInitPower is not set. The returned weight is clamped.
+func (c *Options) RandomInitialWeight(fanIn int, defaultPower float64) float64 {
+	power := defaultPower
+	if c.WeightInitPower > 0 {
+		power = c.WeightInitPower
+	}
+	var weight float64
+	switch c.WeightInitDistribution {
+	case WeightDistributionGaussian:
+		weight = rand.NormFloat64() * power
+	case WeightDistributionFanIn:
+		if fanIn < 1 {
+			fanIn = 1
+		}
+		weight = rand.NormFloat64() * power / gomath.Sqrt(float64(fanIn))
+	default:
+		weight = float64(math.RandSign()) * rand.Float64() * power
+	}
+	return c.ClampWeight(weight)
+}
+
+// RandomWeightPerturbation returns random weight perturbation drawn from the configured weight mutation distribution
+// scaled by power.
+func (c *Options) RandomWeightPerturbation(power float64) float64 {
+	if c.WeightMutDistribution == WeightDistributionGaussian {
+		return rand.NormFloat64() * power
+	}
+	return float64(math.RandSign()) * rand.Float64() * power
+}
+
+// ClampWeight returns the weight clamped to the [WeightMin, WeightMax] range if it is configured, or unchanged
+// weight otherwise.
+func (c *Options) ClampWeight(weight float64) float64 {
+	if c.WeightMax <= c.WeightMin {
+		return weight
+	}
+	return gomath.Max(c.WeightMin, gomath.Min(c.WeightMax, weight))
+}
+
 // Validate is to validate that this options has valid values
 func (c *Options) Validate() error {
 	if err := c.EpochExecutorType.Validate(); err != nil {
@@ -169,6 +330,31 @@ func (c *Options) Validate() error {
 		return err
 	}
 
+	// check weights options
+	if err := c.WeightInitDistribution.Validate(); err != nil {
+		return err
+	}
+	if err := c.WeightMutDistribution.Validate(); err != nil {
+		return err
+	}
+	if c.WeightMutDistribution == WeightDistributionFanIn {
+		return errors.Errorf("weight distribution: [%s] is not supported for mutations", c.WeightMutDistribution)
+	}
+	if c.WeightMutReplaceRate < 0 || c.WeightMutReplaceRate > 1 {
+		return errors.Errorf("weight replace rate must be in range [0, 1], got: %f", c.WeightMutReplaceRate)
+	}
+	if c.SpeciesElitism < 0 || c.PopulationElitism < 0 {
+		return errors.Errorf("elitism counts must not be negative, species: %d, population: %d",
+			c.SpeciesElitism, c.PopulationElitism)
+	}
+	if c.MaxNodes < 0 || c.MaxLinks < 0 {
+		return errors.Errorf("complexity caps must not be negative, max nodes: %d, max links: %d",
+			c.MaxNodes, c.MaxLinks)
+	}
+	if c.WeightMax < c.WeightMin {
+		return errors.Errorf("max weight: %f is less than min weight: %f", c.WeightMax, c.WeightMin)
+	}
+
 	// check activators
 	if len(c.NodeActivators) == 0 {
 		return ErrNoActivatorsRegistered
@@ -177,6 +363,11 @@ func (c *Options) Validate() error {
 		return ErrActivatorsProbabilitiesNumberMismatch
 	}
 
+	// check aggregators
+	if len(c.NodeAggregators) > 1 && len(c.NodeAggregators) != len(c.NodeAggregatorsProb) {
+		return ErrAggregatorsProbabilitiesNumberMismatch
+	}
+
 	return nil
 }
 
@@ -34,6 +34,11 @@ func LoadYAMLOptions(r io.Reader) (*Options, error) {
 		return nil, errors.Wrap(err, "failed to read node activators")
 	}
 
+	// read node aggregators
+	if err = opts.initNodeAggregators(); err != nil {
+		return nil, errors.Wrap(err, "failed to read node aggregators")
+	}
+
 	if err = opts.Validate(); err != nil {
 		return nil, errors.Wrap(err, "invalid NEAT options")
 	}
@@ -67,6 +72,8 @@ func LoadNeatOptions(r io.Reader) (*Options, error) {
 			c.ExcessCoeff = cast.ToFloat64(param)
 		case "mutdiff_coeff":
 			c.MutdiffCoeff = cast.ToFloat64(param)
+		case "aggregation_diff_coeff":
+			c.AggregationDiffCoeff = cast.ToFloat64(param)
 		case "compat_threshold":
 			c.CompatThreshold = cast.ToFloat64(param)
 		case "age_significance":
@@ -81,6 +88,8 @@ func LoadNeatOptions(r io.Reader) (*Options, error) {
 			c.MutateLinkTraitProb = cast.ToFloat64(param)
 		case "mutate_node_trait_prob":
 			c.MutateNodeTraitProb = cast.ToFloat64(param)
+		case "mutate_node_aggregation_prob":
+			c.MutateNodeAggregationProb = cast.ToFloat64(param)
 		case "mutate_link_weights_prob":
 			c.MutateLinkWeightsProb = cast.ToFloat64(param)
 		case "mutate_toggle_enable_prob":
@@ -105,6 +114,28 @@ func LoadNeatOptions(r io.Reader) (*Options, error) {
 			c.MateOnlyProb = cast.ToFloat64(param)
 		case "recur_only_prob":
 			c.RecurOnlyProb = cast.ToFloat64(param)
+		case "disallow_recurrent_links":
+			c.DisallowRecurrentLinks = cast.ToBool(param)
+		case "disallow_self_links":
+			c.DisallowSelfLinks = cast.ToBool(param)
+		case "max_nodes":
+			c.MaxNodes = cast.ToInt(param)
+		case "max_links":
+			c.MaxLinks = cast.ToInt(param)
+		case "feature_selection":
+			c.FeatureSelection = cast.ToBool(param)
+		case "weight_init_distribution":
+			c.WeightInitDistribution = WeightDistribution(param)
+		case "weight_init_power":
+			c.WeightInitPower = cast.ToFloat64(param)
+		case "weight_mut_distribution":
+			c.WeightMutDistribution = WeightDistribution(param)
+		case "weight_mut_replace_rate":
+			c.WeightMutReplaceRate = cast.ToFloat64(param)
+		case "weight_min":
+			c.WeightMin = cast.ToFloat64(param)
+		case "weight_max":
+			c.WeightMax = cast.ToFloat64(param)
 		case "pop_size":
 			c.PopSize = cast.ToInt(param)
 		case "dropoff_age":
@@ -115,6 +146,16 @@ func LoadNeatOptions(r io.Reader) (*Options, error) {
 			c.PrintEvery = cast.ToInt(param)
 		case "babies_stolen":
 			c.BabiesStolen = cast.ToInt(param)
+		case "species_elitism":
+			c.SpeciesElitism = cast.ToInt(param)
+		case "population_elitism":
+			c.PopulationElitism = cast.ToInt(param)
+		case "keep_best_ever":
+			c.KeepBestEver = cast.ToBool(param)
+		case "elitism_strict":
+			c.ElitismStrict = cast.ToBool(param)
+		case "persistent_innovations":
+			c.PersistentInnovations = cast.ToBool(param)
 		case "num_runs":
 			c.NumRuns = cast.ToInt(param)
 		case "num_generations":
@@ -137,6 +178,9 @@ func LoadNeatOptions(r io.Reader) (*Options, error) {
 	if err := c.initNodeActivators(); err != nil {
 		return nil, err
 	}
+	if err := c.initNodeAggregators(); err != nil {
+		return nil, err
+	}
 	if err := c.Validate(); err != nil {
 		return nil, err
 	}
@@ -160,6 +204,13 @@ func ReadNeatOptionsFromFile(configFilePath string) (*Options, error) {
 
 // set default values for activator type and its probability of selection
 func (c *Options) initNodeActivators() (err error) {
+	// register custom activators to be referenced by name
+	for _, custom := range c.CustomActivators {
+		if _, err = math.RegisterExpressionActivation(custom.Name, custom.Expr); err != nil {
+			return err
+		}
+	}
+
 	if len(c.NodeActivatorsWithProbs) == 0 {
 		c.NodeActivators = []math.NodeActivationType{math.SigmoidSteepenedActivation}
 		c.NodeActivatorsProb = []float64{1.0}
@@ -180,3 +231,29 @@ func (c *Options) initNodeActivators() (err error) {
 	}
 	return nil
 }
+
+// set default values for aggregator type and its probability of selection
+func (c *Options) initNodeAggregators() (err error) {
+	if len(c.NodeAggregatorsWithProbs) == 0 {
+		c.NodeAggregators = []math.NodeAggregationType{math.SumAggregation}
+		c.NodeAggregatorsProb = []float64{1.0}
+		return nil
+	}
+	// create aggregators
+	aggFns := c.NodeAggregatorsWithProbs
+	c.NodeAggregators = make([]math.NodeAggregationType, len(aggFns))
+	c.NodeAggregatorsProb = make([]float64, len(aggFns))
+	for i, line := range aggFns {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return errors.Errorf("invalid node aggregator definition: %s", line)
+		}
+		if c.NodeAggregators[i], err = math.AggregationTypeFromName(fields[0]); err != nil {
+			return err
+		}
+		if c.NodeAggregatorsProb[i], err = strconv.ParseFloat(fields[1], 64); err != nil {
+			return err
+		}
+	}
+	return nil
+}
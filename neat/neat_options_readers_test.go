@@ -5,7 +5,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goNEAT/v4/neat/math"
+	gomath "math"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -62,6 +64,92 @@ func TestLoadYAMLOptions(t *testing.T) {
 	}
 }
 
+func TestLoadYAMLOptions_customActivators(t *testing.T) {
+	content, err := os.ReadFile(xorOptionsFileYaml)
+	require.NoError(t, err)
+	custom := `custom_activators:
+  - name: yaml_swish
+    expr: "x / (1 + exp(-x))"
+  - name: yaml_scaled_tanh
+    expr: "a0 * tanh(a1 * x)"
+node_activators:
+  - yaml_swish 0.5
+  - yaml_scaled_tanh 0.25
+  - SigmoidBipolarActivation 0.25
+`
+	// replace original activators
+	original := `node_activators:
+  - SigmoidBipolarActivation 0.25
+  - GaussianBipolarActivation 0.35
+  - LinearAbsActivation 0.15
+  - SineActivation 0.25`
+	require.Contains(t, string(content), original)
+	yml := strings.Replace(string(content), original, custom, 1)
+
+	opts, err := LoadYAMLOptions(strings.NewReader(yml))
+	require.NoError(t, err, "failed to load options")
+	require.Len(t, opts.CustomActivators, 2)
+	assert.Equal(t, CustomActivator{Name: "yaml_swish", Expr: "x / (1 + exp(-x))"}, opts.CustomActivators[0])
+
+	require.Len(t, opts.NodeActivators, 3)
+	assert.Equal(t, []float64{0.5, 0.25, 0.25}, opts.NodeActivatorsProb)
+	assert.Equal(t, math.SigmoidBipolarActivation, opts.NodeActivators[2])
+
+	res, err := math.NodeActivators.ActivateByType(2, nil, opts.NodeActivators[0])
+	require.NoError(t, err)
+	assert.InDelta(t, 2/(1+gomath.Exp(-2)), res, 1e-12)
+
+	res, err = math.NodeActivators.ActivateByType(0.5, []float64{2, 3}, opts.NodeActivators[1])
+	require.NoError(t, err)
+	assert.InDelta(t, 2*gomath.Tanh(1.5), res, 1e-12)
+}
+
+func TestLoadYAMLOptions_nodeAggregators(t *testing.T) {
+	content, err := os.ReadFile(xorOptionsFileYaml)
+	require.NoError(t, err)
+	aggregators := `mutate_node_aggregation_prob: 0.05
+aggregation_diff_coeff: 0.7
+node_aggregators:
+  - SumAggregation 0.6
+  - MaxAggregation 0.3
+  - MedianAggregation 0.1
+`
+	opts, err := LoadYAMLOptions(strings.NewReader(aggregators + string(content)))
+	require.NoError(t, err, "failed to load options")
+	assert.Equal(t, 0.05, opts.MutateNodeAggregationProb)
+	assert.Equal(t, 0.7, opts.AggregationDiffCoeff)
+	assert.Equal(t, []math.NodeAggregationType{math.SumAggregation, math.MaxAggregation, math.MedianAggregation},
+		opts.NodeAggregators)
+	assert.Equal(t, []float64{0.6, 0.3, 0.1}, opts.NodeAggregatorsProb)
+
+	// check default aggregators
+	opts, err = LoadYAMLOptions(strings.NewReader(string(content)))
+	require.NoError(t, err, "failed to load options")
+	assert.Equal(t, []math.NodeAggregationType{math.SumAggregation}, opts.NodeAggregators)
+	assert.Equal(t, []float64{1.0}, opts.NodeAggregatorsProb)
+
+	// check unsupported aggregator
+	aggregators = `node_aggregators:
+  - SoftmaxAggregation 1.0
+`
+	opts, err = LoadYAMLOptions(strings.NewReader(aggregators + string(content)))
+	assert.EqualError(t, err, "failed to read node aggregators: unsupported aggregation type name: SoftmaxAggregation")
+	assert.Nil(t, opts)
+}
+
+func TestLoadYAMLOptions_customActivatorsError(t *testing.T) {
+	content, err := os.ReadFile(xorOptionsFileYaml)
+	require.NoError(t, err)
+	custom := `custom_activators:
+  - name: yaml_broken
+    expr: "x +* 2"
+`
+	opts, err := LoadYAMLOptions(strings.NewReader(custom + string(content)))
+	assert.EqualError(t, err, "failed to read node activators: failed to parse expression of activation "+
+		"function: yaml_broken, reason: unexpected operator: \"*\" at: 3")
+	assert.Nil(t, opts)
+}
+
 func TestLoadYAMLOptions_readError(t *testing.T) {
 	errorReader := ErrorReader(1)
 	opts, err := LoadYAMLOptions(&errorReader)
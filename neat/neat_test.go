@@ -4,6 +4,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goNEAT/v4/neat/math"
+	"math/rand"
 	"testing"
 )
 
@@ -64,3 +65,133 @@ func TestOptions_RandomNodeActivationType(t *testing.T) {
 	res := activator == math.SigmoidApproximationActivation || activator == math.SigmoidBipolarActivation
 	assert.True(t, res)
 }
+
+func TestOptions_RandomNodeAggregationType(t *testing.T) {
+	// the default sum aggregation when no aggregators registered
+	opts := &Options{}
+	aggregator, err := opts.RandomNodeAggregationType()
+	require.NoError(t, err)
+	assert.Equal(t, math.SumAggregation, aggregator)
+
+	opts.NodeAggregators = []math.NodeAggregationType{math.MedianAggregation}
+	aggregator, err = opts.RandomNodeAggregationType()
+	require.NoError(t, err)
+	assert.Equal(t, math.MedianAggregation, aggregator)
+
+	opts.NodeAggregators = []math.NodeAggregationType{math.MaxAggregation, math.ProductAggregation}
+	opts.NodeAggregatorsProb = []float64{0.5, 0.5}
+	aggregator, err = opts.RandomNodeAggregationType()
+	require.NoError(t, err)
+	res := aggregator == math.MaxAggregation || aggregator == math.ProductAggregation
+	assert.True(t, res)
+
+	opts.NodeAggregatorsProb = []float64{0.5}
+	_, err = opts.RandomNodeAggregationType()
+	assert.EqualError(t, err, ErrAggregatorsProbabilitiesNumberMismatch.Error())
+}
+
+func TestOptions_ClampWeight(t *testing.T) {
+	opts := &Options{}
+	// no bounds set
+	assert.Equal(t, 100.0, opts.ClampWeight(100.0))
+	assert.Equal(t, -100.0, opts.ClampWeight(-100.0))
+
+	opts.WeightMin = -5.0
+	opts.WeightMax = 5.0
+	assert.Equal(t, 5.0, opts.ClampWeight(100.0))
+	assert.Equal(t, -5.0, opts.ClampWeight(-100.0))
+	assert.Equal(t, 1.5, opts.ClampWeight(1.5))
+}
+
+func TestOptions_RandomInitialWeight(t *testing.T) {
+	rand.Seed(42)
+	testCases := []WeightDistribution{"", WeightDistributionUniform, WeightDistributionGaussian, WeightDistributionFanIn}
+	for _, distribution := range testCases {
+		opts := &Options{
+			WeightInitDistribution: distribution,
+			WeightMin:              -2.0,
+			WeightMax:              2.0,
+		}
+		for i := 0; i < 100; i++ {
+			weight := opts.RandomInitialWeight(4, 10.0)
+			assert.True(t, weight >= -2.0 && weight <= 2.0, "weight out of range: %f, distribution: %s", weight, distribution)
+		}
+	}
+
+	// check that uniform is within default power
+	opts := &Options{}
+	for i := 0; i < 100; i++ {
+		weight := opts.RandomInitialWeight(1, 3.0)
+		assert.True(t, weight >= -3.0 && weight <= 3.0, "weight out of range: %f", weight)
+	}
+
+	// check that power from options takes precedence
+	opts.WeightInitPower = 0.5
+	for i := 0; i < 100; i++ {
+		weight := opts.RandomInitialWeight(1, 3.0)
+		assert.True(t, weight >= -0.5 && weight <= 0.5, "weight out of range: %f", weight)
+	}
+}
+
+func TestOptions_RandomInitialWeight_fanIn(t *testing.T) {
+	rand.Seed(42)
+	opts := &Options{WeightInitDistribution: WeightDistributionFanIn}
+	samples := 10000
+	var sumSmall, sumLarge float64
+	for i := 0; i < samples; i++ {
+		w := opts.RandomInitialWeight(1, 1.0)
+		sumSmall += w * w
+		w = opts.RandomInitialWeight(100, 1.0)
+		sumLarge += w * w
+	}
+	// the variance should be scaled by fan-in
+	assert.InDelta(t, 1.0, sumSmall/float64(samples), 0.1)
+	assert.InDelta(t, 0.01, sumLarge/float64(samples), 0.001)
+}
+
+func TestOptions_RandomWeightPerturbation(t *testing.T) {
+	rand.Seed(42)
+	opts := &Options{}
+	for i := 0; i < 100; i++ {
+		value := opts.RandomWeightPerturbation(2.5)
+		assert.True(t, value >= -2.5 && value <= 2.5, "value out of range: %f", value)
+	}
+
+	opts.WeightMutDistribution = WeightDistributionGaussian
+	samples := 10000
+	var sum float64
+	for i := 0; i < samples; i++ {
+		value := opts.RandomWeightPerturbation(2.0)
+		sum += value * value
+	}
+	assert.InDelta(t, 4.0, sum/float64(samples), 0.2)
+}
+
+func TestOptions_Validate_weights(t *testing.T) {
+	newOptions := func() *Options {
+		return &Options{
+			EpochExecutorType:  EpochExecutorTypeSequential,
+			GenCompatMethod:    GenomeCompatibilityMethodFast,
+			NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+			NodeActivatorsProb: []float64{1.0},
+		}
+	}
+	opts := newOptions()
+	assert.NoError(t, opts.Validate())
+
+	opts.WeightInitDistribution = "unknown"
+	assert.EqualError(t, opts.Validate(), "unsupported weight distribution: [unknown]")
+
+	opts = newOptions()
+	opts.WeightMutDistribution = WeightDistributionFanIn
+	assert.EqualError(t, opts.Validate(), "weight distribution: [fan_in] is not supported for mutations")
+
+	opts = newOptions()
+	opts.WeightMutReplaceRate = 1.5
+	assert.Error(t, opts.Validate())
+
+	opts = newOptions()
+	opts.WeightMin = 1.0
+	opts.WeightMax = -1.0
+	assert.Error(t, opts.Validate())
+}
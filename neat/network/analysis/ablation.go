@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"github.com/yaricom/goNEAT/v4/neat/network"
+)
+
+// EvaluateFunc evaluates the network solver and returns its fitness score or any other performance measure
+type EvaluateFunc func(solver network.Solver) (float64, error)
+
+// Ablation disables each input, hidden node, and link of the network in turn and measures the change of the
+// performance returned by the evaluate function. The score of each element is the baseline performance of the intact
+// network minus the performance with element disabled, i.e., the positive score means that element contributes to the
+// performance. The node is disabled by setting the weights of its outgoing links to zero, and the link by setting its
+// weight to zero. The links connecting node with the control nodes of modular network are not disabled. The provided
+// network is not modified.
+func Ablation(net *network.Network, evaluate EvaluateFunc) (*Scores, error) {
+	clone := net.Clone().(*network.Network)
+	baseline, err := evaluate(clone)
+	if err != nil {
+		return nil, err
+	}
+	scores, err := ablate(clone, "ablation", evaluate)
+	if err != nil {
+		return nil, err
+	}
+	for id, value := range scores.Nodes {
+		scores.Nodes[id] = baseline - value
+	}
+	for id, value := range scores.Links {
+		scores.Links[id] = baseline - value
+	}
+	return scores, nil
+}
+
+// OutputAblation disables each input, hidden node, and link of the network in turn as described by Ablation and
+// measures the mean absolute change of the network outputs over provided input samples. The provided network is
+// not modified.
+func OutputAblation(net *network.Network, samples [][]float64, opts Options) (*Scores, error) {
+	if len(samples) == 0 {
+		return nil, ErrNoSamples
+	}
+	clone := net.Clone().(*network.Network)
+	eval := &evaluator{solver: clone, steps: opts.steps(clone)}
+	baseline, err := eval.allOutputs(samples)
+	if err != nil {
+		return nil, err
+	}
+	return ablate(clone, "output_ablation", func(_ network.Solver) (float64, error) {
+		return eval.meanOutputsChange(samples, baseline)
+	})
+}
+
+// ablate disables elements of the network in turn and collects the values returned by the measure function
+func ablate(net *network.Network, name string, measure EvaluateFunc) (*Scores, error) {
+	scores := NewScores(name)
+	for _, node := range net.BaseNodes() {
+		if node.NeuronType == network.OutputNeuron {
+			continue
+		}
+		weights := make([]float64, len(node.Outgoing))
+		for i, l := range node.Outgoing {
+			weights[i] = l.ConnectionWeight
+			l.ConnectionWeight = 0
+		}
+		value, err := measure(net)
+		if err != nil {
+			return nil, err
+		}
+		for i, l := range node.Outgoing {
+			l.ConnectionWeight = weights[i]
+		}
+		scores.Nodes[node.Id] = value
+	}
+
+	for _, node := range net.BaseNodes() {
+		for _, l := range node.Incoming {
+			weight := l.ConnectionWeight
+			l.ConnectionWeight = 0
+			value, err := measure(net)
+			if err != nil {
+				return nil, err
+			}
+			l.ConnectionWeight = weight
+			scores.Links[LinkId{InId: l.InNode.Id, OutId: l.OutNode.Id}] = value
+		}
+	}
+	return scores, nil
+}
@@ -0,0 +1,73 @@
+package analysis
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"testing"
+)
+
+func TestAblation(t *testing.T) {
+	net := buildLinearNetwork()
+	// the performance is the network output for the input {1, 0}
+	evaluate := func(solver network.Solver) (float64, error) {
+		if _, err := solver.Flush(); err != nil {
+			return 0, err
+		}
+		if err := solver.LoadSensors([]float64{1, 0}); err != nil {
+			return 0, err
+		}
+		if _, err := solver.ForwardSteps(3); err != nil {
+			return 0, err
+		}
+		return solver.ReadOutputs()[0], nil
+	}
+	scores, err := Ablation(net, evaluate)
+	require.NoError(t, err)
+
+	expectedNodes := map[int]float64{1: 6, 2: 0, 3: 3, 4: 9}
+	assert.Equal(t, expectedNodes, scores.Nodes)
+	expectedLinks := map[LinkId]float64{
+		{InId: 1, OutId: 4}: 6,
+		{InId: 2, OutId: 4}: 0,
+		{InId: 3, OutId: 4}: 3,
+		{InId: 4, OutId: 5}: 9,
+		{InId: 2, OutId: 5}: 0,
+	}
+	assert.Equal(t, expectedLinks, scores.Links)
+
+	// the network is not modified
+	assert.Equal(t, 2.0, net.BaseNodes()[3].Incoming[0].ConnectionWeight)
+}
+
+func TestAblation_error(t *testing.T) {
+	evalErr := errors.New("evaluation failed")
+	_, err := Ablation(buildLinearNetwork(), func(_ network.Solver) (float64, error) {
+		return 0, evalErr
+	})
+	assert.ErrorIs(t, err, evalErr)
+}
+
+func TestOutputAblation(t *testing.T) {
+	net := buildLinearNetwork()
+	scores, err := OutputAblation(net, testSamples, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "output_ablation", scores.Name())
+
+	expectedNodes := map[int]float64{1: 3, 2: 0.25, 3: 3, 4: 6}
+	assert.Equal(t, expectedNodes, scores.Nodes)
+	expectedLinks := map[LinkId]float64{
+		{InId: 1, OutId: 4}: 3,
+		{InId: 2, OutId: 4}: 0,
+		{InId: 3, OutId: 4}: 3,
+		{InId: 4, OutId: 5}: 6,
+		{InId: 2, OutId: 5}: 0.25,
+	}
+	assert.Equal(t, expectedLinks, scores.Links)
+}
+
+func TestOutputAblation_noSamples(t *testing.T) {
+	_, err := OutputAblation(buildLinearNetwork(), nil, Options{})
+	assert.ErrorIs(t, err, ErrNoSamples)
+}
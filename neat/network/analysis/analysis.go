@@ -0,0 +1,205 @@
+// Package analysis provides tools to explain the behavior of the evolved networks by measuring how much each input,
+// hidden node, and link influences the network outputs or performance. The results are stored as Scores, which can be
+// saved as CSV or mapped onto nodes and edges of the network graph with exporters of the formats package.
+package analysis
+
+import (
+	"encoding/csv"
+	"errors"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// DefaultDelta is the default magnitude of perturbation
+const DefaultDelta = 1e-3
+
+// ErrNoSamples the error to be raised when no input samples provided for analysis
+var ErrNoSamples = errors.New("no input samples provided for analysis")
+
+// Options defines the parameters of the analysis
+type Options struct {
+	// The number of activation steps per input sample. If zero, the number of network nodes is used, which is enough
+	// for the activation wave to pass through any acyclic network.
+	Steps int
+	// The magnitude of perturbation. If zero, the DefaultDelta is used.
+	Delta float64
+}
+
+func (o Options) steps(net *network.Network) int {
+	if o.Steps > 0 {
+		return o.Steps
+	}
+	return net.NodeCount()
+}
+
+func (o Options) delta() float64 {
+	if o.Delta != 0 {
+		return o.Delta
+	}
+	return DefaultDelta
+}
+
+// LinkId identifies the link by IDs of its source and target nodes
+type LinkId struct {
+	// The ID of the source node
+	InId int
+	// The ID of the target node
+	OutId int
+}
+
+// Scores holds the numerical scores of the network nodes and links produced by the analysis
+type Scores struct {
+	// The scores of the nodes by node ID
+	Nodes map[int]float64
+	// The scores of the links
+	Links map[LinkId]float64
+
+	// The name of the analysis produced scores
+	name string
+}
+
+// NewScores creates new empty scores produced by the analysis with given name
+func NewScores(name string) *Scores {
+	return &Scores{
+		Nodes: make(map[int]float64),
+		Links: make(map[LinkId]float64),
+		name:  name,
+	}
+}
+
+// Name returns the name of the analysis produced these scores
+func (s *Scores) Name() string {
+	return s.name
+}
+
+// NodeScore returns the score of the node with given ID and true if node was scored
+func (s *Scores) NodeScore(id int) (float64, bool) {
+	score, ok := s.Nodes[id]
+	return score, ok
+}
+
+// LinkScore returns the score of the link between nodes with given IDs and true if link was scored
+func (s *Scores) LinkScore(inId, outId int) (float64, bool) {
+	score, ok := s.Links[LinkId{InId: inId, OutId: outId}]
+	return score, ok
+}
+
+// WriteCSV writes these scores in the CSV format with columns: element (node or link), id, source, target, and score.
+// The nodes are written first ordered by ID, followed by links ordered by source and target IDs.
+func (s *Scores) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"element", "id", "source", "target", s.name}); err != nil {
+		return err
+	}
+	nodes := make([]int, 0, len(s.Nodes))
+	for id := range s.Nodes {
+		nodes = append(nodes, id)
+	}
+	sort.Ints(nodes)
+	for _, id := range nodes {
+		if err := out.Write([]string{"node", strconv.Itoa(id), "", "", formatFloat(s.Nodes[id])}); err != nil {
+			return err
+		}
+	}
+
+	links := make([]LinkId, 0, len(s.Links))
+	for id := range s.Links {
+		links = append(links, id)
+	}
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].InId == links[j].InId {
+			return links[i].OutId < links[j].OutId
+		}
+		return links[i].InId < links[j].InId
+	})
+	for _, id := range links {
+		record := []string{"link", "", strconv.Itoa(id.InId), strconv.Itoa(id.OutId), formatFloat(s.Links[id])}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// evaluator activates the network with input samples
+type evaluator struct {
+	solver network.Solver
+	steps  int
+}
+
+func (e *evaluator) outputs(sample []float64) ([]float64, error) {
+	if _, err := e.solver.Flush(); err != nil {
+		return nil, err
+	}
+	if err := e.solver.LoadSensors(sample); err != nil {
+		return nil, err
+	}
+	if _, err := e.solver.ForwardSteps(e.steps); err != nil {
+		return nil, err
+	}
+	return e.solver.ReadOutputs(), nil
+}
+
+// allOutputs returns the outputs of the network per each sample
+func (e *evaluator) allOutputs(samples [][]float64) ([][]float64, error) {
+	res := make([][]float64, len(samples))
+	for i, sample := range samples {
+		outputs, err := e.outputs(sample)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = outputs
+	}
+	return res, nil
+}
+
+// meanOutputsChange returns the mean absolute change of the network outputs over all samples
+func (e *evaluator) meanOutputsChange(samples, baseline [][]float64) (float64, error) {
+	change := 0.0
+	for i, sample := range samples {
+		outputs, err := e.outputs(sample)
+		if err != nil {
+			return 0, err
+		}
+		change += meanAbsDiff(outputs, baseline[i])
+	}
+	return change / float64(len(samples)), nil
+}
+
+func meanAbsDiff(a, b []float64) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	diff := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		if d < 0 {
+			d = -d
+		}
+		diff += d
+	}
+	return diff / float64(len(a))
+}
+
+// sampleSensors returns the sensor nodes loaded with the values of the input sample of the given length. The bias
+// nodes are loaded with samples only if sample has values for all sensors.
+func sampleSensors(net *network.Network, sampleLen int) []*network.NNode {
+	inputs := net.InputNodes()
+	if sampleLen == len(inputs) {
+		return inputs
+	}
+	sensors := make([]*network.NNode, 0, len(inputs))
+	for _, node := range inputs {
+		if node.NeuronType == network.InputNeuron {
+			sensors = append(sensors, node)
+		}
+	}
+	return sensors
+}
@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"testing"
+)
+
+// buildLinearNetwork builds the network with linear activation which output is: y = 3 * (2 * x1 + 1) + 0.5 * x2
+func buildLinearNetwork() *network.Network {
+	allNodes := []*network.NNode{
+		network.NewNNode(1, network.InputNeuron),
+		network.NewNNode(2, network.InputNeuron),
+		network.NewNNode(3, network.BiasNeuron),
+		network.NewNNode(4, network.HiddenNeuron),
+		network.NewNNode(5, network.OutputNeuron),
+	}
+	for _, node := range allNodes {
+		node.ActivationType = math.LinearActivation
+	}
+	// HIDDEN 4
+	allNodes[3].ConnectFrom(allNodes[0], 2.0)
+	allNodes[3].ConnectFrom(allNodes[1], 0.0)
+	allNodes[3].ConnectFrom(allNodes[2], 1.0)
+	// OUTPUT 5
+	allNodes[4].ConnectFrom(allNodes[3], 3.0)
+	allNodes[4].ConnectFrom(allNodes[1], 0.5)
+
+	return network.NewNetwork(allNodes[0:3], allNodes[4:5], allNodes, 1)
+}
+
+var testSamples = [][]float64{{1, 0}, {0, 1}}
+
+func TestScores_WriteCSV(t *testing.T) {
+	scores := NewScores("test")
+	scores.Nodes[2] = 0.5
+	scores.Nodes[1] = 1
+	scores.Links[LinkId{InId: 2, OutId: 3}] = 0.25
+	scores.Links[LinkId{InId: 1, OutId: 3}] = -1
+
+	var buf bytes.Buffer
+	err := scores.WriteCSV(&buf)
+	require.NoError(t, err)
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	expected := [][]string{
+		{"element", "id", "source", "target", "test"},
+		{"node", "1", "", "", "1"},
+		{"node", "2", "", "", "0.5"},
+		{"link", "", "1", "3", "-1"},
+		{"link", "", "2", "3", "0.25"},
+	}
+	assert.Equal(t, expected, records)
+}
+
+func TestScores_NodeScore_LinkScore(t *testing.T) {
+	scores := NewScores("test")
+	scores.Nodes[1] = 1
+	scores.Links[LinkId{InId: 1, OutId: 3}] = 2
+	assert.Equal(t, "test", scores.Name())
+
+	score, ok := scores.NodeScore(1)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, score)
+	_, ok = scores.NodeScore(2)
+	assert.False(t, ok)
+
+	score, ok = scores.LinkScore(1, 3)
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, score)
+	_, ok = scores.LinkScore(3, 1)
+	assert.False(t, ok)
+}
+
+func TestOptions_defaults(t *testing.T) {
+	net := buildLinearNetwork()
+	opts := Options{}
+	assert.Equal(t, net.NodeCount(), opts.steps(net))
+	assert.Equal(t, DefaultDelta, opts.delta())
+
+	opts = Options{Steps: 3, Delta: 0.1}
+	assert.Equal(t, 3, opts.steps(net))
+	assert.Equal(t, 0.1, opts.delta())
+}
@@ -0,0 +1,154 @@
+package analysis
+
+import (
+	"github.com/yaricom/goNEAT/v4/neat/network"
+)
+
+// Sensitivity measures how much each input, hidden node, and link influences the network outputs over provided input
+// samples by perturbation. Each element is perturbed in turn by the Delta of the options and the score of the element
+// is the mean absolute change of the outputs divided by the Delta, averaged over all samples:
+//   - the input value of the sensor is shifted by Delta;
+//   - the output of the hidden node is scaled by 1 + Delta through the weights of its outgoing links, i.e., the score
+//     reflects the sensitivity to the relative change of the node output. The links connecting node with the control
+//     nodes of modular network are not perturbed;
+//   - the weight of the link is shifted by Delta.
+//
+// The provided network is not modified.
+func Sensitivity(net *network.Network, samples [][]float64, opts Options) (*Scores, error) {
+	if len(samples) == 0 {
+		return nil, ErrNoSamples
+	}
+	clone := net.Clone().(*network.Network)
+	eval := &evaluator{solver: clone, steps: opts.steps(clone)}
+	baseline, err := eval.allOutputs(samples)
+	if err != nil {
+		return nil, err
+	}
+	delta := opts.delta()
+	scores := NewScores("sensitivity")
+
+	// perturb inputs
+	for i, sensor := range sampleSensors(clone, len(samples[0])) {
+		change := 0.0
+		for j, sample := range samples {
+			perturbed := make([]float64, len(sample))
+			copy(perturbed, sample)
+			perturbed[i] += delta
+			outputs, err := eval.outputs(perturbed)
+			if err != nil {
+				return nil, err
+			}
+			change += meanAbsDiff(outputs, baseline[j])
+		}
+		scores.Nodes[sensor.Id] = change / float64(len(samples)) / delta
+	}
+
+	// perturb hidden nodes
+	for _, node := range clone.BaseNodes() {
+		if node.NeuronType != network.HiddenNeuron {
+			continue
+		}
+		weights := make([]float64, len(node.Outgoing))
+		for i, l := range node.Outgoing {
+			weights[i] = l.ConnectionWeight
+			l.ConnectionWeight *= 1 + delta
+		}
+		change, err := eval.meanOutputsChange(samples, baseline)
+		if err != nil {
+			return nil, err
+		}
+		for i, l := range node.Outgoing {
+			l.ConnectionWeight = weights[i]
+		}
+		scores.Nodes[node.Id] = change / delta
+	}
+
+	// perturb links
+	for _, node := range clone.BaseNodes() {
+		for _, l := range node.Incoming {
+			weight := l.ConnectionWeight
+			l.ConnectionWeight += delta
+			change, err := eval.meanOutputsChange(samples, baseline)
+			if err != nil {
+				return nil, err
+			}
+			l.ConnectionWeight = weight
+			scores.Links[LinkId{InId: l.InNode.Id, OutId: l.OutNode.Id}] = change / delta
+		}
+	}
+	return scores, nil
+}
+
+// Saliency holds the gradients of the network outputs with respect to the inputs estimated by finite differences
+type Saliency struct {
+	// The IDs of the sensor nodes in order of the input sample values
+	InputIds []int
+	// The gradients per sample, per input, and per output
+	Gradients [][][]float64
+}
+
+// Scores returns the saliency scores of the inputs, i.e., the sum of absolute gradients over all outputs averaged over
+// all samples.
+func (s *Saliency) Scores() *Scores {
+	scores := NewScores("saliency")
+	for i, id := range s.InputIds {
+		saliency := 0.0
+		for _, sample := range s.Gradients {
+			for _, gradient := range sample[i] {
+				if gradient < 0 {
+					gradient = -gradient
+				}
+				saliency += gradient
+			}
+		}
+		if len(s.Gradients) > 0 {
+			saliency /= float64(len(s.Gradients))
+		}
+		scores.Nodes[id] = saliency
+	}
+	return scores
+}
+
+// FiniteDifferenceSaliency estimates the gradients of the network outputs with respect to each input at each of the
+// provided samples using the central finite difference with step Delta of the options. The provided network is not
+// modified.
+func FiniteDifferenceSaliency(net *network.Network, samples [][]float64, opts Options) (*Saliency, error) {
+	if len(samples) == 0 {
+		return nil, ErrNoSamples
+	}
+	clone := net.Clone().(*network.Network)
+	eval := &evaluator{solver: clone, steps: opts.steps(clone)}
+	delta := opts.delta()
+
+	sensors := sampleSensors(clone, len(samples[0]))
+	saliency := &Saliency{
+		InputIds:  make([]int, len(sensors)),
+		Gradients: make([][][]float64, len(samples)),
+	}
+	for i, sensor := range sensors {
+		saliency.InputIds[i] = sensor.Id
+	}
+	for s, sample := range samples {
+		saliency.Gradients[s] = make([][]float64, len(sensors))
+		perturbed := make([]float64, len(sample))
+		for i := range sensors {
+			copy(perturbed, sample)
+			perturbed[i] += delta
+			forward, err := eval.outputs(perturbed)
+			if err != nil {
+				return nil, err
+			}
+			perturbed[i] = sample[i] - delta
+			backward, err := eval.outputs(perturbed)
+			if err != nil {
+				return nil, err
+			}
+			gradients := make([]float64, len(forward))
+			for o := range forward {
+				gradients[o] = (forward[o] - backward[o]) / (2 * delta)
+			}
+			saliency.Gradients[s][i] = gradients
+		}
+	}
+	return saliency, nil
+}
@@ -0,0 +1,74 @@
+package analysis
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const delta = 1e-6
+
+func TestSensitivity(t *testing.T) {
+	net := buildLinearNetwork()
+	scores, err := Sensitivity(net, testSamples, Options{})
+	require.NoError(t, err)
+
+	expectedNodes := map[int]float64{1: 6, 2: 0.5, 4: 6}
+	require.Len(t, scores.Nodes, len(expectedNodes))
+	for id, expected := range expectedNodes {
+		assert.InDelta(t, expected, scores.Nodes[id], delta, "wrong score of node: %d", id)
+	}
+	expectedLinks := map[LinkId]float64{
+		{InId: 1, OutId: 4}: 1.5,
+		{InId: 2, OutId: 4}: 1.5,
+		{InId: 3, OutId: 4}: 3,
+		{InId: 4, OutId: 5}: 2,
+		{InId: 2, OutId: 5}: 0.5,
+	}
+	require.Len(t, scores.Links, len(expectedLinks))
+	for id, expected := range expectedLinks {
+		assert.InDelta(t, expected, scores.Links[id], delta, "wrong score of link: %v", id)
+	}
+
+	// the network is not modified
+	assert.Equal(t, 2.0, net.BaseNodes()[3].Incoming[0].ConnectionWeight)
+	assert.Equal(t, 3.0, net.BaseNodes()[4].Incoming[0].ConnectionWeight)
+}
+
+func TestSensitivity_withBias(t *testing.T) {
+	net := buildLinearNetwork()
+	samples := [][]float64{{1, 0, 1}, {0, 1, 1}}
+	scores, err := Sensitivity(net, samples, Options{Delta: 0.01})
+	require.NoError(t, err)
+	assert.InDelta(t, 3, scores.Nodes[3], delta)
+}
+
+func TestSensitivity_noSamples(t *testing.T) {
+	_, err := Sensitivity(buildLinearNetwork(), nil, Options{})
+	assert.ErrorIs(t, err, ErrNoSamples)
+}
+
+func TestFiniteDifferenceSaliency(t *testing.T) {
+	net := buildLinearNetwork()
+	saliency, err := FiniteDifferenceSaliency(net, testSamples, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 2}, saliency.InputIds)
+	require.Len(t, saliency.Gradients, len(testSamples))
+	for s, sample := range saliency.Gradients {
+		require.Len(t, sample, 2)
+		assert.InDeltaSlice(t, []float64{6}, sample[0], delta, "wrong gradient at sample: %d", s)
+		assert.InDeltaSlice(t, []float64{0.5}, sample[1], delta, "wrong gradient at sample: %d", s)
+	}
+
+	scores := saliency.Scores()
+	assert.Equal(t, "saliency", scores.Name())
+	assert.InDelta(t, 6, scores.Nodes[1], delta)
+	assert.InDelta(t, 0.5, scores.Nodes[2], delta)
+	assert.Empty(t, scores.Links)
+}
+
+func TestFiniteDifferenceSaliency_noSamples(t *testing.T) {
+	_, err := FiniteDifferenceSaliency(buildLinearNetwork(), nil, Options{})
+	assert.ErrorIs(t, err, ErrNoSamples)
+}
@@ -17,6 +17,8 @@ var (
 	ErrMaximalNetDepthExceeded = errors.New("depth of the network exceeds maximum allowed, fallback to maximal")
 	// ErrZeroActivationStepsRequested the error to be raised when zero activation steps requested
 	ErrZeroActivationStepsRequested = errors.New("zero activation steps requested")
+	// ErrNetNotFeedForward the error to be raised when feed-forward only activation requested for network with recurrent connections
+	ErrNetNotFeedForward = errors.New("the network has recurrent connections and can not be activated as feed-forward")
 )
 
 // NodeType NNodeType defines the type of NNode to create
@@ -63,7 +63,7 @@ func TestActivateNode(t *testing.T) {
 	err := ActivateNode(node, math.NodeActivators)
 	assert.NoError(t, err)
 
-	node.ActivationType = math.MinModuleActivation + 1
+	node.ActivationType = math.FirstCustomActivation
 	err = ActivateNode(node, math.NodeActivators)
 	assert.EqualError(t, err, fmt.Sprintf("unknown neuron activation type: %d", node.ActivationType))
 }
@@ -78,7 +78,7 @@ func TestActivateModule(t *testing.T) {
 	err := ActivateModule(node, math.NodeActivators)
 	assert.NoError(t, err)
 
-	node.ActivationType = math.MinModuleActivation + 1
+	node.ActivationType = math.FirstCustomActivation
 	err = ActivateModule(node, math.NodeActivators)
 	assert.EqualError(t, err, fmt.Sprintf("unknown module activation type: %d", node.ActivationType))
 
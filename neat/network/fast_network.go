@@ -36,10 +36,8 @@ type FastModularNetworkSolver struct {
 	// Is a name of this network */
 	Name string
 
-	// The current activation values per each neuron
-	neuronSignals []float64
-	// This array is a parallel of neuronSignals and used to test network relaxation
-	neuronSignalsBeingProcessed []float64
+	// The mutable activation state of the solver
+	fastNetworkState
 
 	// The activation functions per neuron, must be in the same order as neuronSignals. Has nil entries for
 	// neurons that are inputs or outputs of a module.
@@ -62,6 +60,38 @@ type FastModularNetworkSolver struct {
 	// The total number of neurons in network
 	totalNeuronCount int
 
+	// The incoming connections of each neuron stored in compressed sparse column (CSC) format. The incoming connections
+	// of the neuron i are stored in the range [incomingOffsets[i], incomingOffsets[i+1]) of incomingSources and
+	// incomingWeights in order of their definition.
+	incomingOffsets []int
+	// The indexes of source neurons of the incoming connections
+	incomingSources []int
+	// The weights of the incoming connections
+	incomingWeights []float64
+
+	// The order of neurons and modules activation for batched inference of feed-forward network
+	batchPlan []fastBatchStep
+	// The error of building batchPlan, i.e., ErrNetNotFeedForward if network has recurrent connections
+	batchPlanErr error
+
+	// The IDs of the network nodes per neuron if solver was created from the network
+	neuronIds []int
+	// The auxiliary parameters of activation functions per neuron, must be in the same order as neuronSignals.
+	// It is nil if none of the neurons has parameters.
+	neuronParams [][]float64
+	// The aggregation functions of input signals per neuron, must be in the same order as neuronSignals.
+	// It is nil if all neurons aggregate input signals by sum.
+	aggregationFunctions []neatmath.NodeAggregationType
+}
+
+// fastNetworkState holds the mutable activation state of the FastModularNetworkSolver, which is separate from the
+// immutable network structure to allow many solvers share the same structure.
+type fastNetworkState struct {
+	// The current activation values per each neuron
+	neuronSignals []float64
+	// This array is a parallel of neuronSignals and used to test network relaxation
+	neuronSignalsBeingProcessed []float64
+
 	// For recursive activation, marks whether we have finished this node yet
 	activated []bool
 	// For recursive activation, makes whether a node is currently being calculated (recurrent connections processing)
@@ -69,10 +99,26 @@ type FastModularNetworkSolver struct {
 	// For recursive activation, the previous activation values of recurrent connections (recurrent connections processing)
 	lastActivation []float64
 
-	// The adjacency list to hold IDs of incoming nodes for each network node
-	reverseAdjacencyList [][]int
-	// The adjacency matrix to hold connection weights between all connected nodes
-	adjacencyMatrix [][]float64
+	// The trace to record activation steps if tracing is enabled
+	trace *ActivationTrace
+	// The activation sums per neuron collected during activation step if tracing is enabled
+	traceSums []float64
+}
+
+// newFastNetworkState allocates the arrays that store the states at different points in the neural network.
+// The neuron signals are initialised to 0 by default. Only bias nodes need setting to 1.
+func newFastNetworkState(biasNeuronCount, totalNeuronCount int) fastNetworkState {
+	state := fastNetworkState{
+		neuronSignals:               make([]float64, totalNeuronCount),
+		neuronSignalsBeingProcessed: make([]float64, totalNeuronCount),
+		activated:                   make([]bool, totalNeuronCount),
+		inActivation:                make([]bool, totalNeuronCount),
+		lastActivation:              make([]float64, totalNeuronCount),
+	}
+	for i := 0; i < biasNeuronCount; i++ {
+		state.neuronSignals[i] = 1.0 // BIAS neuron signal
+	}
+	return state
 }
 
 // NewFastModularNetworkSolver Creates new fast modular network solver
@@ -90,42 +136,77 @@ func NewFastModularNetworkSolver(biasNeuronCount, inputNeuronCount, outputNeuron
 		biasList:            biasList,
 		modules:             modules,
 		connections:         connections,
+		fastNetworkState:    newFastNetworkState(biasNeuronCount, totalNeuronCount),
 	}
 
-	// Allocate the arrays that store the states at different points in the neural network.
-	// The neuron signals are initialised to 0 by default. Only bias nodes need setting to 1.
-	fmm.neuronSignals = make([]float64, totalNeuronCount)
-	fmm.neuronSignalsBeingProcessed = make([]float64, totalNeuronCount)
-	for i := 0; i < biasNeuronCount; i++ {
-		fmm.neuronSignals[i] = 1.0 // BIAS neuron signal
+	// Build sparse storage of incoming connections for fast access of incoming nodes and connection weights
+	fmm.incomingOffsets = make([]int, totalNeuronCount+1)
+	for _, conn := range connections {
+		fmm.incomingOffsets[conn.TargetIndex+1]++
 	}
-
-	// Allocate activation arrays
-	fmm.activated = make([]bool, totalNeuronCount)
-	fmm.inActivation = make([]bool, totalNeuronCount)
-	fmm.lastActivation = make([]float64, totalNeuronCount)
-
-	// Build adjacent lists and matrix for fast access of incoming/outgoing nodes and connection weights
-	fmm.reverseAdjacencyList = make([][]int, totalNeuronCount)
-	fmm.adjacencyMatrix = make([][]float64, totalNeuronCount)
-
 	for i := 0; i < totalNeuronCount; i++ {
-		fmm.reverseAdjacencyList[i] = make([]int, 0)
-		fmm.adjacencyMatrix[i] = make([]float64, totalNeuronCount)
+		fmm.incomingOffsets[i+1] += fmm.incomingOffsets[i]
 	}
-
-	for i := 0; i < len(connections); i++ {
-		crs := connections[i].SourceIndex
-		crt := connections[i].TargetIndex
-		// Holds incoming nodes
-		fmm.reverseAdjacencyList[crt] = append(fmm.reverseAdjacencyList[crt], crs)
-		// Holds link weight
-		fmm.adjacencyMatrix[crs][crt] = connections[i].Weight
+	fmm.incomingSources = make([]int, len(connections))
+	fmm.incomingWeights = make([]float64, len(connections))
+	next := make([]int, totalNeuronCount)
+	copy(next, fmm.incomingOffsets[:totalNeuronCount])
+	for _, conn := range connections {
+		pos := next[conn.TargetIndex]
+		fmm.incomingSources[pos] = conn.SourceIndex
+		fmm.incomingWeights[pos] = conn.Weight
+		next[conn.TargetIndex]++
 	}
 
+	// Build the activation plan for batched inference, it is immutable and shared with clones as other structure
+	fmm.batchPlan, fmm.batchPlanErr = fmm.buildBatchPlan()
+
 	return &fmm
 }
 
+// FastModularNetworkStructure describes the structure of the FastModularNetworkSolver, i.e., the neurons with their
+// activation functions and biases, the connections, and the modules. The neurons are indexed in order: bias, input,
+// output, and hidden.
+type FastModularNetworkStructure struct {
+	// The bias neuron count (usually one)
+	BiasNeuronCount int
+	// The number of input neurons
+	InputNeuronCount int
+	// The number of output neurons
+	OutputNeuronCount int
+	// The total number of neurons in network
+	TotalNeuronCount int
+	// The activation functions per neuron
+	ActivationFunctions []neatmath.NodeActivationType
+	// The bias values associated with neurons
+	BiasList []float64
+	// The connections
+	Connections []*FastNetworkLink
+	// The control nodes relaying between network modules
+	Modules []*FastControlNode
+	// The auxiliary parameters of activation functions per neuron, can be nil if neurons have no parameters
+	NeuronParams [][]float64
+	// The aggregation functions of input signals per neuron, can be nil if all neurons aggregate inputs by sum
+	AggregationFunctions []neatmath.NodeAggregationType
+}
+
+// Structure returns the structure of this solver. The returned structure shares data with the solver and must not
+// be modified.
+func (s *FastModularNetworkSolver) Structure() FastModularNetworkStructure {
+	return FastModularNetworkStructure{
+		BiasNeuronCount:      s.biasNeuronCount,
+		InputNeuronCount:     s.inputNeuronCount,
+		OutputNeuronCount:    s.outputNeuronCount,
+		TotalNeuronCount:     s.totalNeuronCount,
+		ActivationFunctions:  s.activationFunctions,
+		BiasList:             s.biasList,
+		Connections:          s.connections,
+		Modules:              s.modules,
+		NeuronParams:         s.neuronParams,
+		AggregationFunctions: s.aggregationFunctions,
+	}
+}
+
 func (s *FastModularNetworkSolver) ForwardSteps(steps int) (res bool, err error) {
 	for i := 0; i < steps; i++ {
 		if res, err = s.forwardStep(0); err != nil {
@@ -162,6 +243,12 @@ func (s *FastModularNetworkSolver) RecursiveSteps() (res bool, err error) {
 		}
 	}
 
+	if s.trace != nil {
+		copy(s.traceSums[s.sensorNeuronCount:], s.neuronSignalsBeingProcessed[s.sensorNeuronCount:])
+		if err = s.recordActivationStep(s.traceLinkSignals()); err != nil {
+			return false, err
+		}
+	}
 	return res, nil
 }
 
@@ -178,14 +265,25 @@ func (s *FastModularNetworkSolver) recursiveActivateNode(currentNode int) (res b
 	// Set the pre-signal to 0
 	s.neuronSignalsBeingProcessed[currentNode] = 0
 
-	// Adjacency list in reverse holds incoming connections, go through each one and activate it
-	for i := 0; i < len(s.reverseAdjacencyList[currentNode]); i++ {
-		currentAdjNode := s.reverseAdjacencyList[currentNode][i]
+	// The input signals to be aggregated by the function other than sum
+	aggregation := s.aggregation(currentNode)
+	var inputs []float64
+	if aggregation != neatmath.SumAggregation {
+		inputs = make([]float64, 0, s.incomingOffsets[currentNode+1]-s.incomingOffsets[currentNode])
+	}
+
+	// Go through each incoming connection and activate it
+	for i := s.incomingOffsets[currentNode]; i < s.incomingOffsets[currentNode+1]; i++ {
+		currentAdjNode := s.incomingSources[i]
 
 		// If this node is currently being activated then we have reached a cycle, or recurrent connection.
 		// Use the previous activation in this case
 		if s.inActivation[currentAdjNode] {
-			s.neuronSignalsBeingProcessed[currentNode] += s.lastActivation[currentAdjNode] * s.adjacencyMatrix[currentAdjNode][currentNode]
+			if inputs != nil {
+				inputs = append(inputs, s.lastActivation[currentAdjNode]*s.incomingWeights[i])
+			} else {
+				s.neuronSignalsBeingProcessed[currentNode] += s.lastActivation[currentAdjNode] * s.incomingWeights[i]
+			}
 		} else {
 			// Otherwise, proceed as normal
 			// Recurse if this neuron has not been activated yet
@@ -200,7 +298,16 @@ func (s *FastModularNetworkSolver) recursiveActivateNode(currentNode int) (res b
 			}
 
 			// Add it to the new activation
-			s.neuronSignalsBeingProcessed[currentNode] += s.neuronSignals[currentAdjNode] * s.adjacencyMatrix[currentAdjNode][currentNode]
+			if inputs != nil {
+				inputs = append(inputs, s.neuronSignals[currentAdjNode]*s.incomingWeights[i])
+			} else {
+				s.neuronSignalsBeingProcessed[currentNode] += s.neuronSignals[currentAdjNode] * s.incomingWeights[i]
+			}
+		}
+	}
+	if inputs != nil {
+		if s.neuronSignalsBeingProcessed[currentNode], err = neatmath.Aggregate(aggregation, inputs); err != nil {
+			return false, err
 		}
 	}
 
@@ -212,7 +319,7 @@ func (s *FastModularNetworkSolver) recursiveActivateNode(currentNode int) (res b
 
 	// Set this signal after running it through the activation function
 	if s.neuronSignals[currentNode], err = neatmath.NodeActivators.ActivateByType(
-		s.neuronSignalsBeingProcessed[currentNode], nil,
+		s.neuronSignalsBeingProcessed[currentNode], s.auxParams(currentNode),
 		s.activationFunctions[currentNode]); err != nil {
 		// failed to activate
 		res = false
@@ -222,6 +329,22 @@ func (s *FastModularNetworkSolver) recursiveActivateNode(currentNode int) (res b
 	return res, err
 }
 
+// Returns the auxiliary parameters of activation function of the neuron with given index
+func (s *FastModularNetworkSolver) auxParams(neuron int) []float64 {
+	if s.neuronParams == nil {
+		return nil
+	}
+	return s.neuronParams[neuron]
+}
+
+// Returns the aggregation function of input signals of the neuron with given index
+func (s *FastModularNetworkSolver) aggregation(neuron int) neatmath.NodeAggregationType {
+	if s.aggregationFunctions == nil {
+		return neatmath.SumAggregation
+	}
+	return s.aggregationFunctions[neuron]
+}
+
 func (s *FastModularNetworkSolver) Relax(maxSteps int, maxAllowedSignalDelta float64) (relaxed bool, err error) {
 	for i := 0; i < maxSteps; i++ {
 		if relaxed, err = s.forwardStep(maxAllowedSignalDelta); err != nil {
@@ -237,22 +360,37 @@ func (s *FastModularNetworkSolver) Relax(maxSteps int, maxAllowedSignalDelta flo
 // when absolute value of the change at any given point is less than maxAllowedSignalDelta during activation waves propagation.
 func (s *FastModularNetworkSolver) forwardStep(maxAllowedSignalDelta float64) (isRelaxed bool, err error) {
 	isRelaxed = true
-
-	// Calculate output signal per each connection and add the signals to the target neurons
-	for _, conn := range s.connections {
-		s.neuronSignalsBeingProcessed[conn.TargetIndex] += s.neuronSignals[conn.SourceIndex] * conn.Weight
-	}
+	signals := s.traceLinkSignals()
 
 	// Pass the signals through the single-valued activation functions
 	for i := s.sensorNeuronCount; i < s.totalNeuronCount; i++ {
+		// Calculate output signal per each incoming connection and add the signals to the target neuron
 		signal := s.neuronSignalsBeingProcessed[i]
+		if aggregation := s.aggregation(i); aggregation == neatmath.SumAggregation {
+			for j := s.incomingOffsets[i]; j < s.incomingOffsets[i+1]; j++ {
+				signal += s.neuronSignals[s.incomingSources[j]] * s.incomingWeights[j]
+			}
+		} else {
+			inputs := make([]float64, 0, s.incomingOffsets[i+1]-s.incomingOffsets[i])
+			for j := s.incomingOffsets[i]; j < s.incomingOffsets[i+1]; j++ {
+				inputs = append(inputs, s.neuronSignals[s.incomingSources[j]]*s.incomingWeights[j])
+			}
+			value, err := neatmath.Aggregate(aggregation, inputs)
+			if err != nil {
+				return false, err
+			}
+			signal += value
+		}
 		if s.biasNeuronCount > 0 {
 			// append BIAS value to the signal if appropriate
 			signal += s.biasList[i]
 		}
+		if s.trace != nil {
+			s.traceSums[i] = signal
+		}
 
 		if s.neuronSignalsBeingProcessed[i], err = neatmath.NodeActivators.ActivateByType(
-			signal, nil, s.activationFunctions[i]); err != nil {
+			signal, s.auxParams(i), s.activationFunctions[i]); err != nil {
 			return false, err
 		}
 	}
@@ -290,6 +428,9 @@ func (s *FastModularNetworkSolver) forwardStep(maxAllowedSignalDelta float64) (i
 		}
 	}
 
+	if err = s.recordActivationStep(signals); err != nil {
+		return false, err
+	}
 	return isRelaxed, err
 }
 
@@ -301,6 +442,37 @@ func (s *FastModularNetworkSolver) Flush() (bool, error) {
 	return true, nil
 }
 
+// Reset resets the activation state of the solver to the initial state including the loaded sensors values and the
+// memory of the recursive activation. The activation trace, if set, is not affected.
+func (s *FastModularNetworkSolver) Reset() error {
+	state := newFastNetworkState(s.biasNeuronCount, s.totalNeuronCount)
+	state.trace, state.traceSums = s.trace, s.traceSums
+	s.fastNetworkState = state
+	return nil
+}
+
+// State returns the snapshot of the neuron signals and the values of neurons being processed.
+func (s *FastModularNetworkSolver) State() *SolverState {
+	state := &SolverState{
+		Solver:     FastModularNetworkSolverName,
+		Signals:    make([]float64, s.totalNeuronCount),
+		Processing: make([]float64, s.totalNeuronCount),
+	}
+	copy(state.Signals, s.neuronSignals)
+	copy(state.Processing, s.neuronSignalsBeingProcessed)
+	return state
+}
+
+// SetState restores the neuron signals and the values of neurons being processed from provided snapshot.
+func (s *FastModularNetworkSolver) SetState(state *SolverState) error {
+	if err := state.checkSignals(FastModularNetworkSolverName, s.totalNeuronCount, true); err != nil {
+		return err
+	}
+	copy(s.neuronSignals, state.Signals)
+	copy(s.neuronSignalsBeingProcessed, state.Processing)
+	return nil
+}
+
 func (s *FastModularNetworkSolver) LoadSensors(inputs []float64) error {
 	if len(inputs) == s.inputNeuronCount {
 		// only inputs should be provided
@@ -320,6 +492,14 @@ func (s *FastModularNetworkSolver) ReadOutputs() []float64 {
 	return outs
 }
 
+// Clone returns new solver which shares the immutable network structure with this solver, and has its own
+// activation state.
+func (s *FastModularNetworkSolver) Clone() Solver {
+	clone := *s
+	clone.fastNetworkState = newFastNetworkState(s.biasNeuronCount, s.totalNeuronCount)
+	return &clone
+}
+
 func (s *FastModularNetworkSolver) NodeCount() int {
 	return s.totalNeuronCount + len(s.modules)
 }
@@ -0,0 +1,477 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	neatmath "github.com/yaricom/goNEAT/v4/neat/math"
+	"math"
+)
+
+// FastModularNetworkSolver32 is the single precision variant of the FastModularNetworkSolver. It stores the neuron
+// signals, connection weights, and biases as float32 values and uses single precision activation functions, which
+// halves the memory bandwidth required to activate large networks. The results differ from the results of the
+// FastModularNetworkSolver only by the rounding errors of the single precision arithmetic. The values are converted
+// to and from float64 when loading sensors and reading outputs.
+type FastModularNetworkSolver32 struct {
+	// A network id
+	Id int
+	// Is a name of this network */
+	Name string
+
+	// The mutable activation state of the solver
+	fastNetworkState32
+
+	// The activation functions per neuron, must be in the same order as neuronSignals.
+	activationFunctions []neatmath.NodeActivationType
+	// The bias values associated with neurons
+	biasList []float32
+	// The control nodes relaying between network modules
+	modules []*FastControlNode
+	// The number of connections
+	connectionCount int
+
+	// The number of input neurons
+	inputNeuronCount int
+	// The total number of sensors in the network (input + bias). This is also the index of the first output neuron in the neuron signals.
+	sensorNeuronCount int
+	// The number of output neurons
+	outputNeuronCount int
+	// The bias neuron count (usually one). This is also the index of the first input neuron in the neuron signals.
+	biasNeuronCount int
+	// The total number of neurons in network
+	totalNeuronCount int
+
+	// The incoming connections of each neuron stored in compressed sparse column (CSC) format. See
+	// FastModularNetworkSolver for details.
+	incomingOffsets []int32
+	// The indexes of source neurons of the incoming connections
+	incomingSources []int32
+	// The weights of the incoming connections
+	incomingWeights []float32
+
+	// The auxiliary parameters of activation functions per neuron. It is nil if none of the neurons has parameters.
+	neuronParams [][]float32
+	// The aggregation functions of input signals per neuron. It is nil if all neurons aggregate inputs by sum.
+	aggregationFunctions []neatmath.NodeAggregationType
+}
+
+// fastNetworkState32 holds the mutable activation state of the FastModularNetworkSolver32
+type fastNetworkState32 struct {
+	// The current activation values per each neuron
+	neuronSignals []float32
+	// This array is a parallel of neuronSignals and used to test network relaxation
+	neuronSignalsBeingProcessed []float32
+
+	// For recursive activation, marks whether we have finished this node yet
+	activated []bool
+	// For recursive activation, makes whether a node is currently being calculated (recurrent connections processing)
+	inActivation []bool
+	// For recursive activation, the previous activation values of recurrent connections (recurrent connections processing)
+	lastActivation []float32
+}
+
+// newFastNetworkState32 allocates the arrays that store the states at different points in the neural network.
+// The neuron signals are initialised to 0 by default. Only bias nodes need setting to 1.
+func newFastNetworkState32(biasNeuronCount, totalNeuronCount int) fastNetworkState32 {
+	state := fastNetworkState32{
+		neuronSignals:               make([]float32, totalNeuronCount),
+		neuronSignalsBeingProcessed: make([]float32, totalNeuronCount),
+		activated:                   make([]bool, totalNeuronCount),
+		inActivation:                make([]bool, totalNeuronCount),
+		lastActivation:              make([]float32, totalNeuronCount),
+	}
+	for i := 0; i < biasNeuronCount; i++ {
+		state.neuronSignals[i] = 1.0 // BIAS neuron signal
+	}
+	return state
+}
+
+// NewFastModularNetworkSolver32 Creates new single precision fast modular network solver with provided structure.
+// The weights and biases of the structure are rounded to single precision, and the structure data is not shared
+// with the created solver except the modules descriptors.
+func NewFastModularNetworkSolver32(structure FastModularNetworkStructure) (*FastModularNetworkSolver32, error) {
+	totalNeuronCount := structure.TotalNeuronCount
+	if totalNeuronCount > math.MaxInt32 || len(structure.Connections) > math.MaxInt32 {
+		return nil, fmt.Errorf("the network is too large for single precision solver, neurons: %d, connections: %d",
+			totalNeuronCount, len(structure.Connections))
+	}
+	if len(structure.ActivationFunctions) != totalNeuronCount || len(structure.BiasList) != totalNeuronCount {
+		return nil, errors.New("the number of activation functions and biases must be equal to the total number of neurons")
+	}
+	if structure.NeuronParams != nil && len(structure.NeuronParams) != totalNeuronCount {
+		return nil, errors.New("the number of neuron parameters must be equal to the total number of neurons")
+	}
+	if structure.AggregationFunctions != nil && len(structure.AggregationFunctions) != totalNeuronCount {
+		return nil, errors.New("the number of aggregation functions must be equal to the total number of neurons")
+	}
+
+	fmm := FastModularNetworkSolver32{
+		biasNeuronCount:      structure.BiasNeuronCount,
+		inputNeuronCount:     structure.InputNeuronCount,
+		sensorNeuronCount:    structure.BiasNeuronCount + structure.InputNeuronCount,
+		outputNeuronCount:    structure.OutputNeuronCount,
+		totalNeuronCount:     totalNeuronCount,
+		activationFunctions:  structure.ActivationFunctions,
+		aggregationFunctions: structure.AggregationFunctions,
+		biasList:             make([]float32, totalNeuronCount),
+		modules:              structure.Modules,
+		connectionCount:      len(structure.Connections),
+		fastNetworkState32:   newFastNetworkState32(structure.BiasNeuronCount, totalNeuronCount),
+	}
+	for i, bias := range structure.BiasList {
+		fmm.biasList[i] = float32(bias)
+	}
+	if structure.NeuronParams != nil {
+		fmm.neuronParams = make([][]float32, totalNeuronCount)
+		for i, params := range structure.NeuronParams {
+			if len(params) == 0 {
+				continue
+			}
+			fmm.neuronParams[i] = make([]float32, len(params))
+			for j, p := range params {
+				fmm.neuronParams[i][j] = float32(p)
+			}
+		}
+	}
+
+	// Build sparse storage of incoming connections for fast access of incoming nodes and connection weights
+	fmm.incomingOffsets = make([]int32, totalNeuronCount+1)
+	for _, conn := range structure.Connections {
+		if conn.TargetIndex < 0 || conn.TargetIndex >= totalNeuronCount ||
+			conn.SourceIndex < 0 || conn.SourceIndex >= totalNeuronCount {
+			return nil, fmt.Errorf("connection index out of range, source: %d, target: %d",
+				conn.SourceIndex, conn.TargetIndex)
+		}
+		fmm.incomingOffsets[conn.TargetIndex+1]++
+	}
+	for i := 0; i < totalNeuronCount; i++ {
+		fmm.incomingOffsets[i+1] += fmm.incomingOffsets[i]
+	}
+	fmm.incomingSources = make([]int32, len(structure.Connections))
+	fmm.incomingWeights = make([]float32, len(structure.Connections))
+	next := make([]int32, totalNeuronCount)
+	copy(next, fmm.incomingOffsets[:totalNeuronCount])
+	for _, conn := range structure.Connections {
+		pos := next[conn.TargetIndex]
+		fmm.incomingSources[pos] = int32(conn.SourceIndex)
+		fmm.incomingWeights[pos] = float32(conn.Weight)
+		next[conn.TargetIndex]++
+	}
+
+	return &fmm, nil
+}
+
+func (s *FastModularNetworkSolver32) ForwardSteps(steps int) (res bool, err error) {
+	for i := 0; i < steps; i++ {
+		if res, err = s.forwardStep(0); err != nil {
+			return false, err
+		}
+	}
+	return res, nil
+}
+
+func (s *FastModularNetworkSolver32) RecursiveSteps() (res bool, err error) {
+	if len(s.modules) > 0 {
+		return false, errors.New("recursive activation can not be used for network with defined modules")
+	}
+
+	// Initialize boolean arrays and set the last activation signal for output/hidden neurons
+	for i := 0; i < s.totalNeuronCount; i++ {
+		// Set as activated if i is an input node, otherwise ensure it is unactivated (false)
+		s.activated[i] = i < s.sensorNeuronCount
+
+		s.inActivation[i] = false
+		// set last activation for output/hidden neurons
+		if i >= s.sensorNeuronCount {
+			s.lastActivation[i] = s.neuronSignals[i]
+		}
+	}
+
+	// Get each output node activation recursively
+	for i := 0; i < s.outputNeuronCount; i++ {
+		index := s.sensorNeuronCount + i
+		if res, err = s.recursiveActivateNode(index); err != nil {
+			return false, err
+		} else if !res {
+			return false, fmt.Errorf("failed to recursively activate the output neuron at %d", index)
+		}
+	}
+	return res, nil
+}
+
+// Propagate activation wave by recursively looking for input signals graph for a given output neuron
+func (s *FastModularNetworkSolver32) recursiveActivateNode(currentNode int) (res bool, err error) {
+	// If we've reached an input node then return since the signal is already set
+	if s.activated[currentNode] {
+		s.inActivation[currentNode] = false
+		return true, nil
+	}
+	// Mark that the node is currently being calculated
+	s.inActivation[currentNode] = true
+
+	// Set the pre-signal to 0
+	s.neuronSignalsBeingProcessed[currentNode] = 0
+
+	// The input signals to be aggregated by the function other than sum
+	aggregation := s.aggregation(currentNode)
+	var inputs []float32
+	if aggregation != neatmath.SumAggregation {
+		inputs = make([]float32, 0, s.incomingOffsets[currentNode+1]-s.incomingOffsets[currentNode])
+	}
+
+	// Go through each incoming connection and activate it
+	for i := s.incomingOffsets[currentNode]; i < s.incomingOffsets[currentNode+1]; i++ {
+		currentAdjNode := s.incomingSources[i]
+
+		// If this node is currently being activated then we have reached a cycle, or recurrent connection.
+		// Use the previous activation in this case
+		if s.inActivation[currentAdjNode] {
+			if inputs != nil {
+				inputs = append(inputs, s.lastActivation[currentAdjNode]*s.incomingWeights[i])
+			} else {
+				s.neuronSignalsBeingProcessed[currentNode] += s.lastActivation[currentAdjNode] * s.incomingWeights[i]
+			}
+		} else {
+			// Otherwise, proceed as normal
+			// Recurse if this neuron has not been activated yet
+			if !s.activated[currentAdjNode] {
+				res, err = s.recursiveActivateNode(int(currentAdjNode))
+				if err != nil {
+					// recursive activation failed
+					return false, err
+				} else if !res {
+					return false, fmt.Errorf("failed to recursively activate neuron at %d", currentAdjNode)
+				}
+			}
+
+			// Add it to the new activation
+			if inputs != nil {
+				inputs = append(inputs, s.neuronSignals[currentAdjNode]*s.incomingWeights[i])
+			} else {
+				s.neuronSignalsBeingProcessed[currentNode] += s.neuronSignals[currentAdjNode] * s.incomingWeights[i]
+			}
+		}
+	}
+	if inputs != nil {
+		if s.neuronSignalsBeingProcessed[currentNode], err = neatmath.Aggregate32(aggregation, inputs); err != nil {
+			return false, err
+		}
+	}
+
+	// Mark this neuron as completed
+	s.activated[currentNode] = true
+
+	// This is no longer being calculated (for cycle detection)
+	s.inActivation[currentNode] = false
+
+	// Set this signal after running it through the activation function
+	if s.neuronSignals[currentNode], err = neatmath.NodeActivators32.ActivateByType(
+		s.neuronSignalsBeingProcessed[currentNode], s.auxParams(currentNode),
+		s.activationFunctions[currentNode]); err != nil {
+		// failed to activate
+		res = false
+	} else {
+		res = true
+	}
+	return res, err
+}
+
+// Returns the auxiliary parameters of activation function of the neuron with given index
+func (s *FastModularNetworkSolver32) auxParams(neuron int) []float32 {
+	if s.neuronParams == nil {
+		return nil
+	}
+	return s.neuronParams[neuron]
+}
+
+// Returns the aggregation function of input signals of the neuron with given index
+func (s *FastModularNetworkSolver32) aggregation(neuron int) neatmath.NodeAggregationType {
+	if s.aggregationFunctions == nil {
+		return neatmath.SumAggregation
+	}
+	return s.aggregationFunctions[neuron]
+}
+
+func (s *FastModularNetworkSolver32) Relax(maxSteps int, maxAllowedSignalDelta float64) (relaxed bool, err error) {
+	for i := 0; i < maxSteps; i++ {
+		if relaxed, err = s.forwardStep(maxAllowedSignalDelta); err != nil {
+			return false, err
+		} else if relaxed {
+			break // no need to iterate any further, already reached desired accuracy
+		}
+	}
+	return relaxed, nil
+}
+
+// Performs single forward step through the network and tests if network become relaxed. The network considered relaxed
+// when absolute value of the change at any given point is less than maxAllowedSignalDelta during activation waves propagation.
+func (s *FastModularNetworkSolver32) forwardStep(maxAllowedSignalDelta float64) (isRelaxed bool, err error) {
+	isRelaxed = true
+
+	// Pass the signals through the single-valued activation functions
+	for i := s.sensorNeuronCount; i < s.totalNeuronCount; i++ {
+		// Calculate output signal per each incoming connection and add the signals to the target neuron
+		signal := s.neuronSignalsBeingProcessed[i]
+		if aggregation := s.aggregation(i); aggregation == neatmath.SumAggregation {
+			for j := s.incomingOffsets[i]; j < s.incomingOffsets[i+1]; j++ {
+				signal += s.neuronSignals[s.incomingSources[j]] * s.incomingWeights[j]
+			}
+		} else {
+			inputs := make([]float32, 0, s.incomingOffsets[i+1]-s.incomingOffsets[i])
+			for j := s.incomingOffsets[i]; j < s.incomingOffsets[i+1]; j++ {
+				inputs = append(inputs, s.neuronSignals[s.incomingSources[j]]*s.incomingWeights[j])
+			}
+			value, err := neatmath.Aggregate32(aggregation, inputs)
+			if err != nil {
+				return false, err
+			}
+			signal += value
+		}
+		if s.biasNeuronCount > 0 {
+			// append BIAS value to the signal if appropriate
+			signal += s.biasList[i]
+		}
+
+		if s.neuronSignalsBeingProcessed[i], err = neatmath.NodeActivators32.ActivateByType(
+			signal, s.auxParams(i), s.activationFunctions[i]); err != nil {
+			return false, err
+		}
+	}
+
+	// Pass the signals through each module (activation function with more than one input or output)
+	for _, module := range s.modules {
+		inputs := make([]float32, len(module.InputIndexes))
+		for i, inIndex := range module.InputIndexes {
+			inputs[i] = s.neuronSignalsBeingProcessed[inIndex]
+		}
+		if outputs, err := neatmath.NodeActivators32.ActivateModuleByType(inputs, nil, module.ActivationType); err == nil {
+			// save outputs
+			for i, outIndex := range module.OutputIndexes {
+				s.neuronSignalsBeingProcessed[outIndex] = outputs[i]
+			}
+		} else {
+			return false, err
+		}
+	}
+
+	// Move all the neuron signals we changed while processing this network activation into storage.
+	if maxAllowedSignalDelta <= 0 {
+		// iterate through output and hidden neurons and collect activations
+		for i := s.sensorNeuronCount; i < s.totalNeuronCount; i++ {
+			s.neuronSignals[i] = s.neuronSignalsBeingProcessed[i]
+			s.neuronSignalsBeingProcessed[i] = 0
+		}
+	} else {
+		for i := s.sensorNeuronCount; i < s.totalNeuronCount; i++ {
+			// First check whether any location in the network has changed by more than a small amount.
+			delta := math.Abs(float64(s.neuronSignals[i] - s.neuronSignalsBeingProcessed[i]))
+			isRelaxed = isRelaxed && !(delta > maxAllowedSignalDelta)
+
+			s.neuronSignals[i] = s.neuronSignalsBeingProcessed[i]
+			s.neuronSignalsBeingProcessed[i] = 0
+		}
+	}
+	return isRelaxed, nil
+}
+
+func (s *FastModularNetworkSolver32) Flush() (bool, error) {
+	for i := s.biasNeuronCount; i < s.totalNeuronCount; i++ {
+		s.neuronSignals[i] = 0.0
+		s.neuronSignalsBeingProcessed[i] = 0.0
+	}
+	return true, nil
+}
+
+// Reset resets the activation state of the solver to the initial state including the loaded sensors values and the
+// memory of the recursive activation.
+func (s *FastModularNetworkSolver32) Reset() error {
+	s.fastNetworkState32 = newFastNetworkState32(s.biasNeuronCount, s.totalNeuronCount)
+	return nil
+}
+
+// State returns the snapshot of the neuron signals and the values of neurons being processed. The single precision
+// values are stored as float64 values without loss of precision.
+func (s *FastModularNetworkSolver32) State() *SolverState {
+	state := &SolverState{
+		Solver:     FastModularNetworkSolver32Name,
+		Signals:    make([]float64, s.totalNeuronCount),
+		Processing: make([]float64, s.totalNeuronCount),
+	}
+	for i := 0; i < s.totalNeuronCount; i++ {
+		state.Signals[i] = float64(s.neuronSignals[i])
+		state.Processing[i] = float64(s.neuronSignalsBeingProcessed[i])
+	}
+	return state
+}
+
+// SetState restores the neuron signals and the values of neurons being processed from provided snapshot.
+func (s *FastModularNetworkSolver32) SetState(state *SolverState) error {
+	if err := state.checkSignals(FastModularNetworkSolver32Name, s.totalNeuronCount, true); err != nil {
+		return err
+	}
+	for i := 0; i < s.totalNeuronCount; i++ {
+		s.neuronSignals[i] = float32(state.Signals[i])
+		s.neuronSignalsBeingProcessed[i] = float32(state.Processing[i])
+	}
+	return nil
+}
+
+func (s *FastModularNetworkSolver32) LoadSensors(inputs []float64) error {
+	if len(inputs) == s.inputNeuronCount {
+		// only inputs should be provided
+		for i := 0; i < s.inputNeuronCount; i++ {
+			s.neuronSignals[s.biasNeuronCount+i] = float32(inputs[i])
+		}
+	} else {
+		return ErrNetUnsupportedSensorsArraySize
+	}
+	return nil
+}
+
+func (s *FastModularNetworkSolver32) ReadOutputs() []float64 {
+	outs := make([]float64, s.outputNeuronCount)
+	for i := range outs {
+		outs[i] = float64(s.neuronSignals[s.sensorNeuronCount+i])
+	}
+	return outs
+}
+
+// Clone returns new solver which shares the immutable network structure with this solver, and has its own
+// activation state.
+func (s *FastModularNetworkSolver32) Clone() Solver {
+	clone := *s
+	clone.fastNetworkState32 = newFastNetworkState32(s.biasNeuronCount, s.totalNeuronCount)
+	return &clone
+}
+
+func (s *FastModularNetworkSolver32) NodeCount() int {
+	return s.totalNeuronCount + len(s.modules)
+}
+
+func (s *FastModularNetworkSolver32) LinkCount() int {
+	// count all connections
+	numLinks := s.connectionCount
+
+	// count all bias links if any
+	if s.biasNeuronCount > 0 {
+		for _, b := range s.biasList {
+			if b != 0 {
+				numLinks++
+			}
+		}
+	}
+
+	// count all modules links
+	for _, module := range s.modules {
+		numLinks += len(module.InputIndexes) + len(module.OutputIndexes)
+	}
+	return numLinks
+}
+
+// Stringer
+func (s *FastModularNetworkSolver32) String() string {
+	str := fmt.Sprintf("FastModularNetwork32, id: %d, name: [%s], neurons: %d,\n\tinputs: %d,\tbias: %d,\toutputs:%d,\t hidden: %d",
+		s.Id, s.Name, s.totalNeuronCount, s.inputNeuronCount, s.biasNeuronCount, s.outputNeuronCount,
+		s.totalNeuronCount-s.sensorNeuronCount-s.outputNeuronCount)
+	return str
+}
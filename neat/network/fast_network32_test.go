@@ -0,0 +1,271 @@
+package network
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	neatmath "github.com/yaricom/goNEAT/v4/neat/math"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// The maximal allowed error of the single precision solver outputs compared to the double precision solver outputs,
+// relative to the magnitude of output
+const fastNetwork32Tolerance = 1e-5
+
+func TestFastModularNetworkSolver32_ForwardSteps(t *testing.T) {
+	testCases := map[string]*Network{
+		"plain":   buildNetwork(),
+		"modular": buildModularNetwork(),
+		"random":  buildRandomFeedForwardNetwork(rand.New(rand.NewSource(42)), 8, 40, 4),
+	}
+	for name, net := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fmm, err := net.FastNetworkSolver()
+			require.NoError(t, err)
+			fmm32, err := net.FastNetworkSolver32()
+			require.NoError(t, err)
+			depth, err := net.MaxActivationDepth()
+			require.NoError(t, err)
+
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 50; i++ {
+				inputs := randomInputs(rnd, len(net.inputs)-1)
+
+				expected := activateFastSolver(t, fmm, inputs, func(s Solver) (bool, error) { return s.ForwardSteps(depth) })
+				actual := activateFastSolver(t, fmm32, inputs, func(s Solver) (bool, error) { return s.ForwardSteps(depth) })
+				assertOutputsWithinTolerance(t, expected, actual)
+			}
+		})
+	}
+}
+
+func TestFastModularNetworkSolver32_RecursiveSteps(t *testing.T) {
+	testCases := map[string]*Network{
+		"plain":  buildNetwork(),
+		"random": buildRandomFeedForwardNetwork(rand.New(rand.NewSource(7)), 5, 30, 3),
+	}
+	for name, net := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fmm, err := net.FastNetworkSolver()
+			require.NoError(t, err)
+			fmm32, err := net.FastNetworkSolver32()
+			require.NoError(t, err)
+
+			rnd := rand.New(rand.NewSource(2))
+			for i := 0; i < 50; i++ {
+				inputs := randomInputs(rnd, len(net.inputs)-1)
+
+				expected := activateFastSolver(t, fmm, inputs, Solver.RecursiveSteps)
+				actual := activateFastSolver(t, fmm32, inputs, Solver.RecursiveSteps)
+				assertOutputsWithinTolerance(t, expected, actual)
+			}
+		})
+	}
+
+	fmm32, err := buildModularNetwork().FastNetworkSolver32()
+	require.NoError(t, err)
+	res, err := fmm32.RecursiveSteps()
+	assert.Error(t, err)
+	assert.False(t, res)
+}
+
+func TestFastModularNetworkSolver32_Relax(t *testing.T) {
+	net := buildRandomFeedForwardNetwork(rand.New(rand.NewSource(3)), 6, 20, 2)
+	fmm, err := net.FastNetworkSolver()
+	require.NoError(t, err)
+	fmm32, err := net.FastNetworkSolver32()
+	require.NoError(t, err)
+	depth, err := net.MaxActivationDepth()
+	require.NoError(t, err)
+
+	inputs := randomInputs(rand.New(rand.NewSource(4)), 6)
+	relax := func(s Solver) (bool, error) { return s.Relax(depth+1, 1e-4) }
+	expected := activateFastSolver(t, fmm, inputs, relax)
+	actual := activateFastSolver(t, fmm32, inputs, relax)
+	assertOutputsWithinTolerance(t, expected, actual)
+}
+
+func TestFastModularNetworkSolver32_Flush(t *testing.T) {
+	net := buildModularNetwork()
+	fmm32, err := net.FastNetworkSolver32()
+	require.NoError(t, err)
+	err = fmm32.LoadSensors([]float64{1.5, 2.0})
+	require.NoError(t, err)
+	_, err = fmm32.ForwardSteps(3)
+	require.NoError(t, err)
+
+	impl := fmm32.(*FastModularNetworkSolver32)
+	assert.NotZero(t, countActiveSignals32(impl), "no active signal found")
+
+	res, err := fmm32.Flush()
+	require.NoError(t, err)
+	require.True(t, res)
+	assert.Zero(t, countActiveSignals32(impl), "after flush the active signal still present")
+	assert.Equal(t, float32(1), impl.neuronSignals[0], "BIAS signal must be preserved")
+}
+
+func TestFastModularNetworkSolver32_LoadSensors(t *testing.T) {
+	fmm32, err := buildNetwork().FastNetworkSolver32()
+	require.NoError(t, err)
+
+	err = fmm32.LoadSensors([]float64{0.5, 1.1})
+	require.NoError(t, err)
+
+	err = fmm32.LoadSensors([]float64{0.5, 1.1, 1.0})
+	assert.EqualError(t, err, ErrNetUnsupportedSensorsArraySize.Error())
+}
+
+func TestFastModularNetworkSolver32_NodeCountLinkCount(t *testing.T) {
+	net := buildModularNetwork()
+	fmm, err := net.FastNetworkSolver()
+	require.NoError(t, err)
+	fmm32, err := net.FastNetworkSolver32()
+	require.NoError(t, err)
+
+	assert.Equal(t, fmm.NodeCount(), fmm32.NodeCount())
+	assert.Equal(t, fmm.LinkCount(), fmm32.LinkCount())
+}
+
+func TestFastModularNetworkSolver32_Clone(t *testing.T) {
+	net := buildRandomFeedForwardNetwork(rand.New(rand.NewSource(5)), 4, 10, 2)
+	fmm32, err := net.FastNetworkSolver32()
+	require.NoError(t, err)
+	depth, err := net.MaxActivationDepth()
+	require.NoError(t, err)
+
+	inputs := []float64{0.1, -0.5, 0.7, 0.2}
+	forward := func(s Solver) (bool, error) { return s.ForwardSteps(depth) }
+	expected := activateFastSolver(t, fmm32, inputs, forward)
+
+	clone := fmm32.Clone()
+	impl := clone.(*FastModularNetworkSolver32)
+	assert.Zero(t, countActiveSignals32(impl), "cloned solver must have flushed state")
+	assert.Equal(t, expected, activateFastSolver(t, clone, inputs, forward))
+}
+
+func TestNewFastModularNetworkSolver32(t *testing.T) {
+	connections := []*FastNetworkLink{
+		{SourceIndex: 1, TargetIndex: 3, Weight: 0.1},
+		{SourceIndex: 2, TargetIndex: 4, Weight: 0.2},
+		{SourceIndex: 0, TargetIndex: 3, Weight: 0.3},
+		{SourceIndex: 4, TargetIndex: 3, Weight: 0.4},
+		{SourceIndex: 3, TargetIndex: 4, Weight: 0.5},
+	}
+	structure := FastModularNetworkStructure{
+		BiasNeuronCount:     1,
+		InputNeuronCount:    2,
+		OutputNeuronCount:   1,
+		TotalNeuronCount:    5,
+		ActivationFunctions: make([]neatmath.NodeActivationType, 5),
+		BiasList:            []float64{0, 0, 0, 0.25, 0},
+		Connections:         connections,
+	}
+	fmm32, err := NewFastModularNetworkSolver32(structure)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int32{0, 0, 0, 0, 3, 5}, fmm32.incomingOffsets)
+	assert.Equal(t, []int32{1, 0, 4, 2, 3}, fmm32.incomingSources)
+	assert.Equal(t, []float32{0.1, 0.3, 0.4, 0.2, 0.5}, fmm32.incomingWeights)
+	assert.Equal(t, []float32{0, 0, 0, 0.25, 0}, fmm32.biasList)
+
+	// test wrong structure
+	structure.BiasList = structure.BiasList[1:]
+	_, err = NewFastModularNetworkSolver32(structure)
+	assert.Error(t, err)
+
+	structure.BiasList = make([]float64, 5)
+	structure.Connections = []*FastNetworkLink{{SourceIndex: 1, TargetIndex: 5}}
+	_, err = NewFastModularNetworkSolver32(structure)
+	assert.Error(t, err)
+}
+
+// activateFastSolver flushes the solver, loads inputs, activates it with provided function and returns outputs
+func activateFastSolver(t *testing.T, solver Solver, inputs []float64, activate func(Solver) (bool, error)) []float64 {
+	_, err := solver.Flush()
+	require.NoError(t, err)
+	err = solver.LoadSensors(inputs)
+	require.NoError(t, err)
+	_, err = activate(solver)
+	require.NoError(t, err)
+	return solver.ReadOutputs()
+}
+
+func assertOutputsWithinTolerance(t *testing.T, expected, actual []float64) {
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		assert.InDelta(t, expected[i], actual[i], fastNetwork32Tolerance*math.Max(1, math.Abs(expected[i])),
+			"wrong output at: %d", i)
+	}
+}
+
+func randomInputs(rnd *rand.Rand, count int) []float64 {
+	inputs := make([]float64, count)
+	for i := range inputs {
+		inputs[i] = rnd.Float64()*2 - 1
+	}
+	return inputs
+}
+
+func countActiveSignals32(impl *FastModularNetworkSolver32) int {
+	active := 0
+	for i := impl.biasNeuronCount; i < impl.totalNeuronCount; i++ {
+		if impl.neuronSignals[i] != 0.0 {
+			active++
+		}
+	}
+	return active
+}
+
+// buildRandomFeedForwardNetwork builds the feed-forward network with provided number of inputs, hidden, and output
+// nodes with random links and weights, and the mix of bounded activation functions
+func buildRandomFeedForwardNetwork(rnd *rand.Rand, inputs, hidden, outputs int) *Network {
+	activations := []neatmath.NodeActivationType{
+		neatmath.SigmoidSteepenedActivation, neatmath.TanhActivation, neatmath.SigmoidBipolarActivation,
+		neatmath.GaussianActivation, neatmath.SigmoidPlainActivation, neatmath.SineActivation,
+	}
+	id := 0
+	newNode := func(neuronType NodeNeuronType) *NNode {
+		id++
+		node := NewNNode(id, neuronType)
+		if neuronType == HiddenNeuron || neuronType == OutputNeuron {
+			node.ActivationType = activations[rnd.Intn(len(activations))]
+		}
+		return node
+	}
+	sensors := make([]*NNode, 0, inputs+1)
+	for i := 0; i < inputs; i++ {
+		sensors = append(sensors, newNode(InputNeuron))
+	}
+	sensors = append(sensors, newNode(BiasNeuron))
+	hiddenNodes := make([]*NNode, hidden)
+	for i := range hiddenNodes {
+		hiddenNodes[i] = newNode(HiddenNeuron)
+	}
+	outputNodes := make([]*NNode, outputs)
+	for i := range outputNodes {
+		outputNodes[i] = newNode(OutputNeuron)
+	}
+
+	// connect each node with random subset of preceding nodes
+	ordered := append(append([]*NNode{}, sensors...), hiddenNodes...)
+	connect := func(node *NNode, sources []*NNode) {
+		for _, source := range sources {
+			if rnd.Float64() < 0.3 {
+				node.ConnectFrom(source, rnd.NormFloat64())
+			}
+		}
+		if len(node.Incoming) == 0 {
+			node.ConnectFrom(sources[rnd.Intn(len(sources))], rnd.NormFloat64())
+		}
+	}
+	for i, node := range hiddenNodes {
+		connect(node, ordered[:len(sensors)+i])
+	}
+	for _, node := range outputNodes {
+		connect(node, ordered)
+	}
+
+	allNodes := append(append(append([]*NNode{}, sensors...), hiddenNodes...), outputNodes...)
+	return NewNetwork(sensors, outputNodes, allNodes, 0)
+}
@@ -0,0 +1,173 @@
+package network
+
+import (
+	neatmath "github.com/yaricom/goNEAT/v4/neat/math"
+	"gonum.org/v1/gonum/mat"
+)
+
+// fastBatchStep is the single step of the feed-forward activation plan. It either activates the neuron with given
+// incoming connections or, if module is not nil, the control node relaying signals between network modules.
+type fastBatchStep struct {
+	// The index of the neuron to activate
+	neuron int
+	// The incoming connections of the neuron in order of their definition in the network
+	incoming []*FastNetworkLink
+	// The control node to activate
+	module *FastControlNode
+}
+
+// ActivateBatch activates the feed-forward network with each row of the inputs matrix as the sensors values and
+// returns the matrix with the corresponding values of the output neurons in each row. The inputs matrix must have
+// the number of columns equal to the number of input neurons (BIAS excluded).
+//
+// The results are guaranteed to be the same as produced for each sample by the per-sample path: Flush, LoadSensors,
+// ForwardSteps with the number of steps not less than the depth of the network, and ReadOutputs. The current
+// activation state of the solver is not affected by this method. If network has recurrent connections the
+// ErrNetNotFeedForward returned.
+func (s *FastModularNetworkSolver) ActivateBatch(inputs *mat.Dense) (*mat.Dense, error) {
+	if inputs == nil || inputs.IsEmpty() {
+		return nil, ErrNetUnsupportedSensorsArraySize
+	}
+	rows, cols := inputs.Dims()
+	if cols != s.inputNeuronCount {
+		return nil, ErrNetUnsupportedSensorsArraySize
+	}
+	if s.batchPlanErr != nil {
+		return nil, s.batchPlanErr
+	}
+
+	// the signals of each neuron are stored as a column of values per each sample
+	signals := make([]float64, s.totalNeuronCount*rows)
+	column := func(neuron int) []float64 {
+		return signals[neuron*rows : (neuron+1)*rows]
+	}
+	for i := 0; i < s.biasNeuronCount; i++ {
+		col := column(i)
+		for r := range col {
+			col[r] = 1.0 // BIAS neuron signal
+		}
+	}
+	for i := 0; i < s.inputNeuronCount; i++ {
+		mat.Col(column(s.biasNeuronCount+i), i, inputs)
+	}
+
+	var err error
+	for _, step := range s.batchPlan {
+		if step.module != nil {
+			moduleInputs := make([]float64, len(step.module.InputIndexes))
+			for r := 0; r < rows; r++ {
+				for i, inIndex := range step.module.InputIndexes {
+					moduleInputs[i] = signals[inIndex*rows+r]
+				}
+				outputs, err := neatmath.NodeActivators.ActivateModuleByType(moduleInputs, nil, step.module.ActivationType)
+				if err != nil {
+					return nil, err
+				}
+				for i, outIndex := range step.module.OutputIndexes {
+					signals[outIndex*rows+r] = outputs[i]
+				}
+			}
+			continue
+		}
+
+		col := column(step.neuron)
+		if aggregation := s.aggregation(step.neuron); aggregation == neatmath.SumAggregation {
+			for _, conn := range step.incoming {
+				src := column(conn.SourceIndex)
+				for r := range col {
+					col[r] += src[r] * conn.Weight
+				}
+			}
+		} else {
+			aggregationInputs := make([]float64, len(step.incoming))
+			for r := range col {
+				for i, conn := range step.incoming {
+					aggregationInputs[i] = signals[conn.SourceIndex*rows+r] * conn.Weight
+				}
+				if col[r], err = neatmath.Aggregate(aggregation, aggregationInputs); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for r := range col {
+			signal := col[r]
+			if s.biasNeuronCount > 0 {
+				// append BIAS value to the signal if appropriate
+				signal += s.biasList[step.neuron]
+			}
+			if col[r], err = neatmath.NodeActivators.ActivateByType(
+				signal, s.auxParams(step.neuron), s.activationFunctions[step.neuron]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	outputs := mat.NewDense(rows, s.outputNeuronCount, nil)
+	for i := 0; i < s.outputNeuronCount; i++ {
+		outputs.SetCol(i, column(s.sensorNeuronCount+i))
+	}
+	return outputs, nil
+}
+
+// buildBatchPlan is to build the order of neurons and control nodes activation in which every neuron is activated
+// after all its sources. Returns ErrNetNotFeedForward if network has recurrent connections.
+func (s *FastModularNetworkSolver) buildBatchPlan() ([]fastBatchStep, error) {
+	// the graph vertices are neurons followed by control nodes
+	verticesCount := s.totalNeuronCount + len(s.modules)
+	incoming := make([][]*FastNetworkLink, s.totalNeuronCount)
+	outgoing := make([][]int, verticesCount)
+	inDegree := make([]int, verticesCount)
+	// the signals of module outputs are set by control nodes only
+	moduleOutputs := make(map[int]bool)
+	for _, conn := range s.connections {
+		if conn.TargetIndex < s.sensorNeuronCount {
+			// the sensors values are never changed by activation
+			continue
+		}
+		incoming[conn.TargetIndex] = append(incoming[conn.TargetIndex], conn)
+		outgoing[conn.SourceIndex] = append(outgoing[conn.SourceIndex], conn.TargetIndex)
+		inDegree[conn.TargetIndex]++
+	}
+	for i, module := range s.modules {
+		moduleVertex := s.totalNeuronCount + i
+		for _, inIndex := range module.InputIndexes {
+			outgoing[inIndex] = append(outgoing[inIndex], moduleVertex)
+			inDegree[moduleVertex]++
+		}
+		for _, outIndex := range module.OutputIndexes {
+			outgoing[moduleVertex] = append(outgoing[moduleVertex], outIndex)
+			inDegree[outIndex]++
+			moduleOutputs[outIndex] = true
+		}
+	}
+
+	// Kahn's topological sorting
+	queue := make([]int, 0, verticesCount)
+	for v := 0; v < verticesCount; v++ {
+		if inDegree[v] == 0 {
+			queue = append(queue, v)
+		}
+	}
+	plan := make([]fastBatchStep, 0, verticesCount-s.sensorNeuronCount)
+	visited := 0
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		visited++
+		if v >= s.totalNeuronCount {
+			plan = append(plan, fastBatchStep{module: s.modules[v-s.totalNeuronCount]})
+		} else if v >= s.sensorNeuronCount && !moduleOutputs[v] {
+			plan = append(plan, fastBatchStep{neuron: v, incoming: incoming[v]})
+		}
+		for _, next := range outgoing[v] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	if visited != verticesCount {
+		return nil, ErrNetNotFeedForward
+	}
+	return plan, nil
+}
@@ -0,0 +1,111 @@
+package network
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gonum.org/v1/gonum/mat"
+	"sync"
+	"testing"
+)
+
+func TestFastModularNetworkSolver_ActivateBatch(t *testing.T) {
+	inputs := mat.NewDense(4, 2, []float64{
+		0.5, 1.1,
+		0.0, 0.0,
+		-1.0, 2.0,
+		1.0, 2.0,
+	})
+	testCases := map[string]*Network{
+		"plain":   buildPlainNetwork(),
+		"hidden":  buildNetwork(),
+		"modular": buildModularNetwork(),
+	}
+	for name, net := range testCases {
+		t.Run(name, func(t *testing.T) {
+			solver, err := net.FastNetworkSolver()
+			require.NoError(t, err, "failed to create fast network solver")
+			fmm := solver.(*FastModularNetworkSolver)
+
+			outputs, err := fmm.ActivateBatch(inputs)
+			require.NoError(t, err, "failed to activate batch")
+			rows, cols := outputs.Dims()
+			require.Equal(t, 4, rows)
+			require.Equal(t, len(net.Outputs), cols)
+
+			// check that results are the same as produced by per-sample path
+			for r := 0; r < rows; r++ {
+				_, err = fmm.Flush()
+				require.NoError(t, err)
+				err = fmm.LoadSensors(mat.Row(nil, r, inputs))
+				require.NoError(t, err)
+				_, err = fmm.ForwardSteps(5)
+				require.NoError(t, err)
+				assert.Equal(t, fmm.ReadOutputs(), mat.Row(nil, r, outputs), "wrong outputs at row: %d", r)
+			}
+		})
+	}
+}
+
+func TestFastModularNetworkSolver_ActivateBatch_state(t *testing.T) {
+	solver, err := buildNetwork().FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	fmm := solver.(*FastModularNetworkSolver)
+
+	err = fmm.LoadSensors([]float64{0.5, 1.1})
+	require.NoError(t, err)
+	_, err = fmm.ForwardSteps(5)
+	require.NoError(t, err)
+	expected := fmm.ReadOutputs()
+
+	_, err = fmm.ActivateBatch(mat.NewDense(1, 2, []float64{-1.0, 2.0}))
+	require.NoError(t, err)
+	assert.Equal(t, expected, fmm.ReadOutputs(), "the solver state must not be affected")
+}
+
+func TestFastModularNetworkSolver_ActivateBatch_concurrent(t *testing.T) {
+	solver, err := buildModularNetwork().FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	inputs := mat.NewDense(2, 2, []float64{0.5, 1.1, -1.0, 2.0})
+
+	// the solver and its clones share the activation plan and can be used concurrently
+	results := make([]*mat.Dense, 8)
+	errs := make([]error, len(results))
+	var wg sync.WaitGroup
+	for i := range results {
+		fmm := solver.(*FastModularNetworkSolver)
+		if i%2 == 1 {
+			fmm = solver.Clone().(*FastModularNetworkSolver)
+		}
+		wg.Add(1)
+		go func(i int, fmm *FastModularNetworkSolver) {
+			defer wg.Done()
+			results[i], errs[i] = fmm.ActivateBatch(inputs)
+		}(i, fmm)
+	}
+	wg.Wait()
+	expected, err := solver.(*FastModularNetworkSolver).ActivateBatch(inputs)
+	require.NoError(t, err)
+	for i, res := range results {
+		require.NoError(t, errs[i])
+		assert.Equal(t, expected.RawMatrix().Data, res.RawMatrix().Data, "wrong outputs of: %d", i)
+	}
+}
+
+func TestFastModularNetworkSolver_ActivateBatch_errors(t *testing.T) {
+	solver, err := buildNetwork().FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	fmm := solver.(*FastModularNetworkSolver)
+
+	_, err = fmm.ActivateBatch(nil)
+	assert.ErrorIs(t, err, ErrNetUnsupportedSensorsArraySize)
+	_, err = fmm.ActivateBatch(mat.NewDense(2, 3, nil))
+	assert.ErrorIs(t, err, ErrNetUnsupportedSensorsArraySize)
+
+	// recurrent network
+	net := buildNetwork()
+	net.allNodes[5].ConnectFrom(net.allNodes[6], 0.5) // HIDDEN 6 <- OUTPUT 7
+	solver, err = net.FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	_, err = solver.(*FastModularNetworkSolver).ActivateBatch(mat.NewDense(1, 2, nil))
+	assert.ErrorIs(t, err, ErrNetNotFeedForward)
+}
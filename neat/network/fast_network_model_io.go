@@ -2,6 +2,7 @@ package network
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/yaricom/goNEAT/v4/neat/math"
 	"io"
 )
@@ -40,6 +41,23 @@ func ReadFMNSModel(reader io.Reader) (*FastModularNetworkSolver, error) {
 		data.TotalNeuronCount, activationFunctions,
 		data.Connections, data.BiasList, modules,
 	)
+	if data.NeuronParams != nil {
+		if len(data.NeuronParams) != data.TotalNeuronCount {
+			return nil, fmt.Errorf("the number of neuron parameters: %d doesn't match the total number of neurons: %d",
+				len(data.NeuronParams), data.TotalNeuronCount)
+		}
+		fmns.neuronParams = data.NeuronParams
+	}
+	if data.AggregationFunctions != nil {
+		if len(data.AggregationFunctions) != data.TotalNeuronCount {
+			return nil, fmt.Errorf("the number of aggregation functions: %d doesn't match the total number of neurons: %d",
+				len(data.AggregationFunctions), data.TotalNeuronCount)
+		}
+		fmns.aggregationFunctions = make([]math.NodeAggregationType, len(data.AggregationFunctions))
+		for i, a := range data.AggregationFunctions {
+			fmns.aggregationFunctions[i] = a.NodeAggregation
+		}
+	}
 	fmns.Name = data.Name
 	fmns.Id = data.Id
 	return fmns, nil
@@ -49,6 +67,11 @@ type NodeActivator struct {
 	NodeActivation math.NodeActivationType
 }
 
+// NodeAggregator is to encode the aggregation function of the neuron by name
+type NodeAggregator struct {
+	NodeAggregation math.NodeAggregationType
+}
+
 type fastControlNodeData struct {
 	ActivationType NodeActivator `json:"activation_type"`
 	InputIndexes   []int         `json:"input_indexes"`
@@ -56,17 +79,19 @@ type fastControlNodeData struct {
 }
 
 type fastModularNetworkSolverData struct {
-	Id                  int                   `json:"id"`
-	Name                string                `json:"name"`
-	InputNeuronCount    int                   `json:"input_neuron_count"`
-	SensorNeuronCount   int                   `json:"sensor_neuron_count"`
-	OutputNeuronCount   int                   `json:"output_neuron_count"`
-	BiasNeuronCount     int                   `json:"bias_neuron_count"`
-	TotalNeuronCount    int                   `json:"total_neuron_count"`
-	ActivationFunctions []NodeActivator       `json:"activation_functions"`
-	BiasList            []float64             `json:"bias_list"`
-	Connections         []*FastNetworkLink    `json:"connections"`
-	Modules             []fastControlNodeData `json:"modules,omitempty"`
+	Id                   int                   `json:"id"`
+	Name                 string                `json:"name"`
+	InputNeuronCount     int                   `json:"input_neuron_count"`
+	SensorNeuronCount    int                   `json:"sensor_neuron_count"`
+	OutputNeuronCount    int                   `json:"output_neuron_count"`
+	BiasNeuronCount      int                   `json:"bias_neuron_count"`
+	TotalNeuronCount     int                   `json:"total_neuron_count"`
+	ActivationFunctions  []NodeActivator       `json:"activation_functions"`
+	BiasList             []float64             `json:"bias_list"`
+	Connections          []*FastNetworkLink    `json:"connections"`
+	Modules              []fastControlNodeData `json:"modules,omitempty"`
+	NeuronParams         [][]float64           `json:"neuron_params,omitempty"`
+	AggregationFunctions []NodeAggregator      `json:"aggregation_functions,omitempty"`
 }
 
 func newFastModularNetworkSolverData(n *FastModularNetworkSolver) *fastModularNetworkSolverData {
@@ -82,12 +107,19 @@ func newFastModularNetworkSolverData(n *FastModularNetworkSolver) *fastModularNe
 		BiasList:            n.biasList,
 		Connections:         n.connections,
 		Modules:             make([]fastControlNodeData, 0),
+		NeuronParams:        n.neuronParams,
 	}
 	for i, v := range n.activationFunctions {
 		data.ActivationFunctions[i] = NodeActivator{
 			NodeActivation: v,
 		}
 	}
+	if n.aggregationFunctions != nil {
+		data.AggregationFunctions = make([]NodeAggregator, len(n.aggregationFunctions))
+		for i, v := range n.aggregationFunctions {
+			data.AggregationFunctions[i] = NodeAggregator{NodeAggregation: v}
+		}
+	}
 	if n.modules != nil {
 		for _, v := range n.modules {
 			data.Modules = append(data.Modules, fastControlNodeData{
@@ -112,3 +144,16 @@ func (n *NodeActivator) UnmarshalText(text []byte) (err error) {
 	n.NodeActivation, err = math.NodeActivators.ActivationTypeFromName(string(text))
 	return err
 }
+
+func (n *NodeAggregator) MarshalText() ([]byte, error) {
+	if aggregationName, err := math.AggregationNameFromType(n.NodeAggregation); err != nil {
+		return nil, err
+	} else {
+		return []byte(aggregationName), nil
+	}
+}
+
+func (n *NodeAggregator) UnmarshalText(text []byte) (err error) {
+	n.NodeAggregation, err = math.AggregationTypeFromName(string(text))
+	return err
+}
@@ -3,6 +3,7 @@ package network
 import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/math"
 	"testing"
 )
 
@@ -192,3 +193,19 @@ func countActiveSignals(impl *FastModularNetworkSolver) int {
 	}
 	return active
 }
+
+func TestNewFastModularNetworkSolver_incomingConnections(t *testing.T) {
+	connections := []*FastNetworkLink{
+		{SourceIndex: 1, TargetIndex: 3, Weight: 0.1},
+		{SourceIndex: 2, TargetIndex: 4, Weight: 0.2},
+		{SourceIndex: 0, TargetIndex: 3, Weight: 0.3},
+		{SourceIndex: 4, TargetIndex: 3, Weight: 0.4},
+		{SourceIndex: 3, TargetIndex: 4, Weight: 0.5},
+	}
+	activations := make([]math.NodeActivationType, 5)
+	fmm := NewFastModularNetworkSolver(1, 2, 1, 5, activations, connections, make([]float64, 5), nil)
+
+	assert.Equal(t, []int{0, 0, 0, 0, 3, 5}, fmm.incomingOffsets)
+	assert.Equal(t, []int{1, 0, 4, 2, 3}, fmm.incomingSources)
+	assert.Equal(t, []float64{0.1, 0.3, 0.4, 0.2, 0.5}, fmm.incomingWeights)
+}
@@ -1,2 +1,47 @@
 // Package formats defines the serialization formats which can be used for network graph persistence
 package formats
+
+import (
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"math"
+)
+
+const (
+	minEdgeWidth = 1.0
+	maxEdgeWidth = 10.0
+)
+
+// maxScores returns the maximal absolute scores of the nodes and links of the network
+func maxScores(n *network.Network, scores ElementScores) (maxNodeScore, maxLinkScore float64) {
+	for _, node := range n.AllNodes() {
+		if score, ok := scores.NodeScore(node.Id); ok {
+			maxNodeScore = math.Max(maxNodeScore, math.Abs(score))
+		}
+		for _, l := range node.Incoming {
+			if score, ok := scores.LinkScore(l.InNode.Id, l.OutNode.Id); ok {
+				maxLinkScore = math.Max(maxLinkScore, math.Abs(score))
+			}
+		}
+	}
+	return maxNodeScore, maxLinkScore
+}
+
+// scoredEdgeWidth returns the width of the edge proportional to the absolute value of its score relative to the
+// maximal score
+func scoredEdgeWidth(score, maxScore float64) float64 {
+	if maxScore <= 0 {
+		return minEdgeWidth
+	}
+	return minEdgeWidth + (maxEdgeWidth-minEdgeWidth)*math.Min(math.Abs(score)/maxScore, 1)
+}
+
+// ElementScores provides the numerical scores of the network nodes and links to be mapped onto the elements of the
+// network graph, e.g., the results of the network analysis.
+type ElementScores interface {
+	// Name returns the name of the scores to be used as attribute name
+	Name() string
+	// NodeScore returns the score of the node with given ID and true if node was scored
+	NodeScore(id int) (float64, bool)
+	// LinkScore returns the score of the link between nodes with given IDs and true if link was scored
+	LinkScore(inId, outId int) (float64, bool)
+}
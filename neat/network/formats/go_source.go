@@ -0,0 +1,424 @@
+package formats
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	neatmath "github.com/yaricom/goNEAT/v4/neat/math"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"go/format"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoSourceOptions is to hold options of the Go source code generation for evolved networks
+type GoSourceOptions struct {
+	// PackageName the name of the package of generated source file
+	PackageName string
+	// ActivationSteps the number of forward activation steps performed by each call to the generated Activate.
+	// If zero, the maximal activation depth of the network is used when generating from network.Network.
+	ActivationSteps int
+}
+
+// goActivation is the source code of the activation function to be inlined into generated code
+type goActivation struct {
+	// The body of the function with input value x and output value returned
+	body string
+	// Whether the body uses the math package
+	usesMath bool
+}
+
+// goActivations the Go source code of supported neuron activation functions from the math package
+var goActivations = map[neatmath.NodeActivationType]goActivation{
+	neatmath.SigmoidPlainActivation:     {body: "return 1 / (1 + math.Exp(-x))", usesMath: true},
+	neatmath.SigmoidReducedActivation:   {body: "return 1 / (1 + math.Exp(-0.5*x))", usesMath: true},
+	neatmath.SigmoidSteepenedActivation: {body: "return 1.0 / (1.0 + math.Exp(-4.924273*x))", usesMath: true},
+	neatmath.SigmoidBipolarActivation:   {body: "return (2.0 / (1.0 + math.Exp(-4.924273*x))) - 1.0", usesMath: true},
+	neatmath.SigmoidApproximationActivation: {body: `four, one32nd := 4.0, 0.03125
+		if x < -4.0 {
+			return 0.0
+		} else if x < 0.0 {
+			return (x + four) * (x + four) * one32nd
+		} else if x < 4.0 {
+			return 1.0 - (x-four)*(x-four)*one32nd
+		} else {
+			return 1.0
+		}`},
+	neatmath.SigmoidSteepenedApproximationActivation: {body: `one, oneHalf := 1.0, 0.5
+		if x < -1.0 {
+			return 0.0
+		} else if x < 0.0 {
+			return (x + one) * (x + one) * oneHalf
+		} else if x < 1.0 {
+			return 1.0 - (x-one)*(x-one)*oneHalf
+		} else {
+			return 1.0
+		}`},
+	neatmath.SigmoidInverseAbsoluteActivation:       {body: "return 0.5 + (x/(1.0+math.Abs(x)))*0.5", usesMath: true},
+	neatmath.SigmoidLeftShiftedActivation:           {body: "return 1.0 / (1.0 + math.Exp(-x-2.4621365))", usesMath: true},
+	neatmath.SigmoidLeftShiftedSteepenedActivation:  {body: "return 1.0 / (1.0 + math.Exp(-(4.924273*x + 2.4621365)))", usesMath: true},
+	neatmath.SigmoidRightShiftedSteepenedActivation: {body: "return 1.0 / (1.0 + math.Exp(-(4.924273*x - 2.4621365)))", usesMath: true},
+	neatmath.TanhActivation:                         {body: "return math.Tanh(0.9 * x)", usesMath: true},
+	neatmath.GaussianBipolarActivation:              {body: "return 2.0*math.Exp(-math.Pow(x*2.5, 2.0)) - 1.0", usesMath: true},
+	neatmath.GaussianActivation:                     {body: "return math.Exp(-math.Pow(x, 2.0))", usesMath: true},
+	neatmath.LinearActivation:                       {body: "return x"},
+	neatmath.LinearAbsActivation:                    {body: "return math.Abs(x)", usesMath: true},
+	neatmath.LinearClippedActivation: {body: `if x < -1.0 {
+			return -1.0
+		}
+		if x > 1.0 {
+			return 1.0
+		}
+		return x`},
+	neatmath.NullActivation: {body: "return 0.0"},
+	neatmath.SignActivation: {body: `if math.IsNaN(x) || x == 0.0 {
+			return 0.0
+		} else if math.Signbit(x) {
+			return -1.0
+		} else {
+			return 1.0
+		}`, usesMath: true},
+	neatmath.SineActivation: {body: "return math.Sin(2.0 * x)", usesMath: true},
+	neatmath.StepActivation: {body: `if math.Signbit(x) {
+			return 0.0
+		} else {
+			return 1.0
+		}`, usesMath: true},
+}
+
+// goModuleActivations the Go source code of supported module activation functions from the math package
+var goModuleActivations = map[neatmath.NodeActivationType]goActivation{
+	neatmath.MultiplyModuleActivation: {body: `ret := 1.0
+		for _, v := range inputs {
+			ret *= v
+		}
+		return ret`},
+	neatmath.MaxModuleActivation: {body: `maxVal := float64(math.MinInt64)
+		for _, v := range inputs {
+			maxVal = math.Max(maxVal, v)
+		}
+		return maxVal`, usesMath: true},
+	neatmath.MinModuleActivation: {body: `minVal := math.MaxFloat64
+		for _, v := range inputs {
+			minVal = math.Min(minVal, v)
+		}
+		return minVal`, usesMath: true},
+}
+
+// WriteGoSource is to write provided network as self-contained Go source file without any dependencies. The generated
+// code has the same activation semantics as the FastModularNetworkSolver created from the network: each call to the
+// generated Activate function loads inputs into the network and performs options.ActivationSteps forward steps with
+// unrolled weighted sums and inlined activation functions. The recurrent state of the network is held in the generated
+// Network struct between calls. If options.ActivationSteps is zero, the maximal activation depth of the network is used.
+func WriteGoSource(w io.Writer, n *network.Network, options GoSourceOptions) error {
+	if options.ActivationSteps == 0 {
+		depth, err := n.MaxActivationDepth()
+		if err != nil {
+			return fmt.Errorf("failed to calculate activation depth of the network: %w", err)
+		}
+		options.ActivationSteps = depth
+	}
+	solver, err := n.FastNetworkSolver()
+	if err != nil {
+		return err
+	}
+	return WriteFMNSGoSource(w, solver.(*network.FastModularNetworkSolver), options)
+}
+
+// WriteFMNSGoSource is to write provided FastModularNetworkSolver as self-contained Go source file without any
+// dependencies. See WriteGoSource for details. The options.ActivationSteps must be positive.
+func WriteFMNSGoSource(w io.Writer, s *network.FastModularNetworkSolver, options GoSourceOptions) error {
+	if options.PackageName == "" {
+		return errors.New("the package name must be provided")
+	}
+	if options.ActivationSteps <= 0 {
+		return fmt.Errorf("the number of activation steps must be positive: %d", options.ActivationSteps)
+	}
+	st := s.Structure()
+	sensorCount := st.BiasNeuronCount + st.InputNeuronCount
+
+	// the signals of module outputs are set by control nodes only
+	moduleOutputs := make(map[int]bool)
+	for _, module := range st.Modules {
+		for _, outIndex := range module.OutputIndexes {
+			moduleOutputs[outIndex] = true
+		}
+	}
+
+	// collect activation functions to be inlined
+	activations := make(map[neatmath.NodeActivationType]bool)
+	modules := make(map[neatmath.NodeActivationType]bool)
+	usesMath := false
+	for i := sensorCount; i < st.TotalNeuronCount; i++ {
+		if moduleOutputs[i] {
+			continue
+		}
+		activation, ok := goActivations[st.ActivationFunctions[i]]
+		if !ok {
+			return fmt.Errorf("unsupported activation type: %d of neuron at: %d", st.ActivationFunctions[i], i)
+		}
+		if st.AggregationFunctions != nil && st.AggregationFunctions[i] != neatmath.SumAggregation {
+			return fmt.Errorf("unsupported aggregation type: %d of neuron at: %d", st.AggregationFunctions[i], i)
+		}
+		activations[st.ActivationFunctions[i]] = true
+		usesMath = usesMath || activation.usesMath
+	}
+	for i, module := range st.Modules {
+		activation, ok := goModuleActivations[module.ActivationType]
+		if !ok {
+			return fmt.Errorf("unsupported module activation type: %d of module at: %d", module.ActivationType, i)
+		}
+		if len(module.OutputIndexes) > 1 {
+			return fmt.Errorf("unsupported number of outputs: %d of module at: %d", len(module.OutputIndexes), i)
+		}
+		modules[module.ActivationType] = true
+		usesMath = usesMath || activation.usesMath
+	}
+
+	// collect incoming connections in order of their definition
+	incoming := make([][]*network.FastNetworkLink, st.TotalNeuronCount)
+	for _, conn := range st.Connections {
+		if conn.TargetIndex >= sensorCount {
+			incoming[conn.TargetIndex] = append(incoming[conn.TargetIndex], conn)
+			usesMath = usesMath || !isFinite(conn.Weight)
+		}
+	}
+	for i := sensorCount; i < st.TotalNeuronCount && st.BiasNeuronCount > 0; i++ {
+		usesMath = usesMath || !isFinite(st.BiasList[i])
+	}
+
+	b := bytes.NewBufferString("")
+	_, _ = fmt.Fprintf(b, "// Code generated by goNEAT from network %q (id: %d). DO NOT EDIT.\n\n", s.Name, s.Id)
+	_, _ = fmt.Fprintf(b, "// Package %s provides the activation of the evolved neural network.\n", options.PackageName)
+	_, _ = fmt.Fprintf(b, "package %s\n\n", options.PackageName)
+	if usesMath {
+		b.WriteString("import \"math\"\n\n")
+	}
+	b.WriteString("const (\n")
+	_, _ = fmt.Fprintf(b, "// InputCount is the number of the network inputs\nInputCount = %d\n", st.InputNeuronCount)
+	_, _ = fmt.Fprintf(b, "// OutputCount is the number of the network outputs\nOutputCount = %d\n", st.OutputNeuronCount)
+	_, _ = fmt.Fprintf(b, "// ActivationSteps is the number of forward activation steps per each activation\nActivationSteps = %d\n", options.ActivationSteps)
+	b.WriteString(")\n\n")
+
+	b.WriteString("// Network holds the activation state of the network between activations.\n")
+	_, _ = fmt.Fprintf(b, "type Network struct {\nsignals [%d]float64\n}\n\n", st.TotalNeuronCount)
+
+	b.WriteString("// NewNetwork creates new network with initial activation state.\n")
+	b.WriteString("func NewNetwork() *Network {\nn := &Network{}\nn.Reset()\nreturn n\n}\n\n")
+
+	b.WriteString("// Reset resets the activation state of the network to the initial one.\n")
+	_, _ = fmt.Fprintf(b, "func (n *Network) Reset() {\nn.signals = [%d]float64{}\n", st.TotalNeuronCount)
+	for i := 0; i < st.BiasNeuronCount; i++ {
+		_, _ = fmt.Fprintf(b, "n.signals[%d] = 1.0 // BIAS\n", i)
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// Activate loads the inputs into the network, propagates activation ActivationSteps times, and returns\n")
+	b.WriteString("// the outputs. It panics if the number of inputs is not equal to InputCount.\n")
+	b.WriteString("func (n *Network) Activate(in []float64) []float64 {\n")
+	b.WriteString("if len(in) != InputCount {\npanic(\"unsupported number of inputs\")\n}\n")
+	_, _ = fmt.Fprintf(b, "copy(n.signals[%d:%d], in)\n", st.BiasNeuronCount, sensorCount)
+	b.WriteString("for i := 0; i < ActivationSteps; i++ {\nn.step()\n}\n")
+	b.WriteString("out := make([]float64, OutputCount)\n")
+	_, _ = fmt.Fprintf(b, "copy(out, n.signals[%d:%d])\n", sensorCount, sensorCount+st.OutputNeuronCount)
+	b.WriteString("return out\n}\n\n")
+
+	b.WriteString("// step performs single forward activation step through all network neurons.\n")
+	b.WriteString("func (n *Network) step() {\n")
+	b.WriteString("s := &n.signals\n")
+	_, _ = fmt.Fprintf(b, "var p [%d]float64\n", st.TotalNeuronCount)
+	for i := sensorCount; i < st.TotalNeuronCount; i++ {
+		if moduleOutputs[i] {
+			continue
+		}
+		terms := make([]string, len(incoming[i]))
+		for j, conn := range incoming[i] {
+			terms[j] = fmt.Sprintf("s[%d]*%s", conn.SourceIndex, goFloat(conn.Weight))
+		}
+		signal := "0.0"
+		if len(terms) > 0 {
+			signal = strings.Join(terms, " + ")
+		}
+		if st.BiasNeuronCount > 0 {
+			signal = fmt.Sprintf("%s + %s", signal, goFloat(st.BiasList[i]))
+		}
+		_, _ = fmt.Fprintf(b, "p[%d] = %s(%s)\n", i, goFunctionName(st.ActivationFunctions[i]), signal)
+	}
+	for _, module := range st.Modules {
+		if len(module.OutputIndexes) == 0 {
+			continue
+		}
+		inputs := make([]string, len(module.InputIndexes))
+		for j, inIndex := range module.InputIndexes {
+			inputs[j] = fmt.Sprintf("p[%d]", inIndex)
+		}
+		_, _ = fmt.Fprintf(b, "p[%d] = %s(%s)\n", module.OutputIndexes[0],
+			goFunctionName(module.ActivationType), strings.Join(inputs, ", "))
+	}
+	_, _ = fmt.Fprintf(b, "copy(s[%d:], p[%d:])\n}\n\n", sensorCount, sensorCount)
+
+	b.WriteString("var defaultNetwork = NewNetwork()\n\n")
+	b.WriteString("// Activate activates the default instance of the network with provided inputs and returns the outputs.\n")
+	b.WriteString("// The default instance keeps its activation state between calls and is not safe for concurrent use.\n")
+	b.WriteString("func Activate(in []float64) []float64 {\nreturn defaultNetwork.Activate(in)\n}\n")
+
+	for _, aType := range sortedActivationTypes(activations) {
+		_, _ = fmt.Fprintf(b, "\nfunc %s(x float64) float64 {\n%s\n}\n", goFunctionName(aType), goActivations[aType].body)
+	}
+	for _, aType := range sortedActivationTypes(modules) {
+		_, _ = fmt.Fprintf(b, "\nfunc %s(inputs ...float64) float64 {\n%s\n}\n", goFunctionName(aType), goModuleActivations[aType].body)
+	}
+
+	return writeGoSource(w, b.Bytes())
+}
+
+// WriteGoSourceTest is to write the test harness for the Go source file generated by WriteFMNSGoSource with the same
+// options. The harness checks that the generated code produces the same outputs as provided solver for the sequence
+// of the given number of random inputs generated with provided seed. The expected outputs are collected by activating
+// the solver which is flushed before and after.
+func WriteGoSourceTest(w io.Writer, s *network.FastModularNetworkSolver, options GoSourceOptions, samples int, seed int64) error {
+	if options.PackageName == "" {
+		return errors.New("the package name must be provided")
+	}
+	if options.ActivationSteps <= 0 {
+		return fmt.Errorf("the number of activation steps must be positive: %d", options.ActivationSteps)
+	}
+	if samples <= 0 {
+		return fmt.Errorf("the number of samples must be positive: %d", samples)
+	}
+
+	// collect expected outputs
+	inputCount := s.Structure().InputNeuronCount
+	rng := rand.New(rand.NewSource(seed))
+	inputs, outputs := make([][]float64, samples), make([][]float64, samples)
+	if _, err := s.Flush(); err != nil {
+		return err
+	}
+	for i := range inputs {
+		inputs[i] = make([]float64, inputCount)
+		for j := range inputs[i] {
+			inputs[i][j] = rng.Float64()*2.0 - 1.0
+		}
+		if err := s.LoadSensors(inputs[i]); err != nil {
+			return err
+		}
+		if _, err := s.ForwardSteps(options.ActivationSteps); err != nil {
+			return err
+		}
+		outputs[i] = s.ReadOutputs()
+	}
+	if _, err := s.Flush(); err != nil {
+		return err
+	}
+
+	b := bytes.NewBufferString("")
+	_, _ = fmt.Fprintf(b, "// Code generated by goNEAT from network %q (id: %d). DO NOT EDIT.\n\n", s.Name, s.Id)
+	_, _ = fmt.Fprintf(b, "package %s\n\n", options.PackageName)
+	b.WriteString("import (\n\"math\"\n\"testing\"\n)\n\n")
+	b.WriteString("func TestActivate(t *testing.T) {\n")
+	_, _ = fmt.Fprintf(b, "inputs := %s\n", goFloatsMatrix(inputs))
+	_, _ = fmt.Fprintf(b, "expected := %s\n", goFloatsMatrix(outputs))
+	b.WriteString(`net := NewNetwork()
+	for i, in := range inputs {
+		out := net.Activate(in)
+		if len(out) != len(expected[i]) {
+			t.Fatalf("wrong number of outputs at sample %d: %d", i, len(out))
+		}
+		for j, v := range out {
+			if !equalOutputs(v, expected[i][j]) {
+				t.Errorf("wrong output %d at sample %d, expected: %v, found: %v", j, i, expected[i][j], v)
+			}
+		}
+	}
+}
+
+func equalOutputs(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	if a == b {
+		return true
+	}
+	return math.Abs(a-b) <= 1e-9*math.Max(1.0, math.Max(math.Abs(a), math.Abs(b)))
+}
+`)
+	return writeGoSource(w, b.Bytes())
+}
+
+// writeGoSource is to format and write the generated Go source
+func writeGoSource(w io.Writer, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("failed to format generated source: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
+
+// goFunctionName returns the name of the generated function for given activation type
+func goFunctionName(aType neatmath.NodeActivationType) string {
+	name, err := neatmath.NodeActivators.ActivationNameFromType(aType)
+	if err != nil || name == "" {
+		return fmt.Sprintf("activation%d", aType)
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// goFloat returns the Go literal for given float value which preserves its precision
+func goFloat(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "math.NaN()"
+	case math.IsInf(v, 1):
+		return "math.Inf(1)"
+	case math.IsInf(v, -1):
+		return "math.Inf(-1)"
+	}
+	str := strconv.FormatFloat(v, 'g', -1, 64)
+	if !strings.ContainsAny(str, ".eE") {
+		str += ".0"
+	}
+	if v < 0 {
+		str = "(" + str + ")"
+	}
+	return str
+}
+
+// isFinite checks whether given float value is neither NaN nor infinity
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// goFloatsMatrix returns the Go literal for given matrix of float values
+func goFloatsMatrix(values [][]float64) string {
+	b := strings.Builder{}
+	b.WriteString("[][]float64{\n")
+	for _, row := range values {
+		b.WriteString("{")
+		for i, v := range row {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(goFloat(v))
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// sortedActivationTypes returns activation types from the given set in ascending order
+func sortedActivationTypes(types map[neatmath.NodeActivationType]bool) []neatmath.NodeActivationType {
+	sorted := make([]neatmath.NodeActivationType, 0, len(types))
+	for aType := range types {
+		sorted = append(sorted, aType)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	return sorted
+}
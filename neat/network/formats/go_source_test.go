@@ -0,0 +1,132 @@
+package formats
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	neatmath "github.com/yaricom/goNEAT/v4/neat/math"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteGoSource(t *testing.T) {
+	net := buildNetwork()
+	b := bytes.NewBufferString("")
+	err := WriteGoSource(b, net, GoSourceOptions{PackageName: "controller"})
+	require.NoError(t, err)
+
+	src := b.String()
+	_, err = parser.ParseFile(token.NewFileSet(), "controller.go", src, parser.AllErrors)
+	require.NoError(t, err, "generated source must be valid Go code")
+	assert.Contains(t, src, "package controller")
+	assert.Contains(t, src, "func Activate(in []float64) []float64")
+	assert.Contains(t, src, "ActivationSteps = 3")
+	assert.Contains(t, src, "func sigmoidSteepenedActivation(x float64) float64")
+	assert.Contains(t, src, "s[1]*15.0 + s[2]*10.0")
+}
+
+func TestWriteGoSource_Modular(t *testing.T) {
+	net := buildModularNetwork()
+	b := bytes.NewBufferString("")
+	err := WriteGoSource(b, net, GoSourceOptions{PackageName: "controller", ActivationSteps: 5})
+	require.NoError(t, err)
+
+	src := b.String()
+	_, err = parser.ParseFile(token.NewFileSet(), "controller.go", src, parser.AllErrors)
+	require.NoError(t, err, "generated source must be valid Go code")
+	assert.Contains(t, src, "ActivationSteps = 5")
+	assert.Contains(t, src, "func multiplyModuleActivation(inputs ...float64) float64")
+	assert.Contains(t, src, "p[7] = multiplyModuleActivation(p[5], p[6])")
+}
+
+func TestWriteGoSource_errors(t *testing.T) {
+	net := buildNetwork()
+	err := WriteGoSource(bytes.NewBufferString(""), net, GoSourceOptions{})
+	assert.EqualError(t, err, "the package name must be provided")
+
+	solver, err := net.FastNetworkSolver()
+	require.NoError(t, err)
+	fmm := solver.(*network.FastModularNetworkSolver)
+	err = WriteFMNSGoSource(bytes.NewBufferString(""), fmm, GoSourceOptions{PackageName: "controller"})
+	assert.Error(t, err, "activation steps must be positive")
+
+	err = WriteGoSourceTest(bytes.NewBufferString(""), fmm, GoSourceOptions{PackageName: "controller", ActivationSteps: 3}, 0, 42)
+	assert.Error(t, err, "samples number must be positive")
+
+	// unsupported activation
+	net.Outputs[0].ActivationType = neatmath.MultiplyModuleActivation
+	err = WriteGoSource(bytes.NewBufferString(""), net, GoSourceOptions{PackageName: "controller", ActivationSteps: 3})
+	assert.Error(t, err)
+
+	// unsupported aggregation
+	net = buildNetwork()
+	net.Outputs[0].AggregationType = neatmath.MaxAggregation
+	err = WriteGoSource(bytes.NewBufferString(""), net, GoSourceOptions{PackageName: "controller", ActivationSteps: 3})
+	assert.ErrorContains(t, err, "unsupported aggregation type: 2 of neuron at:")
+}
+
+func TestWriteGoSource_Write_Error(t *testing.T) {
+	errWriter := ErrorWriter(1)
+	err := WriteGoSource(&errWriter, buildNetwork(), GoSourceOptions{PackageName: "controller"})
+	assert.EqualError(t, err, alwaysErrorText)
+}
+
+func Test_goFloat(t *testing.T) {
+	assert.Equal(t, "1.0", goFloat(1))
+	assert.Equal(t, "(-2.5)", goFloat(-2.5))
+	assert.Equal(t, "1e-20", goFloat(1e-20))
+	assert.Equal(t, "math.NaN()", goFloat(math.NaN()))
+}
+
+func TestWriteGoSourceTest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping compilation of generated code in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("Go toolchain is not available")
+	}
+
+	recurrent := buildNetwork()
+	recurrent.AllNodes()[5].ConnectFrom(recurrent.Outputs[0], 0.5) // HIDDEN 6 <- OUTPUT 7
+
+	testCases := map[string]*network.Network{
+		"plain":     buildNetwork(),
+		"modular":   buildModularNetwork(),
+		"recurrent": recurrent,
+	}
+	for name, net := range testCases {
+		t.Run(name, func(t *testing.T) {
+			options := GoSourceOptions{PackageName: "controller", ActivationSteps: 3}
+			solver, err := net.FastNetworkSolver()
+			require.NoError(t, err)
+			fmm := solver.(*network.FastModularNetworkSolver)
+
+			dir := t.TempDir()
+			src := bytes.NewBufferString("")
+			err = WriteFMNSGoSource(src, fmm, options)
+			require.NoError(t, err)
+			err = os.WriteFile(filepath.Join(dir, "controller.go"), src.Bytes(), 0644)
+			require.NoError(t, err)
+
+			harness := bytes.NewBufferString("")
+			err = WriteGoSourceTest(harness, fmm, options, 20, 42)
+			require.NoError(t, err)
+			err = os.WriteFile(filepath.Join(dir, "controller_test.go"), harness.Bytes(), 0644)
+			require.NoError(t, err)
+			err = os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module controller\n\ngo 1.17\n"), 0644)
+			require.NoError(t, err)
+
+			cmd := exec.Command(goBin, "test", "./...")
+			cmd.Dir = dir
+			out, err := cmd.CombinedOutput()
+			assert.NoError(t, err, "generated test harness failed: %s", string(out))
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/yaricom/goNEAT/v4/neat/network"
 	"gonum.org/v1/gonum/graph/formats/cytoscapejs"
 	"io"
+	gomath "math"
+	"strconv"
 )
 
 // CytoscapeStyleOptions is to hold style options to be appended to the graph elements definition when serializing to
@@ -43,6 +45,104 @@ func WriteCytoscapeJSON(w io.Writer, n *network.Network) error {
 // Additionally, it is possible to provide style to be used for rendering of the graph.
 // For more details about style, see https://js.cytoscape.org/#getting-started/specifying-basic-options
 func WriteCytoscapeJSONWithStyle(w io.Writer, n *network.Network, style *CytoscapeStyleOptions) error {
+	return writeCytoscapeJSON(w, networkToCyJsElements(n), style)
+}
+
+// WriteCytoscapeJSONWithTrace is to write this network graph using Cytoscape JSON encoding with node and edge
+// attributes reflecting the activation recorded by provided trace at the given step. The activation_value and
+// activation_sum attributes of the nodes are set to the recorded values and the background-color is set to the color
+// which intensity is proportional to the node output: red for positive and blue for negative values. The edges get
+// the signal attribute if signals of links were recorded. This will use goNEAT default style for the graph.
+func WriteCytoscapeJSONWithTrace(w io.Writer, n *network.Network, trace *network.ActivationTrace, step int) error {
+	if trace == nil || step < 0 || step >= trace.Len() {
+		return fmt.Errorf("activation step: %d is out of the trace range", step)
+	}
+	elements := networkToCyJsElements(n)
+
+	// find the maximal magnitude of the outputs at the step to scale colors
+	maxOutput := 0.0
+	for _, out := range trace.Outputs[step] {
+		maxOutput = gomath.Max(maxOutput, gomath.Abs(out))
+	}
+	for _, node := range elements.Nodes {
+		if node.Data.Attributes[attrControlNode].(bool) {
+			continue
+		}
+		id, _ := strconv.Atoi(node.Data.ID)
+		if sum, out, ok := trace.NodeActivation(step, id); ok {
+			node.Data.Attributes[attrActivationValue] = out
+			node.Data.Attributes[attrActivationSum] = sum
+			node.Data.Attributes[attrBackgroundColor] = activationColor(out, maxOutput)
+		}
+	}
+	if trace.RecordsLinks() {
+		for _, edge := range elements.Edges {
+			source, _ := strconv.Atoi(edge.Data.Source)
+			target, _ := strconv.Atoi(edge.Data.Target)
+			if signal, ok := trace.LinkSignal(step, source, target); ok {
+				edge.Data.Attributes[attrSignal] = signal
+			}
+		}
+	}
+
+	style := &CytoscapeStyleOptions{
+		Style:  []ElementStyle{defaultNodeStyle(), defaultEdgeStyle()},
+		Layout: defaultLayout(),
+	}
+	return writeCytoscapeJSON(w, elements, style)
+}
+
+// WriteCytoscapeJSONWithScores is to write this network graph using Cytoscape JSON encoding with provided scores of
+// the nodes and links mapped onto the graph elements. The scores are stored as attributes named by the scores name. The
+// background-color of the scored nodes is set to the color which intensity is proportional to the node score: red for
+// positive and blue for negative values. The width of the scored edges is proportional to the absolute value of the
+// link score. This will use goNEAT default style for the graph with edges width taken from data.
+func WriteCytoscapeJSONWithScores(w io.Writer, n *network.Network, scores ElementScores) error {
+	elements := networkToCyJsElements(n)
+
+	maxNodeScore, maxLinkScore := maxScores(n, scores)
+	for _, node := range elements.Nodes {
+		id, _ := strconv.Atoi(node.Data.ID)
+		if score, ok := scores.NodeScore(id); ok {
+			node.Data.Attributes[scores.Name()] = score
+			node.Data.Attributes[attrBackgroundColor] = activationColor(score, maxNodeScore)
+		}
+	}
+	for _, edge := range elements.Edges {
+		source, _ := strconv.Atoi(edge.Data.Source)
+		target, _ := strconv.Atoi(edge.Data.Target)
+		width := minEdgeWidth
+		if score, ok := scores.LinkScore(source, target); ok {
+			edge.Data.Attributes[scores.Name()] = score
+			width = scoredEdgeWidth(score, maxLinkScore)
+		}
+		edge.Data.Attributes[attrWidth] = width
+	}
+
+	edgeStyle := defaultEdgeStyle()
+	edgeStyle.Style["width"] = "data(width)"
+	style := &CytoscapeStyleOptions{
+		Style:  []ElementStyle{defaultNodeStyle(), edgeStyle},
+		Layout: defaultLayout(),
+	}
+	return writeCytoscapeJSON(w, elements, style)
+}
+
+// activationColor returns the color of the node with given activation output. The color is red for positive and blue
+// for negative values with intensity proportional to the value magnitude relative to the provided maximal magnitude.
+func activationColor(value, maxValue float64) string {
+	intensity := 0.0
+	if maxValue > 0 {
+		intensity = gomath.Min(gomath.Abs(value)/maxValue, 1)
+	}
+	fade := int(gomath.Round(255 * (1 - intensity)))
+	if value < 0 {
+		return fmt.Sprintf("#%02X%02XFF", fade, fade)
+	}
+	return fmt.Sprintf("#FF%02X%02X", fade, fade)
+}
+
+func networkToCyJsElements(n *network.Network) cytoscapejs.Elements {
 	elements := cytoscapejs.Elements{
 		Nodes: make([]cytoscapejs.Node, 0),
 		Edges: make([]cytoscapejs.Edge, 0),
@@ -72,7 +172,10 @@ func WriteCytoscapeJSONWithStyle(w io.Writer, n *network.Network, style *Cytosca
 			elements.Edges = append(elements.Edges, linkToCyJsEdge(e))
 		}
 	}
+	return elements
+}
 
+func writeCytoscapeJSON(w io.Writer, elements cytoscapejs.Elements, style *CytoscapeStyleOptions) error {
 	// create Cytoscape graph
 	graphNodeEdge := cytoscapejs.GraphNodeEdge{
 		Elements: elements,
@@ -99,7 +202,9 @@ func WriteCytoscapeJSONWithStyle(w io.Writer, n *network.Network, style *Cytosca
 
 const (
 	attrActivationValue        = "activation_value"
+	attrActivationSum          = "activation_sum"
 	attrActivationFunc         = "activation_function"
+	attrAggregationFunc        = "aggregation_function"
 	attrNeuronType             = "neuron_type"
 	attrNodeType               = "node_type"
 	attrInputConnectionsCount  = "in_connections_count"
@@ -109,6 +214,8 @@ const (
 	attrBorderColor            = "border-color"
 	attrShape                  = "shape"
 	attrTrait                  = "trait"
+	attrSignal                 = "signal"
+	attrWidth                  = "width"
 )
 
 func nodeToCyJsNode(node *network.NNode, control bool) cytoscapejs.Node {
@@ -137,6 +244,13 @@ func nodeToCyJsNode(node *network.NNode, control bool) cytoscapejs.Node {
 	if node.Trait != nil {
 		nodeJS.Data.Attributes[attrTrait] = node.Trait.String()
 	}
+	if node.AggregationType != math.SumAggregation {
+		aggName, err := math.AggregationNameFromType(node.AggregationType)
+		if err != nil {
+			aggName = "unknown"
+		}
+		nodeJS.Data.Attributes[attrAggregationFunc] = aggName
+	}
 	return nodeJS
 }
 
@@ -2,11 +2,14 @@ package formats
 
 import (
 	"bytes"
+	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/math"
 	"github.com/yaricom/goNEAT/v4/neat/network"
+	"gonum.org/v1/gonum/graph/formats/cytoscapejs"
+	"strconv"
 	"testing"
 )
 
@@ -201,12 +204,128 @@ func TestWriteCytoscapeJSON_nodeToCyJsNode(t *testing.T) {
 		assert.Equal(t, attrs, nodeJS.Data.Attributes)
 
 		// check unknown activation type
-		node.ActivationType = math.MinModuleActivation + 1
+		node.ActivationType = math.FirstCustomActivation
 		nodeJS = nodeToCyJsNode(node, tc.control)
 		require.NotNil(t, nodeJS)
 		require.NotEmpty(t, nodeJS.Data.Attributes)
 
 		attrs[attrActivationFunc] = "unknown"
 		assert.Equal(t, attrs, nodeJS.Data.Attributes)
+
+		// check aggregation type
+		node.AggregationType = math.MedianAggregation
+		nodeJS = nodeToCyJsNode(node, tc.control)
+		attrs[attrAggregationFunc] = "MedianAggregation"
+		assert.Equal(t, attrs, nodeJS.Data.Attributes)
+		node.AggregationType = math.SumAggregation
+		delete(attrs, attrAggregationFunc)
+	}
+}
+
+func TestWriteCytoscapeJSONWithTrace(t *testing.T) {
+	net := buildModularNetwork()
+	trace := network.NewActivationTrace(true)
+	net.SetActivationTrace(trace)
+	err := net.LoadSensors([]float64{1.0, 2.0})
+	require.NoError(t, err)
+	_, err = net.ForwardSteps(3)
+	require.NoError(t, err)
+
+	step := trace.Len() - 1
+	b := bytes.NewBufferString("")
+	err = WriteCytoscapeJSONWithTrace(b, net, trace, step)
+	require.NoError(t, err)
+
+	var graph cytoscapejs.GraphNodeEdge
+	err = json.Unmarshal(b.Bytes(), &graph)
+	require.NoError(t, err)
+	require.Len(t, graph.Elements.Nodes, 9)
+	for _, node := range graph.Elements.Nodes {
+		attrs := node.Data.Attributes
+		if attrs[attrControlNode].(bool) {
+			assert.Equal(t, colorControl, attrs[attrBackgroundColor])
+			assert.NotContains(t, attrs, attrActivationSum)
+			continue
+		}
+		id, err := strconv.Atoi(node.Data.ID)
+		require.NoError(t, err)
+		sum, out, ok := trace.NodeActivation(step, id)
+		require.True(t, ok)
+		assert.Equal(t, out, attrs[attrActivationValue], "wrong activation of node: %d", id)
+		assert.Equal(t, sum, attrs[attrActivationSum], "wrong activation sum of node: %d", id)
 	}
+	for _, edge := range graph.Elements.Edges {
+		source, _ := strconv.Atoi(edge.Data.Source)
+		target, _ := strconv.Atoi(edge.Data.Target)
+		if signal, ok := trace.LinkSignal(step, source, target); ok {
+			assert.Equal(t, signal, edge.Data.Attributes[attrSignal])
+		} else {
+			// links of the control node are not traced
+			assert.NotContains(t, edge.Data.Attributes, attrSignal)
+		}
+	}
+}
+
+func TestWriteCytoscapeJSONWithTrace_wrongStep(t *testing.T) {
+	net := buildNetwork()
+	trace := network.NewActivationTrace(false)
+	b := bytes.NewBufferString("")
+	err := WriteCytoscapeJSONWithTrace(b, net, trace, 0)
+	assert.Error(t, err)
+
+	err = WriteCytoscapeJSONWithTrace(b, net, nil, 0)
+	assert.Error(t, err)
+}
+
+func TestWriteCytoscapeJSON_activationColor(t *testing.T) {
+	testCases := []struct {
+		value    float64
+		maxValue float64
+		color    string
+	}{
+		{value: 1.0, maxValue: 1.0, color: "#FF0000"},
+		{value: -1.0, maxValue: 1.0, color: "#0000FF"},
+		{value: 0.5, maxValue: 1.0, color: "#FF8080"},
+		{value: 0.0, maxValue: 1.0, color: "#FFFFFF"},
+		{value: 0.0, maxValue: 0.0, color: "#FFFFFF"},
+	}
+	for i, tc := range testCases {
+		assert.Equal(t, tc.color, activationColor(tc.value, tc.maxValue), "wrong color at: %d", i)
+	}
+}
+
+func TestWriteCytoscapeJSONWithScores(t *testing.T) {
+	net := buildNetwork()
+	scores := buildTestScores()
+
+	b := bytes.NewBufferString("")
+	err := WriteCytoscapeJSONWithScores(b, net, scores)
+	require.NoError(t, err)
+
+	var graph cytoscapejs.GraphNodeEdge
+	err = json.Unmarshal(b.Bytes(), &graph)
+	require.NoError(t, err)
+	for _, node := range graph.Elements.Nodes {
+		id, err := strconv.Atoi(node.Data.ID)
+		require.NoError(t, err)
+		if score, ok := scores.NodeScore(id); ok {
+			assert.Equal(t, score, node.Data.Attributes[scores.Name()])
+		} else {
+			assert.NotContains(t, node.Data.Attributes, scores.Name())
+		}
+	}
+	assert.Equal(t, "#FF0000", graph.Elements.Nodes[0].Data.Attributes[attrBackgroundColor])
+	assert.Equal(t, "#8080FF", graph.Elements.Nodes[3].Data.Attributes[attrBackgroundColor])
+	assert.Equal(t, colorInput, graph.Elements.Nodes[1].Data.Attributes[attrBackgroundColor])
+
+	widths := make(map[string]interface{})
+	for _, edge := range graph.Elements.Edges {
+		widths[edge.Data.ID] = edge.Data.Attributes[attrWidth]
+	}
+	assert.Equal(t, 5.5, widths["1-4"])
+	assert.Equal(t, maxEdgeWidth, widths["6-8"])
+	assert.Equal(t, minEdgeWidth, widths["2-4"])
+
+	edgeStyle := graph.Style[1].(map[string]interface{})["style"].(map[string]interface{})
+	assert.Equal(t, "data(width)", edgeStyle["width"])
 }
@@ -1,8 +1,12 @@
 package formats
 
 import (
+	"fmt"
 	"github.com/yaricom/goNEAT/v4/neat/network"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
 	"gonum.org/v1/gonum/graph/encoding/dot"
+	"gonum.org/v1/gonum/graph/iterator"
 	"io"
 )
 
@@ -18,3 +22,84 @@ func WriteDOT(w io.Writer, n *network.Network) error {
 	}
 	return nil
 }
+
+// WriteDOTWithScores is to write provided network graph using the GraphViz DOT encoding with provided scores of the
+// nodes and links mapped onto the graph elements. The scores are stored as attributes named by the scores name. The
+// scored nodes are filled with the color which intensity is proportional to the node score: red for positive and blue
+// for negative values. The pen width of the scored edges is proportional to the absolute value of the link score.
+func WriteDOTWithScores(w io.Writer, n *network.Network, scores ElementScores) error {
+	maxNodeScore, maxLinkScore := maxScores(n, scores)
+	g := scoredGraph{
+		Network:      n,
+		scores:       scores,
+		maxNodeScore: maxNodeScore,
+		maxLinkScore: maxLinkScore,
+	}
+	data, err := dot.Marshal(g, n.Name, "", "")
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// scoredGraph is the network graph with nodes and edges attributes extended by scores
+type scoredGraph struct {
+	*network.Network
+	scores       ElementScores
+	maxNodeScore float64
+	maxLinkScore float64
+}
+
+func (g scoredGraph) Nodes() graph.Nodes {
+	nodes := make([]graph.Node, len(g.AllNodes()))
+	for i, node := range g.AllNodes() {
+		nodes[i] = scoredNode{NNode: node, graph: g}
+	}
+	return iterator.NewOrderedNodes(nodes)
+}
+
+func (g scoredGraph) Edge(uid, vid int64) graph.Edge {
+	e := g.Network.Edge(uid, vid)
+	if l, ok := e.(*network.Link); ok && l != nil {
+		return scoredLink{Link: l, graph: g}
+	}
+	return e
+}
+
+// scoredNode is the network node with attributes extended by score
+type scoredNode struct {
+	*network.NNode
+	graph scoredGraph
+}
+
+func (n scoredNode) Attributes() []encoding.Attribute {
+	attrs := n.NNode.Attributes()
+	if score, ok := n.graph.scores.NodeScore(n.Id); ok {
+		attrs = append(attrs,
+			encoding.Attribute{Key: n.graph.scores.Name(), Value: fmt.Sprintf("%f", score)},
+			encoding.Attribute{Key: "style", Value: "filled"},
+			encoding.Attribute{Key: "fillcolor", Value: fmt.Sprintf("%q", activationColor(score, n.graph.maxNodeScore))},
+		)
+	}
+	return attrs
+}
+
+// scoredLink is the network link with attributes extended by score
+type scoredLink struct {
+	*network.Link
+	graph scoredGraph
+}
+
+func (l scoredLink) Attributes() []encoding.Attribute {
+	attrs := l.Link.Attributes()
+	if score, ok := l.graph.scores.LinkScore(l.InNode.Id, l.OutNode.Id); ok {
+		attrs = append(attrs,
+			encoding.Attribute{Key: l.graph.scores.Name(), Value: fmt.Sprintf("%f", score)},
+			encoding.Attribute{Key: "penwidth", Value: fmt.Sprintf("%f", scoredEdgeWidth(score, l.graph.maxLinkScore))},
+		)
+	}
+	return attrs
+}
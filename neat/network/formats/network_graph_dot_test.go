@@ -26,3 +26,45 @@ func TestWriteDOT_Write_Error(t *testing.T) {
 	err := WriteDOT(&errWriter, net)
 	assert.EqualError(t, err, alwaysErrorText)
 }
+
+type testScores struct {
+	nodes map[int]float64
+	links map[[2]int]float64
+}
+
+func (s testScores) Name() string {
+	return "score"
+}
+
+func (s testScores) NodeScore(id int) (float64, bool) {
+	score, ok := s.nodes[id]
+	return score, ok
+}
+
+func (s testScores) LinkScore(inId, outId int) (float64, bool) {
+	score, ok := s.links[[2]int{inId, outId}]
+	return score, ok
+}
+
+func buildTestScores() testScores {
+	return testScores{
+		nodes: map[int]float64{1: 2.0, 4: -1.0},
+		links: map[[2]int]float64{{1, 4}: 0.5, {6, 8}: 1.0},
+	}
+}
+
+func TestWriteDOTWithScores(t *testing.T) {
+	net := buildNetwork()
+	net.Name = "TestNN"
+
+	b := bytes.NewBufferString("")
+	err := WriteDOTWithScores(b, net, buildTestScores())
+	require.NoError(t, err, "failed to DOT encode")
+	t.Log(b)
+	dotStr := b.String()
+	assert.Contains(t, dotStr, `fillcolor="#FF0000"`)
+	assert.Contains(t, dotStr, `fillcolor="#8080FF"`)
+	assert.Contains(t, dotStr, "penwidth=5.500000")
+	assert.Contains(t, dotStr, "penwidth=10.000000")
+	assert.Contains(t, dotStr, "score=2.000000")
+}
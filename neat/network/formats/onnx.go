@@ -0,0 +1,392 @@
+package formats
+
+import (
+	"errors"
+	"fmt"
+	neatmath "github.com/yaricom/goNEAT/v4/neat/math"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"io"
+	"sort"
+)
+
+const (
+	// ONNXInputName the name of the ONNX graph input with network inputs values, shape: [batch, inputs]
+	ONNXInputName = "inputs"
+	// ONNXOutputName the name of the ONNX graph output with network outputs values, shape: [batch, outputs]
+	ONNXOutputName = "outputs"
+
+	onnxIRVersion    = 8
+	onnxOpsetVersion = 13
+)
+
+// WriteONNX is to write provided acyclic network as the ONNX model (https://onnx.ai) with single graph which has
+// the input named ONNXInputName and the output named ONNXOutputName. Both are two-dimensional float tensors with
+// dynamic batch size as first dimension, and the number of network inputs (BIAS excluded) and outputs as the second
+// one. The BIAS nodes have constant value 1.0.
+//
+// The neurons are arranged into layers by their activation depth, i.e., by the length of the longest path from
+// the sensors, thus the number of layers is equal to the maximal activation depth of the network when every neuron
+// is connected to the outputs. The neurons of each layer are grouped by their activation function and each group is
+// evaluated by selecting sources of its incoming connections with the Gather operator followed by the MatMul with
+// the masked weights matrix. The activation functions are mapped to the ONNX operators or composed of them if there
+// is no corresponding operator. The values are calculated with float32 precision. The same as with network.Network
+// activation, the neurons not reachable from the sensors have zero value.
+//
+// Returns error if network has recurrent connections, control nodes, or unsupported activation functions.
+func WriteONNX(w io.Writer, n *network.Network) error {
+	model, err := buildONNXModel(n)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(model.encode())
+	return err
+}
+
+// onnxGraphBuilder is to build ONNX graph with unique names of values and deduplicated constants
+type onnxGraphBuilder struct {
+	model     *onnxModel
+	counter   int
+	constants map[float32]string
+}
+
+// op is to add operator node and return the name of its output
+func (b *onnxGraphBuilder) op(opType string, inputs []string, attributes ...onnxAttribute) string {
+	b.counter++
+	output := fmt.Sprintf("%s_%d", opType, b.counter)
+	return b.namedOp(output, opType, inputs, attributes...)
+}
+
+// namedOp is to add operator node with given name of the output
+func (b *onnxGraphBuilder) namedOp(output, opType string, inputs []string, attributes ...onnxAttribute) string {
+	b.model.nodes = append(b.model.nodes, &onnxNode{
+		name:       fmt.Sprintf("node_%s", output),
+		opType:     opType,
+		inputs:     inputs,
+		outputs:    []string{output},
+		attributes: attributes,
+	})
+	return output
+}
+
+// scalar is to return the name of the scalar float constant with given value
+func (b *onnxGraphBuilder) scalar(v float32) string {
+	if name, ok := b.constants[v]; ok {
+		return name
+	}
+	name := fmt.Sprintf("const_%d", len(b.constants))
+	b.model.initializers = append(b.model.initializers, &onnxTensor{name: name, dataType: onnxFloat, floats: []float32{v}})
+	b.constants[v] = name
+	return name
+}
+
+// tensor is to add initializer tensor and return its name
+func (b *onnxGraphBuilder) tensor(prefix string, t *onnxTensor) string {
+	b.counter++
+	t.name = fmt.Sprintf("%s_%d", prefix, b.counter)
+	b.model.initializers = append(b.model.initializers, t)
+	return t.name
+}
+
+// gather is to select given columns of the two-dimensional tensor
+func (b *onnxGraphBuilder) gather(input string, columns []int) string {
+	indices := make([]int64, len(columns))
+	for i, c := range columns {
+		indices[i] = int64(c)
+	}
+	name := b.tensor("indices", &onnxTensor{dims: []int64{int64(len(indices))}, dataType: onnxInt64, ints: indices})
+	return b.op("Gather", []string{input, name}, onnxAttribute{name: "axis", attributeType: onnxAttributeInt, i: 1})
+}
+
+// constantColumns is to create two-dimensional tensor with the batch size of the input and given number of columns
+// filled with provided value
+func (b *onnxGraphBuilder) constantColumns(columns int, value float32) string {
+	shape := b.op("Shape", []string{ONNXInputName})
+	batch := b.op("Gather", []string{shape, b.tensor("indices", &onnxTensor{
+		dims: []int64{1}, dataType: onnxInt64, ints: []int64{0}})})
+	cols := b.tensor("columns", &onnxTensor{dims: []int64{1}, dataType: onnxInt64, ints: []int64{int64(columns)}})
+	outShape := b.op("Concat", []string{batch, cols}, onnxAttribute{name: "axis", attributeType: onnxAttributeInt, i: 0})
+	return b.op("ConstantOfShape", []string{outShape}, onnxAttribute{name: "value", attributeType: onnxAttributeTensor,
+		t: &onnxTensor{dims: []int64{1}, dataType: onnxFloat, floats: []float32{value}}})
+}
+
+// activation is to apply activation function of given type to the input
+func (b *onnxGraphBuilder) activation(x string, aType neatmath.NodeActivationType) (string, error) {
+	mul := func(a, c string) string { return b.op("Mul", []string{a, c}) }
+	add := func(a, c string) string { return b.op("Add", []string{a, c}) }
+	sub := func(a, c string) string { return b.op("Sub", []string{a, c}) }
+	sigmoid := func(a string) string { return b.op("Sigmoid", []string{a}) }
+	// approximation is piecewise quadratic sigmoid approximation with squashing range [-r; r]
+	approximation := func(r float32) string {
+		clipped := b.op("Clip", []string{x, b.scalar(-r), b.scalar(r)})
+		scale := b.scalar(1 / (2 * r * r))
+		left := add(clipped, b.scalar(r))
+		left = mul(mul(left, left), scale)
+		right := sub(clipped, b.scalar(r))
+		right = sub(b.scalar(1), mul(mul(right, right), scale))
+		return b.op("Where", []string{b.op("Less", []string{clipped, b.scalar(0)}), left, right})
+	}
+	steepness := func() string { return b.scalar(4.924273) }
+	shift := func() string { return b.scalar(2.4621365) }
+
+	switch aType {
+	case neatmath.SigmoidPlainActivation:
+		return sigmoid(x), nil
+	case neatmath.SigmoidReducedActivation:
+		return sigmoid(mul(x, b.scalar(0.5))), nil
+	case neatmath.SigmoidSteepenedActivation:
+		return sigmoid(mul(x, steepness())), nil
+	case neatmath.SigmoidBipolarActivation:
+		return sub(mul(sigmoid(mul(x, steepness())), b.scalar(2)), b.scalar(1)), nil
+	case neatmath.SigmoidApproximationActivation:
+		return approximation(4), nil
+	case neatmath.SigmoidSteepenedApproximationActivation:
+		return approximation(1), nil
+	case neatmath.SigmoidInverseAbsoluteActivation:
+		return add(mul(b.op("Softsign", []string{x}), b.scalar(0.5)), b.scalar(0.5)), nil
+	case neatmath.SigmoidLeftShiftedActivation:
+		return sigmoid(add(x, shift())), nil
+	case neatmath.SigmoidLeftShiftedSteepenedActivation:
+		return sigmoid(add(mul(x, steepness()), shift())), nil
+	case neatmath.SigmoidRightShiftedSteepenedActivation:
+		return sigmoid(sub(mul(x, steepness()), shift())), nil
+	case neatmath.TanhActivation:
+		return b.op("Tanh", []string{mul(x, b.scalar(0.9))}), nil
+	case neatmath.GaussianBipolarActivation:
+		scaled := mul(x, b.scalar(2.5))
+		gaussian := b.op("Exp", []string{b.op("Neg", []string{mul(scaled, scaled)})})
+		return sub(mul(gaussian, b.scalar(2)), b.scalar(1)), nil
+	case neatmath.GaussianActivation:
+		return b.op("Exp", []string{b.op("Neg", []string{mul(x, x)})}), nil
+	case neatmath.LinearActivation:
+		return x, nil
+	case neatmath.LinearAbsActivation:
+		return b.op("Abs", []string{x}), nil
+	case neatmath.LinearClippedActivation:
+		return b.op("Clip", []string{x, b.scalar(-1), b.scalar(1)}), nil
+	case neatmath.NullActivation:
+		return mul(x, b.scalar(0)), nil
+	case neatmath.SignActivation:
+		return b.op("Sign", []string{x}), nil
+	case neatmath.SineActivation:
+		return b.op("Sin", []string{mul(x, b.scalar(2))}), nil
+	case neatmath.StepActivation:
+		negative := b.op("Less", []string{x, b.scalar(0)})
+		return b.op("Where", []string{negative, b.scalar(0), b.scalar(1)}), nil
+	default:
+		name, err := neatmath.NodeActivators.ActivationNameFromType(aType)
+		if err != nil {
+			name = fmt.Sprintf("%d", aType)
+		}
+		return "", fmt.Errorf("activation function is not supported by ONNX export: %s", name)
+	}
+}
+
+// buildONNXModel is to build ONNX model of the provided network
+func buildONNXModel(n *network.Network) (*onnxModel, error) {
+	if len(n.ControlNodes()) > 0 {
+		return nil, errors.New("networks with control nodes are not supported by ONNX export")
+	}
+
+	// collect sensors: inputs followed by BIAS
+	inputs, biases := make([]*network.NNode, 0), make([]*network.NNode, 0)
+	for _, node := range n.BaseNodes() {
+		if node.AggregationType != neatmath.SumAggregation {
+			return nil, fmt.Errorf("aggregation function other than sum of node: %d is not supported by ONNX export",
+				node.Id)
+		}
+		switch node.NeuronType {
+		case network.InputNeuron:
+			inputs = append(inputs, node)
+		case network.BiasNeuron:
+			biases = append(biases, node)
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, errors.New("network without input nodes can not be exported to ONNX")
+	}
+	sensors := append(append(make([]*network.NNode, 0), inputs...), biases...)
+
+	layers, err := onnxLayers(n, sensors)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &onnxGraphBuilder{
+		model: &onnxModel{
+			irVersion:    onnxIRVersion,
+			opsetVersion: onnxOpsetVersion,
+			producer:     "goNEAT",
+			graphName:    n.Name,
+			docString:    fmt.Sprintf("goNEAT network id: %d, name: %s", n.Id, n.Name),
+			inputs:       []onnxValueInfo{{name: ONNXInputName, columns: int64(len(inputs))}},
+			outputs:      []onnxValueInfo{{name: ONNXOutputName, columns: int64(len(n.Outputs))}},
+		},
+		constants: make(map[float32]string),
+	}
+	if b.model.graphName == "" {
+		b.model.graphName = "network"
+	}
+
+	// the values of all calculated nodes are accumulated as columns of the layer tensor
+	columns := make(map[*network.NNode]int)
+	for i, node := range sensors {
+		columns[node] = i
+	}
+	current := ONNXInputName
+	if len(biases) > 0 {
+		current = b.op("Concat", []string{ONNXInputName, b.constantColumns(len(biases), 1)},
+			onnxAttribute{name: "axis", attributeType: onnxAttributeInt, i: 1})
+	}
+	for l, layer := range layers {
+		values := []string{current}
+		for _, group := range groupByActivation(layer) {
+			// collect connected sources and masked weights matrix
+			sources := make([]int, 0)
+			sourceRows := make(map[int]int)
+			for _, node := range group {
+				for _, link := range node.Incoming {
+					if column, ok := columns[link.InNode]; ok {
+						if _, ok = sourceRows[column]; !ok {
+							sourceRows[column] = len(sources)
+							sources = append(sources, column)
+						}
+					}
+				}
+			}
+			weights := make([]float32, len(sources)*len(group))
+			for j, node := range group {
+				for _, link := range node.Incoming {
+					if column, ok := columns[link.InNode]; ok {
+						weights[sourceRows[column]*len(group)+j] += float32(link.ConnectionWeight)
+					}
+				}
+			}
+			weightsName := b.tensor("weights", &onnxTensor{
+				dims: []int64{int64(len(sources)), int64(len(group))}, dataType: onnxFloat, floats: weights})
+			sum := b.op("MatMul", []string{b.gather(current, sources), weightsName})
+			value, err := b.activation(sum, group[0].ActivationType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to export node: %d: %w", group[0].Id, err)
+			}
+			values = append(values, value)
+			for _, node := range group {
+				columns[node] = len(columns)
+			}
+		}
+		current = b.namedOp(fmt.Sprintf("layer_%d", l+1), "Concat", values,
+			onnxAttribute{name: "axis", attributeType: onnxAttributeInt, i: 1})
+	}
+
+	// collect outputs, the unreachable outputs have zero value
+	indices := make([]int64, len(n.Outputs))
+	zeroColumn := -1
+	for i, node := range n.Outputs {
+		column, ok := columns[node]
+		if !ok {
+			if zeroColumn < 0 {
+				current = b.op("Concat", []string{current, b.constantColumns(1, 0)},
+					onnxAttribute{name: "axis", attributeType: onnxAttributeInt, i: 1})
+				zeroColumn = len(columns)
+			}
+			column = zeroColumn
+		}
+		indices[i] = int64(column)
+	}
+	indicesName := b.tensor("indices", &onnxTensor{dims: []int64{int64(len(indices))}, dataType: onnxInt64, ints: indices})
+	b.namedOp(ONNXOutputName, "Gather", []string{current, indicesName},
+		onnxAttribute{name: "axis", attributeType: onnxAttributeInt, i: 1})
+
+	return b.model, nil
+}
+
+// onnxLayers is to arrange the neurons reachable from sensors into layers by the length of the longest path from
+// sensors. Returns error if network has recurrent connections.
+func onnxLayers(n *network.Network, sensors []*network.NNode) ([][]*network.NNode, error) {
+	// find nodes reachable from sensors
+	reachable := make(map[*network.NNode]bool)
+	queue := append(make([]*network.NNode, 0), sensors...)
+	for _, node := range sensors {
+		reachable[node] = true
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, link := range node.Outgoing {
+			if link.IsTimeDelayed {
+				return nil, fmt.Errorf("time delayed link from node: %d to node: %d is not supported by ONNX export",
+					link.InNode.Id, link.OutNode.Id)
+			}
+			if !reachable[link.OutNode] && !link.OutNode.IsSensor() {
+				reachable[link.OutNode] = true
+				queue = append(queue, link.OutNode)
+			}
+		}
+	}
+
+	// topological sorting of reachable nodes with calculation of the longest path length
+	inDegree := make(map[*network.NNode]int)
+	for node := range reachable {
+		for _, link := range node.Incoming {
+			if reachable[link.InNode] && !node.IsSensor() {
+				inDegree[node]++
+			}
+		}
+	}
+	depth := make(map[*network.NNode]int)
+	queue = append(queue, sensors...)
+	visited := 0
+	maxDepth := 0
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, link := range node.Outgoing {
+			next := link.OutNode
+			if next.IsSensor() {
+				continue
+			}
+			if depth[node]+1 > depth[next] {
+				depth[next] = depth[node] + 1
+			}
+			if depth[next] > maxDepth {
+				maxDepth = depth[next]
+			}
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	if visited != len(reachable) {
+		return nil, fmt.Errorf("failed to export to ONNX: %w", network.ErrNetNotFeedForward)
+	}
+
+	layers := make([][]*network.NNode, maxDepth)
+	for _, node := range n.BaseNodes() {
+		if reachable[node] && depth[node] > 0 {
+			layers[depth[node]-1] = append(layers[depth[node]-1], node)
+		}
+	}
+	return layers, nil
+}
+
+// groupByActivation is to split provided nodes into groups with the same activation function preserving the order
+// of nodes
+func groupByActivation(nodes []*network.NNode) [][]*network.NNode {
+	groups := make(map[neatmath.NodeActivationType][]*network.NNode)
+	for _, node := range nodes {
+		groups[node.ActivationType] = append(groups[node.ActivationType], node)
+	}
+	types := make([]neatmath.NodeActivationType, 0, len(groups))
+	for aType := range groups {
+		types = append(types, aType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	result := make([][]*network.NNode, len(types))
+	for i, aType := range types {
+		result[i] = groups[aType]
+	}
+	return result
+}
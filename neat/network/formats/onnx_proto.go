@@ -0,0 +1,230 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// The protobuf wire types
+const (
+	protoWireVarint  = 0
+	protoWireBytes   = 2
+	protoWireFixed32 = 5
+)
+
+// The ONNX tensor element data types
+const (
+	onnxFloat = 1
+	onnxInt64 = 7
+)
+
+// The ONNX attribute types
+const (
+	onnxAttributeFloat  = 1
+	onnxAttributeInt    = 2
+	onnxAttributeTensor = 4
+)
+
+// protoBuffer is the minimal protobuf encoder sufficient to write ONNX models without additional dependencies
+type protoBuffer struct {
+	bytes.Buffer
+}
+
+func (p *protoBuffer) varint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	_, _ = p.Write(buf[:n])
+}
+
+func (p *protoBuffer) tag(field, wireType int) {
+	p.varint(uint64(field<<3 | wireType))
+}
+
+func (p *protoBuffer) int64Field(field int, v int64) {
+	p.tag(field, protoWireVarint)
+	p.varint(uint64(v))
+}
+
+func (p *protoBuffer) float32Field(field int, v float32) {
+	p.tag(field, protoWireFixed32)
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], math.Float32bits(v))
+	_, _ = p.Write(buf[:])
+}
+
+func (p *protoBuffer) bytesField(field int, v []byte) {
+	p.tag(field, protoWireBytes)
+	p.varint(uint64(len(v)))
+	_, _ = p.Write(v)
+}
+
+func (p *protoBuffer) stringField(field int, v string) {
+	p.bytesField(field, []byte(v))
+}
+
+func (p *protoBuffer) messageField(field int, m *protoBuffer) {
+	p.bytesField(field, m.Bytes())
+}
+
+func (p *protoBuffer) packedFloat32Field(field int, values []float32) {
+	buf := make([]byte, 4*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	p.bytesField(field, buf)
+}
+
+func (p *protoBuffer) packedInt64Field(field int, values []int64) {
+	m := protoBuffer{}
+	for _, v := range values {
+		m.varint(uint64(v))
+	}
+	p.messageField(field, &m)
+}
+
+// onnxTensor is the ONNX TensorProto
+type onnxTensor struct {
+	name     string
+	dims     []int64
+	dataType int
+	floats   []float32
+	ints     []int64
+}
+
+func (t *onnxTensor) encode() *protoBuffer {
+	p := &protoBuffer{}
+	if len(t.dims) > 0 {
+		p.packedInt64Field(1, t.dims)
+	}
+	p.int64Field(2, int64(t.dataType))
+	switch t.dataType {
+	case onnxFloat:
+		p.packedFloat32Field(4, t.floats)
+	case onnxInt64:
+		p.packedInt64Field(7, t.ints)
+	}
+	if t.name != "" {
+		p.stringField(8, t.name)
+	}
+	return p
+}
+
+// onnxAttribute is the ONNX AttributeProto
+type onnxAttribute struct {
+	name          string
+	attributeType int
+	f             float32
+	i             int64
+	t             *onnxTensor
+}
+
+func (a *onnxAttribute) encode() *protoBuffer {
+	p := &protoBuffer{}
+	p.stringField(1, a.name)
+	switch a.attributeType {
+	case onnxAttributeFloat:
+		p.float32Field(2, a.f)
+	case onnxAttributeInt:
+		p.int64Field(3, a.i)
+	case onnxAttributeTensor:
+		p.messageField(5, a.t.encode())
+	}
+	p.int64Field(20, int64(a.attributeType))
+	return p
+}
+
+// onnxNode is the ONNX NodeProto
+type onnxNode struct {
+	name       string
+	opType     string
+	inputs     []string
+	outputs    []string
+	attributes []onnxAttribute
+}
+
+func (n *onnxNode) encode() *protoBuffer {
+	p := &protoBuffer{}
+	for _, in := range n.inputs {
+		p.stringField(1, in)
+	}
+	for _, out := range n.outputs {
+		p.stringField(2, out)
+	}
+	p.stringField(3, n.name)
+	p.stringField(4, n.opType)
+	for i := range n.attributes {
+		p.messageField(5, n.attributes[i].encode())
+	}
+	return p
+}
+
+// onnxValueInfo is the ONNX ValueInfoProto of the two-dimensional tensor with dynamic batch size dimension
+type onnxValueInfo struct {
+	name    string
+	columns int64
+}
+
+func (v *onnxValueInfo) encode() *protoBuffer {
+	batchDim, columnsDim := &protoBuffer{}, &protoBuffer{}
+	batchDim.stringField(2, "batch")
+	columnsDim.int64Field(1, v.columns)
+	shape := &protoBuffer{}
+	shape.messageField(1, batchDim)
+	shape.messageField(1, columnsDim)
+
+	tensorType := &protoBuffer{}
+	tensorType.int64Field(1, onnxFloat)
+	tensorType.messageField(2, shape)
+	typeProto := &protoBuffer{}
+	typeProto.messageField(1, tensorType)
+
+	p := &protoBuffer{}
+	p.stringField(1, v.name)
+	p.messageField(2, typeProto)
+	return p
+}
+
+// onnxModel is the ONNX ModelProto with single graph
+type onnxModel struct {
+	irVersion    int64
+	opsetVersion int64
+	producer     string
+	graphName    string
+	docString    string
+	nodes        []*onnxNode
+	initializers []*onnxTensor
+	inputs       []onnxValueInfo
+	outputs      []onnxValueInfo
+}
+
+func (m *onnxModel) encode() []byte {
+	graph := &protoBuffer{}
+	for _, n := range m.nodes {
+		graph.messageField(1, n.encode())
+	}
+	graph.stringField(2, m.graphName)
+	for _, t := range m.initializers {
+		graph.messageField(5, t.encode())
+	}
+	if m.docString != "" {
+		graph.stringField(10, m.docString)
+	}
+	for i := range m.inputs {
+		graph.messageField(11, m.inputs[i].encode())
+	}
+	for i := range m.outputs {
+		graph.messageField(12, m.outputs[i].encode())
+	}
+
+	opset := &protoBuffer{}
+	opset.stringField(1, "")
+	opset.int64Field(2, m.opsetVersion)
+
+	p := &protoBuffer{}
+	p.int64Field(1, m.irVersion)
+	p.stringField(2, m.producer)
+	p.messageField(7, graph)
+	p.messageField(8, opset)
+	return p.Bytes()
+}
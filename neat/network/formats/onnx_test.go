@@ -0,0 +1,477 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	neatmath "github.com/yaricom/goNEAT/v4/neat/math"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"math"
+	"testing"
+)
+
+func TestWriteONNX(t *testing.T) {
+	testCases := map[string]*network.Network{
+		"hidden":      buildNetwork(),
+		"activations": buildActivationsNetwork(),
+		"unreachable": buildUnreachableNetwork(),
+	}
+	inputs := [][]float64{{0.5, 1.1}, {0.0, 0.0}, {-1.0, 2.0}, {0.1, -0.3}, {-0.25, -0.5}}
+	for name, net := range testCases {
+		t.Run(name, func(t *testing.T) {
+			b := bytes.NewBufferString("")
+			err := WriteONNX(b, net)
+			require.NoError(t, err)
+
+			model := decodeTestONNXModel(t, b.Bytes())
+			assert.Equal(t, []string{ONNXInputName}, model.inputs)
+			assert.Equal(t, []string{ONNXOutputName}, model.outputs)
+
+			depth, err := net.MaxActivationDepth()
+			require.NoError(t, err)
+			for _, n := range model.nodes {
+				if n.opType == "Concat" && n.outputs[0] == fmt.Sprintf("layer_%d", depth+1) {
+					t.Errorf("the number of layers exceeds network depth: %d", depth)
+				}
+			}
+
+			values := make([]float64, 0)
+			for _, in := range inputs {
+				values = append(values, in...)
+			}
+			outputs := model.run(t, &onnxTestTensor{shape: []int{len(inputs), 2}, data: values})
+			require.Equal(t, []int{len(inputs), len(net.Outputs)}, outputs.shape)
+
+			for i, in := range inputs {
+				_, err = net.Flush()
+				require.NoError(t, err)
+				err = net.LoadSensors(in)
+				require.NoError(t, err)
+				_, err = net.ForwardSteps(depth)
+				require.NoError(t, err)
+				expected := net.ReadOutputs()
+				for j, v := range expected {
+					assert.InDelta(t, v, outputs.data[i*len(expected)+j], 1e-4*math.Max(1, math.Abs(v)),
+						"wrong output: %d for sample: %d", j, i)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteONNX_unreachableOutput(t *testing.T) {
+	allNodes := []*network.NNode{
+		network.NewNNode(1, network.InputNeuron),
+		network.NewNNode(2, network.InputNeuron),
+		network.NewNNode(3, network.OutputNeuron),
+		network.NewNNode(4, network.OutputNeuron),
+	}
+	// OUTPUT 3 <- INPUT 1, OUTPUT 4 is unreachable
+	allNodes[2].ConnectFrom(allNodes[0], 1.0)
+	net := network.NewNetwork(allNodes[0:2], allNodes[2:4], allNodes, 0)
+
+	b := bytes.NewBufferString("")
+	err := WriteONNX(b, net)
+	require.NoError(t, err)
+
+	model := decodeTestONNXModel(t, b.Bytes())
+	outputs := model.run(t, &onnxTestTensor{shape: []int{2, 2}, data: []float64{0.5, 1.0, -1.0, 2.0}})
+	assert.Equal(t, []int{2, 2}, outputs.shape)
+	assert.Equal(t, []float64{0, 0}, []float64{outputs.data[1], outputs.data[3]}, "unreachable output must be zero")
+}
+
+func TestWriteONNX_errors(t *testing.T) {
+	err := WriteONNX(bytes.NewBufferString(""), buildModularNetwork())
+	assert.EqualError(t, err, "networks with control nodes are not supported by ONNX export")
+
+	net := buildNetwork()
+	net.AllNodes()[5].ConnectFrom(net.Outputs[0], 0.5) // HIDDEN 6 <- OUTPUT 7
+	err = WriteONNX(bytes.NewBufferString(""), net)
+	assert.ErrorIs(t, err, network.ErrNetNotFeedForward)
+
+	net = buildNetwork()
+	net.Outputs[0].ActivationType = neatmath.MultiplyModuleActivation
+	err = WriteONNX(bytes.NewBufferString(""), net)
+	assert.EqualError(t, err, "failed to export node: 7: activation function is not supported by ONNX export: MultiplyModuleActivation")
+
+	net = buildNetwork()
+	net.Outputs[0].AggregationType = neatmath.ProductAggregation
+	err = WriteONNX(bytes.NewBufferString(""), net)
+	assert.EqualError(t, err, "aggregation function other than sum of node: 7 is not supported by ONNX export")
+
+	errWriter := ErrorWriter(1)
+	err = WriteONNX(&errWriter, buildNetwork())
+	assert.EqualError(t, err, alwaysErrorText)
+}
+
+// buildActivationsNetwork builds the network with hidden neuron for every supported activation function connected
+// to its own output neuron
+func buildActivationsNetwork() *network.Network {
+	activations := []neatmath.NodeActivationType{
+		neatmath.SigmoidPlainActivation, neatmath.SigmoidReducedActivation, neatmath.SigmoidSteepenedActivation,
+		neatmath.SigmoidBipolarActivation, neatmath.SigmoidApproximationActivation,
+		neatmath.SigmoidSteepenedApproximationActivation, neatmath.SigmoidInverseAbsoluteActivation,
+		neatmath.SigmoidLeftShiftedActivation, neatmath.SigmoidLeftShiftedSteepenedActivation,
+		neatmath.SigmoidRightShiftedSteepenedActivation, neatmath.TanhActivation, neatmath.GaussianBipolarActivation,
+		neatmath.GaussianActivation, neatmath.LinearActivation, neatmath.LinearAbsActivation,
+		neatmath.LinearClippedActivation, neatmath.NullActivation, neatmath.SignActivation, neatmath.SineActivation,
+		neatmath.StepActivation,
+	}
+	in1, in2 := network.NewNNode(1, network.InputNeuron), network.NewNNode(2, network.InputNeuron)
+	bias := network.NewNNode(3, network.BiasNeuron)
+	allNodes := []*network.NNode{in1, in2, bias}
+	outputs := make([]*network.NNode, len(activations))
+	for i, aType := range activations {
+		hidden := network.NewNNode(10+i, network.HiddenNeuron)
+		hidden.ActivationType = aType
+		hidden.ConnectFrom(in1, 1.5)
+		hidden.ConnectFrom(in2, -0.7)
+		hidden.ConnectFrom(bias, 0.2)
+		outputs[i] = network.NewNNode(100+i, network.OutputNeuron)
+		outputs[i].ActivationType = neatmath.LinearActivation
+		outputs[i].ConnectFrom(hidden, 1.0)
+		allNodes = append(allNodes, hidden)
+	}
+	allNodes = append(allNodes, outputs...)
+	return network.NewNetwork(allNodes[0:3], outputs, allNodes, 0)
+}
+
+// buildUnreachableNetwork builds the network with hidden neurons not reachable from sensors
+func buildUnreachableNetwork() *network.Network {
+	allNodes := []*network.NNode{
+		network.NewNNode(1, network.InputNeuron),
+		network.NewNNode(2, network.InputNeuron),
+		network.NewNNode(3, network.BiasNeuron),
+		network.NewNNode(4, network.HiddenNeuron),
+		network.NewNNode(5, network.HiddenNeuron),
+		network.NewNNode(6, network.OutputNeuron),
+		network.NewNNode(7, network.OutputNeuron),
+	}
+	// HIDDEN 5 <- HIDDEN 4 (unreachable)
+	allNodes[4].ConnectFrom(allNodes[3], 2.0)
+	// OUTPUT 6 <- INPUT 1, INPUT 2, HIDDEN 5
+	allNodes[5].ConnectFrom(allNodes[0], 1.0)
+	allNodes[5].ConnectFrom(allNodes[1], -1.0)
+	allNodes[5].ConnectFrom(allNodes[4], 3.0)
+	// OUTPUT 7 <- INPUT 2
+	allNodes[6].ConnectFrom(allNodes[1], 0.5)
+
+	return network.NewNetwork(allNodes[0:3], allNodes[5:7], allNodes, 0)
+}
+
+// The minimal ONNX model decoder and interpreter of the operators used by ONNX export
+
+type onnxTestTensor struct {
+	shape []int
+	data  []float64
+}
+
+type onnxTestNode struct {
+	opType     string
+	inputs     []string
+	outputs    []string
+	attributes map[string]interface{}
+}
+
+type onnxTestModel struct {
+	nodes        []onnxTestNode
+	initializers map[string]*onnxTestTensor
+	inputs       []string
+	outputs      []string
+}
+
+type protoTestValue struct {
+	varint uint64
+	bytes  []byte
+}
+
+func decodeTestProto(t *testing.T, b []byte) map[int][]protoTestValue {
+	fields := make(map[int][]protoTestValue)
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		require.True(t, n > 0, "invalid field key")
+		b = b[n:]
+		field, wireType := int(key>>3), int(key&7)
+		var value protoTestValue
+		switch wireType {
+		case protoWireVarint:
+			value.varint, n = binary.Uvarint(b)
+			require.True(t, n > 0, "invalid varint")
+			b = b[n:]
+		case protoWireFixed32:
+			value.bytes = b[:4]
+			b = b[4:]
+		case protoWireBytes:
+			length, n := binary.Uvarint(b)
+			require.True(t, n > 0, "invalid length")
+			value.bytes = b[n : n+int(length)]
+			b = b[n+int(length):]
+		default:
+			require.Fail(t, "unsupported wire type", "%d", wireType)
+		}
+		fields[field] = append(fields[field], value)
+	}
+	return fields
+}
+
+func decodeTestVarints(t *testing.T, b []byte) []int64 {
+	values := make([]int64, 0)
+	for len(b) > 0 {
+		v, n := binary.Uvarint(b)
+		require.True(t, n > 0, "invalid varint")
+		values = append(values, int64(v))
+		b = b[n:]
+	}
+	return values
+}
+
+func decodeTestTensor(t *testing.T, b []byte) (string, *onnxTestTensor) {
+	fields := decodeTestProto(t, b)
+	tensor := &onnxTestTensor{shape: make([]int, 0)}
+	for _, dims := range fields[1] {
+		for _, d := range decodeTestVarints(t, dims.bytes) {
+			tensor.shape = append(tensor.shape, int(d))
+		}
+	}
+	switch fields[2][0].varint {
+	case onnxFloat:
+		for _, v := range fields[4] {
+			for i := 0; i < len(v.bytes); i += 4 {
+				tensor.data = append(tensor.data, float64(math.Float32frombits(binary.LittleEndian.Uint32(v.bytes[i:]))))
+			}
+		}
+	case onnxInt64:
+		for _, v := range fields[7] {
+			for _, d := range decodeTestVarints(t, v.bytes) {
+				tensor.data = append(tensor.data, float64(d))
+			}
+		}
+	default:
+		require.Fail(t, "unsupported tensor data type")
+	}
+	name := ""
+	if len(fields[8]) > 0 {
+		name = string(fields[8][0].bytes)
+	}
+	return name, tensor
+}
+
+func decodeTestONNXModel(t *testing.T, b []byte) *onnxTestModel {
+	fields := decodeTestProto(t, b)
+	require.Equal(t, uint64(onnxIRVersion), fields[1][0].varint)
+	assert.Equal(t, "goNEAT", string(fields[2][0].bytes))
+	opset := decodeTestProto(t, fields[8][0].bytes)
+	require.Equal(t, uint64(onnxOpsetVersion), opset[2][0].varint)
+
+	graph := decodeTestProto(t, fields[7][0].bytes)
+	model := &onnxTestModel{initializers: make(map[string]*onnxTestTensor)}
+	for _, v := range graph[1] {
+		nodeFields := decodeTestProto(t, v.bytes)
+		node := onnxTestNode{opType: string(nodeFields[4][0].bytes), attributes: make(map[string]interface{})}
+		for _, in := range nodeFields[1] {
+			node.inputs = append(node.inputs, string(in.bytes))
+		}
+		for _, out := range nodeFields[2] {
+			node.outputs = append(node.outputs, string(out.bytes))
+		}
+		for _, a := range nodeFields[5] {
+			attrFields := decodeTestProto(t, a.bytes)
+			name := string(attrFields[1][0].bytes)
+			switch attrFields[20][0].varint {
+			case onnxAttributeInt:
+				node.attributes[name] = int64(attrFields[3][0].varint)
+			case onnxAttributeTensor:
+				_, node.attributes[name] = decodeTestTensor(t, attrFields[5][0].bytes)
+			default:
+				require.Fail(t, "unsupported attribute type")
+			}
+		}
+		model.nodes = append(model.nodes, node)
+	}
+	for _, v := range graph[5] {
+		name, tensor := decodeTestTensor(t, v.bytes)
+		model.initializers[name] = tensor
+	}
+	for _, v := range graph[11] {
+		model.inputs = append(model.inputs, string(decodeTestProto(t, v.bytes)[1][0].bytes))
+	}
+	for _, v := range graph[12] {
+		model.outputs = append(model.outputs, string(decodeTestProto(t, v.bytes)[1][0].bytes))
+	}
+	return model
+}
+
+func (m *onnxTestModel) run(t *testing.T, input *onnxTestTensor) *onnxTestTensor {
+	values := map[string]*onnxTestTensor{m.inputs[0]: input}
+	for name, v := range m.initializers {
+		values[name] = v
+	}
+	for _, node := range m.nodes {
+		in := make([]*onnxTestTensor, len(node.inputs))
+		for i, name := range node.inputs {
+			require.Contains(t, values, name, "value not found: %s", name)
+			in[i] = values[name]
+		}
+		values[node.outputs[0]] = runTestOperator(t, node, in)
+	}
+	return values[m.outputs[0]]
+}
+
+func runTestOperator(t *testing.T, node onnxTestNode, in []*onnxTestTensor) *onnxTestTensor {
+	unary := func(f func(float64) float64) *onnxTestTensor {
+		out := &onnxTestTensor{shape: in[0].shape, data: make([]float64, len(in[0].data))}
+		for i, v := range in[0].data {
+			out.data[i] = float64(float32(f(v)))
+		}
+		return out
+	}
+	// broadcast scalars to the shape of the largest tensor
+	elementwise := func(f func(args ...float64) float64) *onnxTestTensor {
+		out := &onnxTestTensor{}
+		for _, tensor := range in {
+			if len(tensor.data) > len(out.data) {
+				out.shape, out.data = tensor.shape, make([]float64, len(tensor.data))
+			}
+		}
+		args := make([]float64, len(in))
+		for i := range out.data {
+			for j, tensor := range in {
+				if len(tensor.data) == 1 {
+					args[j] = tensor.data[0]
+				} else {
+					require.Equal(t, len(out.data), len(tensor.data), "unsupported broadcasting")
+					args[j] = tensor.data[i]
+				}
+			}
+			out.data[i] = float64(float32(f(args...)))
+		}
+		return out
+	}
+
+	switch node.opType {
+	case "Shape":
+		out := &onnxTestTensor{shape: []int{len(in[0].shape)}}
+		for _, d := range in[0].shape {
+			out.data = append(out.data, float64(d))
+		}
+		return out
+	case "ConstantOfShape":
+		out := &onnxTestTensor{}
+		size := 1
+		for _, d := range in[0].data {
+			out.shape = append(out.shape, int(d))
+			size *= int(d)
+		}
+		value := node.attributes["value"].(*onnxTestTensor).data[0]
+		for i := 0; i < size; i++ {
+			out.data = append(out.data, value)
+		}
+		return out
+	case "Gather":
+		axis, _ := node.attributes["axis"].(int64)
+		indices := in[1].data
+		if axis == 0 {
+			require.Len(t, in[0].shape, 1)
+			out := &onnxTestTensor{shape: []int{len(indices)}}
+			for _, idx := range indices {
+				out.data = append(out.data, in[0].data[int(idx)])
+			}
+			return out
+		}
+		rows, cols := in[0].shape[0], in[0].shape[1]
+		out := &onnxTestTensor{shape: []int{rows, len(indices)}}
+		for r := 0; r < rows; r++ {
+			for _, idx := range indices {
+				require.True(t, int(idx) < cols, "index out of range")
+				out.data = append(out.data, in[0].data[r*cols+int(idx)])
+			}
+		}
+		return out
+	case "Concat":
+		if node.attributes["axis"].(int64) == 0 {
+			out := &onnxTestTensor{}
+			for _, tensor := range in {
+				out.data = append(out.data, tensor.data...)
+			}
+			out.shape = []int{len(out.data)}
+			return out
+		}
+		rows, cols := in[0].shape[0], 0
+		for _, tensor := range in {
+			require.Equal(t, rows, tensor.shape[0])
+			cols += tensor.shape[1]
+		}
+		out := &onnxTestTensor{shape: []int{rows, cols}}
+		for r := 0; r < rows; r++ {
+			for _, tensor := range in {
+				c := tensor.shape[1]
+				out.data = append(out.data, tensor.data[r*c:(r+1)*c]...)
+			}
+		}
+		return out
+	case "MatMul":
+		rows, inner, cols := in[0].shape[0], in[0].shape[1], in[1].shape[1]
+		require.Equal(t, inner, in[1].shape[0])
+		out := &onnxTestTensor{shape: []int{rows, cols}, data: make([]float64, rows*cols)}
+		for r := 0; r < rows; r++ {
+			for c := 0; c < cols; c++ {
+				sum := float32(0)
+				for k := 0; k < inner; k++ {
+					sum += float32(in[0].data[r*inner+k]) * float32(in[1].data[k*cols+c])
+				}
+				out.data[r*cols+c] = float64(sum)
+			}
+		}
+		return out
+	case "Add":
+		return elementwise(func(a ...float64) float64 { return a[0] + a[1] })
+	case "Sub":
+		return elementwise(func(a ...float64) float64 { return a[0] - a[1] })
+	case "Mul":
+		return elementwise(func(a ...float64) float64 { return a[0] * a[1] })
+	case "Less":
+		return elementwise(func(a ...float64) float64 {
+			if a[0] < a[1] {
+				return 1
+			}
+			return 0
+		})
+	case "Where":
+		return elementwise(func(a ...float64) float64 {
+			if a[0] != 0 {
+				return a[1]
+			}
+			return a[2]
+		})
+	case "Clip":
+		return elementwise(func(a ...float64) float64 { return math.Min(math.Max(a[0], a[1]), a[2]) })
+	case "Sigmoid":
+		return unary(func(x float64) float64 { return 1 / (1 + math.Exp(-x)) })
+	case "Tanh":
+		return unary(math.Tanh)
+	case "Exp":
+		return unary(math.Exp)
+	case "Neg":
+		return unary(func(x float64) float64 { return -x })
+	case "Abs":
+		return unary(math.Abs)
+	case "Sin":
+		return unary(math.Sin)
+	case "Softsign":
+		return unary(func(x float64) float64 { return x / (1 + math.Abs(x)) })
+	case "Sign":
+		return unary(func(x float64) float64 {
+			if x > 0 {
+				return 1
+			} else if x < 0 {
+				return -1
+			}
+			return 0
+		})
+	default:
+		require.Fail(t, "unsupported operator", node.opType)
+	}
+	return nil
+}
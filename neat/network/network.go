@@ -31,6 +31,9 @@ type Network struct {
 
 	// allNodesMIMO a list of all nodes in the network including MIMO control ones
 	allNodesMIMO []*NNode
+
+	// The trace to record activation steps if tracing is enabled
+	trace *ActivationTrace
 }
 
 // NewNetwork Creates new network
@@ -54,6 +57,76 @@ func NewModularNetwork(in, out, all, control []*NNode, netId int) *Network {
 	return n
 }
 
+// Clone returns a copy of this network with the same topology, connection weights, and activation functions, but with
+// activation state reset. As far as Network keeps activation state within its nodes, the nodes and links are copied,
+// while traits and parameters are shared with this network. The returned solver can be used concurrently with this network.
+func (n *Network) Clone() Solver {
+	nodes := make(map[*NNode]*NNode, len(n.allNodes)+len(n.controlNodes))
+	copyNode := func(node *NNode) *NNode {
+		if c, ok := nodes[node]; ok {
+			return c
+		}
+		c := NewNNodeCopy(node, node.Trait)
+		c.Params = node.Params
+		nodes[node] = c
+		return c
+	}
+	copyLink := func(l *Link) *Link {
+		link := *l
+		link.InNode = copyNode(l.InNode)
+		link.OutNode = copyNode(l.OutNode)
+		return &link
+	}
+	copyNodes := func(list []*NNode) []*NNode {
+		res := make([]*NNode, len(list))
+		for i, node := range list {
+			res[i] = copyNode(node)
+		}
+		return res
+	}
+
+	allNodes := copyNodes(n.allNodes)
+	for _, node := range n.allNodes {
+		for _, l := range node.Incoming {
+			link := copyLink(l)
+			link.OutNode.Incoming = append(link.OutNode.Incoming, link)
+			link.InNode.Outgoing = append(link.InNode.Outgoing, link)
+		}
+	}
+	inputs, outputs := copyNodes(n.inputs), copyNodes(n.Outputs)
+
+	var clone *Network
+	if len(n.controlNodes) == 0 {
+		clone = NewNetwork(inputs, outputs, allNodes, n.Id)
+	} else {
+		controlNodes := copyNodes(n.controlNodes)
+		for i, cn := range n.controlNodes {
+			// only incoming to and outgoing from the control node
+			for _, l := range cn.Incoming {
+				controlNodes[i].Incoming = append(controlNodes[i].Incoming, copyLink(l))
+			}
+			for _, l := range cn.Outgoing {
+				controlNodes[i].Outgoing = append(controlNodes[i].Outgoing, copyLink(l))
+			}
+		}
+		clone = NewModularNetwork(inputs, outputs, allNodes, controlNodes, n.Id)
+	}
+	clone.Name = n.Name
+	return clone
+}
+
+// SortedNetworkSolver creates the solver which evaluates each node of this network exactly once per activation in
+// topological order. If this network is not acyclic, the FastModularNetworkSolver is returned instead.
+func (n *Network) SortedNetworkSolver() (Solver, error) {
+	solver, err := NewSortedNetworkSolver(n)
+	if errors.Is(err, ErrNetNotFeedForward) {
+		return n.FastNetworkSolver()
+	} else if err != nil {
+		return nil, err
+	}
+	return solver, nil
+}
+
 // FastNetworkSolver Creates fast network solver based on the architecture of this network. It's primarily aimed for
 // big networks to improve processing speed.
 func (n *Network) FastNetworkSolver() (Solver, error) {
@@ -80,15 +153,17 @@ func (n *Network) FastNetworkSolver() (Solver, error) {
 	inputNeuronCount := len(inList)
 	totalNeuronCount := len(n.allNodes)
 
-	// create activation functions array
+	// create activation functions, activation parameters, and aggregation functions arrays
 	activations := make([]math.NodeActivationType, totalNeuronCount)
+	params := make([][]float64, totalNeuronCount)
+	aggregations := make([]math.NodeAggregationType, totalNeuronCount)
 	neuronLookup := make(map[int]int) // id:index
 
 	// walk through neuron nodes in order: bias, input, output, hidden
-	neuronIndex := processList(0, biasList, activations, neuronLookup)
-	neuronIndex = processList(neuronIndex, inList, activations, neuronLookup)
-	neuronIndex = processList(neuronIndex, n.Outputs, activations, neuronLookup)
-	processList(neuronIndex, hiddenList, activations, neuronLookup)
+	neuronIndex := processList(0, biasList, activations, params, aggregations, neuronLookup)
+	neuronIndex = processList(neuronIndex, inList, activations, params, aggregations, neuronLookup)
+	neuronIndex = processList(neuronIndex, n.Outputs, activations, params, aggregations, neuronLookup)
+	processList(neuronIndex, hiddenList, activations, params, aggregations, neuronLookup)
 
 	// walk through neurons in order: input, output, hidden and create bias and connections lists
 	biases := make([]float64, totalNeuronCount)
@@ -141,12 +216,50 @@ func (n *Network) FastNetworkSolver() (Solver, error) {
 		activations, connections, biases, modules)
 	solver.Id = n.Id
 	solver.Name = n.Name
+	solver.neuronIds = make([]int, totalNeuronCount)
+	for id, index := range neuronLookup {
+		solver.neuronIds[index] = id
+	}
+	for _, p := range params {
+		if len(p) > 0 {
+			// store activation parameters only if any neuron has them
+			solver.neuronParams = params
+			break
+		}
+	}
+	for _, a := range aggregations {
+		if a != math.SumAggregation {
+			// store aggregation functions only if any neuron aggregates inputs other than by sum
+			solver.aggregationFunctions = aggregations
+			break
+		}
+	}
 	return solver, nil
 }
 
-func processList(startIndex int, nList []*NNode, activations []math.NodeActivationType, neuronLookup map[int]int) int {
+// FastNetworkSolver32 Creates single precision fast network solver based on the architecture of this network. It has
+// the same structure as the solver created by FastNetworkSolver, but stores signals and weights as float32 values to
+// halve the memory bandwidth.
+func (n *Network) FastNetworkSolver32() (Solver, error) {
+	solver, err := n.FastNetworkSolver()
+	if err != nil {
+		return nil, err
+	}
+	solver32, err := NewFastModularNetworkSolver32(solver.(*FastModularNetworkSolver).Structure())
+	if err != nil {
+		return nil, err
+	}
+	solver32.Id = n.Id
+	solver32.Name = n.Name
+	return solver32, nil
+}
+
+func processList(startIndex int, nList []*NNode, activations []math.NodeActivationType, params [][]float64,
+	aggregations []math.NodeAggregationType, neuronLookup map[int]int) int {
 	for _, ne := range nList {
 		activations[startIndex] = ne.ActivationType
+		params[startIndex] = ne.Params
+		aggregations[startIndex] = ne.AggregationType
 		neuronLookup[ne.Id] = startIndex
 		startIndex += 1
 	}
@@ -159,8 +272,9 @@ func (n *Network) processIncomingConnections(nList []*NNode, biases []float64, n
 		if targetIndex, ok := neuronLookup[ne.Id]; ok {
 			for _, in := range ne.Incoming {
 				if sourceIndex, ok := neuronLookup[in.InNode.Id]; ok {
-					if in.InNode.NeuronType == BiasNeuron {
-						// store bias for target neuron
+					if in.InNode.NeuronType == BiasNeuron && ne.AggregationType == math.SumAggregation {
+						// store bias for target neuron, the bias of neuron with aggregation function other than
+						// sum is kept as connection to be aggregated with other inputs
 						biases[targetIndex] += in.ConnectionWeight
 					} else {
 						// save connection
@@ -207,6 +321,52 @@ func (n *Network) Flush() (res bool, err error) {
 	return res, err
 }
 
+// Reset resets activation state of all nodes including control nodes, and clears activation sums. The activation
+// trace, if set, is not affected.
+func (n *Network) Reset() error {
+	for _, node := range n.allNodesMIMO {
+		node.Flushback()
+		node.ActivationSum = 0
+	}
+	return nil
+}
+
+// State returns the snapshot of activation state of all nodes followed by control nodes.
+func (n *Network) State() *SolverState {
+	state := &SolverState{
+		Solver: NetworkSolverName,
+		Nodes:  make([]NodeState, len(n.allNodesMIMO)),
+	}
+	for i, node := range n.allNodesMIMO {
+		state.Nodes[i] = node.state()
+	}
+	return state
+}
+
+// SetState restores activation state of all nodes and control nodes. The state must have the states of the nodes
+// with the same IDs in the same order as nodes of this network.
+func (n *Network) SetState(state *SolverState) error {
+	if state == nil {
+		return ErrSolverStateMismatch
+	}
+	if state.Solver != NetworkSolverName {
+		return fmt.Errorf("%w: expected state of: %s, found: %s", ErrSolverStateMismatch, NetworkSolverName, state.Solver)
+	}
+	if len(state.Nodes) != len(n.allNodesMIMO) {
+		return fmt.Errorf("%w: expected nodes: %d, found: %d", ErrSolverStateMismatch, len(n.allNodesMIMO), len(state.Nodes))
+	}
+	for i, node := range n.allNodesMIMO {
+		if state.Nodes[i].Id != node.Id {
+			return fmt.Errorf("%w: expected node ID: %d at: %d, found: %d", ErrSolverStateMismatch, node.Id, i,
+				state.Nodes[i].Id)
+		}
+	}
+	for i, node := range n.allNodesMIMO {
+		node.setState(state.Nodes[i])
+	}
+	return nil
+}
+
 // PrintActivation Prints the values of network outputs to the console
 func (n *Network) PrintActivation() string {
 	out := bytes.NewBufferString(fmt.Sprintf("Network %s with id %d outputs: (", n.Name, n.Id))
@@ -260,11 +420,23 @@ func (n *Network) ActivateSteps(maxSteps int) (bool, error) {
 			return false, ErrNetExceededMaxActivationAttempts
 		}
 
-		// For each neuron node, compute the sum of its incoming activation
+		// The signals relayed by links if they are traced
+		var signals []float64
+		if n.trace != nil && n.trace.recordLinks {
+			signals = make([]float64, 0, len(n.trace.Links))
+		}
+
+		// For each neuron node, compute the aggregation of its incoming activation
 		for _, np := range n.allNodes {
 			if np.IsNeuron() {
 				np.ActivationSum = 0.0 // reset activation value
 
+				// The incoming activations to be aggregated by the function other than sum
+				var inputs []float64
+				if np.AggregationType != math.SumAggregation {
+					inputs = make([]float64, 0, len(np.Incoming))
+				}
+
 				// For each node's incoming connection, add the activity from the connection to the activesum
 				for _, link := range np.Incoming {
 					// Handle possible time delays
@@ -276,8 +448,23 @@ func (n *Network) ActivateSteps(maxSteps int) (bool, error) {
 					} else {
 						addAmount = link.ConnectionWeight * link.InNode.GetActiveOutTd()
 					}
-					np.ActivationSum += addAmount
+					if inputs != nil {
+						inputs = append(inputs, addAmount)
+					} else {
+						np.ActivationSum += addAmount
+					}
+					if signals != nil {
+						signals = append(signals, addAmount)
+					}
 				} // End {for} over incoming links
+
+				if inputs != nil {
+					sum, err := math.Aggregate(np.AggregationType, inputs)
+					if err != nil {
+						return false, err
+					}
+					np.ActivationSum = sum
+				}
 			} // End if != SENSOR
 		} // End {for} over all nodes
 
@@ -307,6 +494,10 @@ func (n *Network) ActivateSteps(maxSteps int) (bool, error) {
 			cn.isActive = true
 		}
 
+		if err := n.recordActivationStep(signals); err != nil {
+			return false, err
+		}
+
 		oneTime = true
 		abortCount += 1
 	}
@@ -480,6 +671,11 @@ func (n *Network) ControlNodes() []*NNode {
 	return n.controlNodes
 }
 
+// InputNodes returns the sensor nodes of this network including bias nodes in order of sensors loading
+func (n *Network) InputNodes() []*NNode {
+	return n.inputs
+}
+
 // BaseNodes returns all nodes in this network excluding MIMO control nodes
 func (n *Network) BaseNodes() []*NNode {
 	return n.allNodes
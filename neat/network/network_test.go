@@ -371,3 +371,10 @@ func TestModularNetwork_BaseNodes(t *testing.T) {
 	assert.NotNil(t, baseNodes)
 	assert.Len(t, baseNodes, len(net.allNodes))
 }
+
+func TestNetwork_InputNodes(t *testing.T) {
+	net := buildNetwork()
+
+	inputs := net.InputNodes()
+	assert.Equal(t, []int{1, 2, 3}, nodeIds(inputs))
+}
@@ -18,6 +18,8 @@ type NNode struct {
 
 	// The type of node activation function (SIGMOID, ...)
 	ActivationType math.NodeActivationType
+	// The type of function to aggregate weighted input signals of the node (SUM, PRODUCT, ...)
+	AggregationType math.NodeAggregationType
 	// The neuron type for this node (HIDDEN, INPUT, OUTPUT, BIAS)
 	NeuronType NodeNeuronType
 
@@ -44,6 +46,8 @@ type NNode struct {
 	/* ************ LEARNING PARAMETERS *********** */
 	// The following parameters are for use in neurons that learn through habituation,
 	// sensitization, or Hebbian-type processes  */
+	// The parameters are derived from the node's trait and passed as auxiliary parameters to the activation function,
+	// which allows parametric activation functions to evolve along with traits.
 	Params []float64
 
 	// Activation value of node at time t-1; Holds the previous step's activation for recurrency
@@ -71,7 +75,9 @@ func NewNNodeCopy(n *NNode, t *neat.Trait) *NNode {
 	node.Id = n.Id
 	node.NeuronType = n.NeuronType
 	node.ActivationType = n.ActivationType
+	node.AggregationType = n.AggregationType
 	node.Trait = t
+	node.deriveTrait(t)
 	return node
 }
 
@@ -98,6 +104,14 @@ func NewNetworkNode() *NNode {
 	}
 }
 
+// Copies trait parameters into this node's parameters to be used by parametric activation functions
+func (n *NNode) deriveTrait(t *neat.Trait) {
+	if t != nil {
+		n.Params = make([]float64, len(t.Params))
+		copy(n.Params, t.Params)
+	}
+}
+
 // Set new activation value to this node
 func (n *NNode) setActivation(input float64) {
 	// Keep a memory of activations for potential time delayed connections
@@ -314,6 +328,8 @@ func (n *NNode) PrintDebug() string {
 	_, _ = fmt.Fprintf(b, "\tActivation: %f\n", n.Activation)
 	activation, _ := math.NodeActivators.ActivationNameFromType(n.ActivationType)
 	_, _ = fmt.Fprintf(b, "\tActivation Type: %s\n", activation)
+	aggregation, _ := math.AggregationNameFromType(n.AggregationType)
+	_, _ = fmt.Fprintf(b, "\tAggregation Type: %s\n", aggregation)
 	_, _ = fmt.Fprintf(b, "\tNeuronType: %d\n", n.NeuronType)
 	_, _ = fmt.Fprintf(b, "\tActivationsCount: %d\n", n.ActivationsCount)
 	_, _ = fmt.Fprintf(b, "\tActivationSum: %f\n", n.ActivationSum)
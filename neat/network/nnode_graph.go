@@ -26,6 +26,14 @@ func (n *NNode) Attributes() []encoding.Attribute {
 			Value: activationFunc,
 		})
 	}
+	if n.AggregationType != math.SumAggregation {
+		if aggregationFunc, err := math.AggregationNameFromType(n.AggregationType); err == nil {
+			attrs = append(attrs, encoding.Attribute{
+				Key:   "aggregation_type",
+				Value: aggregationFunc,
+			})
+		}
+	}
 	if len(n.Params) > 0 {
 		attrs = append(attrs, encoding.Attribute{
 			Key:   "parameters",
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/math"
 	"testing"
 )
 
@@ -24,6 +25,13 @@ func TestNNode_Attributes(t *testing.T) {
 	require.Len(t, attrs, 3, "wrong attributes length")
 	assert.Equal(t, "parameters", attrs[2].Key)
 	assert.Equal(t, fmt.Sprintf("%v", params), attrs[2].Value)
+
+	node.AggregationType = math.MaxAbsAggregation
+	attrs = node.Attributes()
+	require.Len(t, attrs, 4, "wrong attributes length")
+	assert.Equal(t, "aggregation_type", attrs[2].Key)
+	assert.Equal(t, "MaxAbsAggregation", attrs[2].Value)
+	assert.Equal(t, "parameters", attrs[3].Key)
 }
 
 func TestNNode_ID(t *testing.T) {
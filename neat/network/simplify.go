@@ -0,0 +1,332 @@
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/topo"
+	"gonum.org/v1/gonum/graph/traverse"
+)
+
+// SimplifyReport describes the structure removed from the network by simplification.
+type SimplifyReport struct {
+	// The hidden nodes removed because they have no path to the outputs or can not be activated from the sensors
+	RemovedNodes []*NNode
+	// The control nodes removed because they have no path to the outputs
+	RemovedControlNodes []*NNode
+	// The links removed because they connect removed nodes
+	RemovedLinks []*Link
+	// The links removed after merging their weights into parallel links connecting the same nodes
+	MergedLinks []*Link
+	// The hidden nodes with linear activation function removed after folding into links connecting their neighbours
+	FoldedNodes []*NNode
+	// The sensors which can not influence the outputs. They are not removed to keep the network inputs intact.
+	UnusedInputs []*NNode
+}
+
+// IsEmpty returns true if nothing was changed by simplification.
+func (r *SimplifyReport) IsEmpty() bool {
+	return len(r.RemovedNodes) == 0 && len(r.RemovedControlNodes) == 0 && len(r.RemovedLinks) == 0 &&
+		len(r.MergedLinks) == 0 && len(r.FoldedNodes) == 0
+}
+
+// Stringer
+func (r *SimplifyReport) String() string {
+	b := bytes.NewBufferString("Simplification report:\n")
+	_, _ = fmt.Fprintf(b, "\tremoved nodes: %v\n", nodeIds(r.RemovedNodes))
+	_, _ = fmt.Fprintf(b, "\tremoved control nodes: %v\n", nodeIds(r.RemovedControlNodes))
+	_, _ = fmt.Fprintf(b, "\tremoved links: %d\n", len(r.RemovedLinks))
+	_, _ = fmt.Fprintf(b, "\tmerged links: %d\n", len(r.MergedLinks))
+	_, _ = fmt.Fprintf(b, "\tfolded linear nodes: %v\n", nodeIds(r.FoldedNodes))
+	_, _ = fmt.Fprintf(b, "\tunused inputs: %v\n", nodeIds(r.UnusedInputs))
+	return b.String()
+}
+
+func nodeIds(nodes []*NNode) []int {
+	ids := make([]int, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.Id
+	}
+	return ids
+}
+
+// Simplify returns the simplified copy of this network which produces the same outputs as this network, and
+// the report describing removed structure. This network is not modified. The simplification includes:
+//   - removal of the hidden nodes, control nodes, and links that can not influence the outputs, i.e., have no path to
+//     the outputs or can not be activated from the sensors. The sensors are kept to preserve the network inputs;
+//   - merging of the parallel links connecting the same nodes into one link with summed weight. Only the links into
+//     nodes with SumAggregation are merged, because other aggregation functions depend on the individual inputs;
+//   - folding of the hidden nodes with LinearActivation and single incoming link into links connecting their
+//     neighbours. The folding is applied only to the acyclic networks without time delayed links, where the outputs
+//     after full activation do not depend on the length of activation paths. The node is not folded if it would
+//     require merging of links into the node with other than SumAggregation.
+//
+// The outputs of the simplified network are identical up to the floating point rounding errors caused by merging
+// of the weights.
+func (n *Network) Simplify() (*Network, *SimplifyReport) {
+	s := n.Clone().(*Network)
+	report := &SimplifyReport{}
+
+	s.pruneDeadStructure(report)
+	s.mergeParallelLinks(report)
+	if s.isAcyclic() {
+		s.foldLinearNodes(report)
+	}
+	s.numLinks = -1
+	return s, report
+}
+
+// reversedNetwork is the view of the network graph with all edges reversed
+type reversedNetwork struct {
+	*Network
+}
+
+func (r reversedNetwork) From(id int64) graph.Nodes {
+	return r.Network.To(id)
+}
+
+func (r reversedNetwork) Edge(uid, vid int64) graph.Edge {
+	return r.Network.Edge(vid, uid)
+}
+
+// moduleNodes returns the input and output nodes of the control nodes of this network
+func (n *Network) moduleNodes() map[*NNode]bool {
+	nodes := make(map[*NNode]bool)
+	for _, cn := range n.controlNodes {
+		for _, l := range cn.Incoming {
+			nodes[l.InNode] = true
+		}
+		for _, l := range cn.Outgoing {
+			nodes[l.OutNode] = true
+		}
+	}
+	return nodes
+}
+
+// pruneDeadStructure removes nodes and links that can not influence the network outputs
+func (n *Network) pruneDeadStructure(report *SimplifyReport) {
+	// find nodes that have path to the outputs
+	required := make(map[int64]bool)
+	backward := traverse.BreadthFirst{Visit: func(node graph.Node) {
+		required[node.ID()] = true
+	}}
+	for _, out := range n.Outputs {
+		backward.Walk(reversedNetwork{n}, out, nil)
+	}
+
+	// find nodes that can be activated by sensors or control nodes, which are always active
+	reachable := make(map[int64]bool)
+	forward := traverse.BreadthFirst{Visit: func(node graph.Node) {
+		reachable[node.ID()] = true
+	}}
+	for _, in := range n.inputs {
+		forward.Walk(n, in, nil)
+	}
+	for _, cn := range n.controlNodes {
+		forward.Walk(n, cn, nil)
+	}
+
+	// the IO nodes of the required control nodes must be kept to preserve modules arity
+	controlNodes := make([]*NNode, 0, len(n.controlNodes))
+	keep := make(map[*NNode]bool, len(n.allNodesMIMO))
+	for _, cn := range n.controlNodes {
+		if !required[cn.ID()] {
+			report.RemovedControlNodes = append(report.RemovedControlNodes, cn)
+			report.RemovedLinks = append(report.RemovedLinks, cn.Incoming...)
+			report.RemovedLinks = append(report.RemovedLinks, cn.Outgoing...)
+			continue
+		}
+		controlNodes = append(controlNodes, cn)
+		for _, l := range cn.Incoming {
+			keep[l.InNode] = true
+		}
+		for _, l := range cn.Outgoing {
+			keep[l.OutNode] = true
+		}
+	}
+	for _, node := range n.allNodes {
+		switch {
+		case node.IsSensor():
+			keep[node] = true
+			if !required[node.ID()] {
+				report.UnusedInputs = append(report.UnusedInputs, node)
+			}
+		case node.NeuronType == OutputNeuron:
+			keep[node] = true
+		case required[node.ID()] && reachable[node.ID()]:
+			keep[node] = true
+		}
+	}
+
+	allNodes := make([]*NNode, 0, len(n.allNodes))
+	for _, node := range n.allNodes {
+		if !keep[node] {
+			report.RemovedNodes = append(report.RemovedNodes, node)
+			report.RemovedLinks = append(report.RemovedLinks, node.Incoming...)
+			continue
+		}
+		allNodes = append(allNodes, node)
+		incoming := make([]*Link, 0, len(node.Incoming))
+		for _, l := range node.Incoming {
+			if keep[l.InNode] {
+				incoming = append(incoming, l)
+			} else {
+				report.RemovedLinks = append(report.RemovedLinks, l)
+			}
+		}
+		node.Incoming = incoming
+	}
+	for _, node := range allNodes {
+		outgoing := make([]*Link, 0, len(node.Outgoing))
+		for _, l := range node.Outgoing {
+			if keep[l.OutNode] {
+				outgoing = append(outgoing, l)
+			}
+		}
+		node.Outgoing = outgoing
+	}
+
+	n.setNodes(allNodes, controlNodes)
+}
+
+// mergeParallelLinks merges links connecting the same nodes into one link with summed weight. The links into the nodes
+// with other than sum aggregation are not merged.
+func (n *Network) mergeParallelLinks(report *SimplifyReport) {
+	type linkKey struct {
+		in          *NNode
+		timeDelayed bool
+	}
+	merged := make(map[*Link]bool)
+	for _, node := range n.allNodes {
+		if node.AggregationType != math.SumAggregation {
+			continue
+		}
+		links := make(map[linkKey]*Link, len(node.Incoming))
+		incoming := make([]*Link, 0, len(node.Incoming))
+		for _, l := range node.Incoming {
+			key := linkKey{in: l.InNode, timeDelayed: l.IsTimeDelayed}
+			if first, ok := links[key]; ok {
+				first.ConnectionWeight += l.ConnectionWeight
+				merged[l] = true
+				report.MergedLinks = append(report.MergedLinks, l)
+			} else {
+				links[key] = l
+				incoming = append(incoming, l)
+			}
+		}
+		node.Incoming = incoming
+	}
+	if len(merged) == 0 {
+		return
+	}
+	for _, node := range n.allNodes {
+		node.Outgoing = removeLinks(node.Outgoing, merged)
+	}
+}
+
+// isAcyclic checks whether this network has no cycles and time delayed links
+func (n *Network) isAcyclic() bool {
+	for _, node := range n.allNodes {
+		for _, l := range node.Incoming {
+			if l.IsTimeDelayed || l.InNode == node {
+				return false
+			}
+		}
+	}
+	_, err := topo.Sort(n)
+	return err == nil
+}
+
+// foldLinearNodes removes hidden nodes with linear activation function and single incoming link by connecting
+// the source of incoming link with targets of outgoing links directly.
+func (n *Network) foldLinearNodes(report *SimplifyReport) {
+	moduleNodes := n.moduleNodes()
+	canFold := func(node *NNode) bool {
+		if node.NeuronType != HiddenNeuron || node.ActivationType != math.LinearActivation ||
+			len(node.Incoming) != 1 || moduleNodes[node] {
+			return false
+		}
+		// the links into targets with other than sum aggregation can not be merged
+		source := node.Incoming[0].InNode
+		for _, out := range node.Outgoing {
+			if out.OutNode.AggregationType != math.SumAggregation && findLink(out.OutNode.Incoming, source) != nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	folded := make(map[*NNode]bool)
+	for _, node := range n.allNodes {
+		if !canFold(node) {
+			continue
+		}
+		in := node.Incoming[0]
+		source := in.InNode
+		for _, out := range node.Outgoing {
+			target := out.OutNode
+			weight := in.ConnectionWeight * out.ConnectionWeight
+			// replace the outgoing link of the folded node with link from the source or merge with existing one
+			if existing := findLink(target.Incoming, source); existing != nil {
+				existing.ConnectionWeight += weight
+				target.Incoming = removeLinks(target.Incoming, map[*Link]bool{out: true})
+			} else {
+				link := NewLinkCopy(out, source, target)
+				link.ConnectionWeight = weight
+				for i, l := range target.Incoming {
+					if l == out {
+						target.Incoming[i] = link
+					}
+				}
+				source.Outgoing = append(source.Outgoing, link)
+			}
+		}
+		source.Outgoing = removeLinks(source.Outgoing, map[*Link]bool{in: true})
+		node.Incoming, node.Outgoing = nil, nil
+		folded[node] = true
+		report.FoldedNodes = append(report.FoldedNodes, node)
+	}
+	if len(folded) == 0 {
+		return
+	}
+
+	allNodes := make([]*NNode, 0, len(n.allNodes)-len(folded))
+	for _, node := range n.allNodes {
+		if !folded[node] {
+			allNodes = append(allNodes, node)
+		}
+	}
+	n.setNodes(allNodes, n.controlNodes)
+}
+
+// setNodes is to set new lists of nodes of this network
+func (n *Network) setNodes(allNodes, controlNodes []*NNode) {
+	n.allNodes = allNodes
+	n.controlNodes = controlNodes
+	n.allNodesMIMO = allNodes
+	if len(controlNodes) > 0 {
+		n.allNodesMIMO = append(append(make([]*NNode, 0, len(allNodes)+len(controlNodes)), allNodes...), controlNodes...)
+	}
+}
+
+// findLink returns the first not time delayed link from the source node among provided links or nil if not found
+func findLink(links []*Link, source *NNode) *Link {
+	for _, l := range links {
+		if l.InNode == source && !l.IsTimeDelayed {
+			return l
+		}
+	}
+	return nil
+}
+
+// removeLinks returns the list of links without the links from the provided set
+func removeLinks(links []*Link, remove map[*Link]bool) []*Link {
+	res := make([]*Link, 0, len(links))
+	for _, l := range links {
+		if !remove[l] {
+			res = append(res, l)
+		}
+	}
+	return res
+}
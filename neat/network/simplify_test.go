@@ -0,0 +1,208 @@
+package network
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"testing"
+)
+
+func buildNetworkWithRedundancy() *Network {
+	allNodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, InputNeuron),
+		NewNNode(3, BiasNeuron),
+		NewNNode(4, HiddenNeuron),
+		NewNNode(5, HiddenNeuron),
+		NewNNode(6, HiddenNeuron),
+		NewNNode(7, HiddenNeuron),
+		NewNNode(8, HiddenNeuron),
+		NewNNode(10, OutputNeuron),
+		NewNNode(11, OutputNeuron),
+	}
+	// HIDDEN 4 with parallel links from INPUT 1
+	allNodes[3].ConnectFrom(allNodes[0], 1.5)
+	allNodes[3].ConnectFrom(allNodes[1], -0.5)
+	allNodes[3].ConnectFrom(allNodes[0], 0.7)
+	// HIDDEN 5 and HIDDEN 6 - the chain of linear nodes
+	allNodes[4].ActivationType = math.LinearActivation
+	allNodes[4].ConnectFrom(allNodes[3], 2.0)
+	allNodes[5].ActivationType = math.LinearActivation
+	allNodes[5].ConnectFrom(allNodes[4], 0.5)
+	// HIDDEN 7 - dead end
+	allNodes[6].ConnectFrom(allNodes[0], 1.0)
+	// HIDDEN 8 - can not be activated
+	// OUTPUT 10
+	allNodes[8].ConnectFrom(allNodes[5], 1.2)
+	allNodes[8].ConnectFrom(allNodes[2], 0.3)
+	// OUTPUT 11
+	allNodes[9].ConnectFrom(allNodes[7], 3.0)
+	allNodes[9].ConnectFrom(allNodes[1], 1.1)
+
+	return NewNetwork(allNodes[0:3], allNodes[8:10], allNodes, 0)
+}
+
+func activateForSimplifyTest(t *testing.T, net *Network, inputs []float64, steps int) []float64 {
+	_, err := net.Flush()
+	require.NoError(t, err)
+	require.NoError(t, net.LoadSensors(inputs))
+	_, err = net.ForwardSteps(steps)
+	require.NoError(t, err)
+	return net.ReadOutputs()
+}
+
+func TestNetwork_Simplify(t *testing.T) {
+	net := buildNetworkWithRedundancy()
+	nodeCount, linkCount := net.NodeCount(), net.LinkCount()
+
+	simplified, report := net.Simplify()
+	require.NotNil(t, simplified)
+	require.NotNil(t, report)
+	assert.False(t, report.IsEmpty())
+
+	assert.Equal(t, []int{7, 8}, nodeIds(report.RemovedNodes))
+	assert.Len(t, report.RemovedLinks, 2)
+	assert.Len(t, report.MergedLinks, 1)
+	assert.Equal(t, []int{5, 6}, nodeIds(report.FoldedNodes))
+	assert.Empty(t, report.UnusedInputs)
+	assert.Empty(t, report.RemovedControlNodes)
+
+	// check structure
+	assert.Equal(t, []int{1, 2, 3, 4, 10, 11}, nodeIds(simplified.AllNodes()))
+	assert.Equal(t, 6, simplified.NodeCount())
+	assert.Equal(t, 5, simplified.LinkCount())
+	out := simplified.Outputs[0]
+	require.Len(t, out.Incoming, 2)
+	assert.Equal(t, 4, out.Incoming[0].InNode.Id)
+	assert.InDelta(t, 2.0*0.5*1.2, out.Incoming[0].ConnectionWeight, 1e-12)
+	hidden := simplified.AllNodes()[3]
+	require.Len(t, hidden.Incoming, 2)
+	assert.InDelta(t, 2.2, hidden.Incoming[0].ConnectionWeight, 1e-12)
+	require.Len(t, hidden.Outgoing, 1)
+	assert.Same(t, out, hidden.Outgoing[0].OutNode)
+
+	// the original network is not modified
+	assert.Equal(t, nodeCount, net.NodeCount())
+	assert.Equal(t, linkCount, net.LinkCount())
+
+	// check outputs
+	depth, err := net.MaxActivationDepth()
+	require.NoError(t, err)
+	for _, in := range [][]float64{{0.5, 1.1}, {0.0, 0.0}, {-1.0, 2.0}, {1.0, 2.0}} {
+		expected := activateForSimplifyTest(t, net, in, depth)
+		actual := activateForSimplifyTest(t, simplified, in, depth)
+		assert.InDeltaSlice(t, expected, actual, 1e-12, "wrong outputs for: %v", in)
+	}
+}
+
+func TestNetwork_Simplify_unusedInput(t *testing.T) {
+	net := buildNetworkWithRedundancy()
+	// disconnect INPUT 2 from OUTPUT 11 and HIDDEN 4
+	output := net.Outputs[1]
+	output.Incoming = output.Incoming[:1]
+	hidden := net.allNodes[3]
+	hidden.Incoming = []*Link{hidden.Incoming[0], hidden.Incoming[2]}
+	net.inputs[1].Outgoing = nil
+
+	simplified, report := net.Simplify()
+	assert.Equal(t, []int{2}, nodeIds(report.UnusedInputs))
+	// the sensor is preserved
+	assert.Len(t, simplified.inputs, 3)
+	assert.Equal(t, 2, simplified.inputs[1].Id)
+}
+
+func TestNetwork_Simplify_recurrent(t *testing.T) {
+	net := buildNetworkWithRedundancy()
+	// make recurrent link from OUTPUT 10 to HIDDEN 5
+	net.allNodes[4].ConnectFrom(net.Outputs[0], 0.3)
+
+	simplified, report := net.Simplify()
+	assert.Equal(t, []int{7, 8}, nodeIds(report.RemovedNodes))
+	assert.Len(t, report.MergedLinks, 1)
+	assert.Empty(t, report.FoldedNodes, "no folding expected for recurrent network")
+
+	for steps := 1; steps < 10; steps++ {
+		in := []float64{0.5, 1.1}
+		expected := activateForSimplifyTest(t, net, in, steps)
+		actual := activateForSimplifyTest(t, simplified, in, steps)
+		assert.InDeltaSlice(t, expected, actual, 1e-12, "wrong outputs at step: %d", steps)
+	}
+}
+
+func TestNetwork_Simplify_aggregation(t *testing.T) {
+	allNodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, BiasNeuron),
+		NewNNode(3, HiddenNeuron),
+		NewNNode(4, OutputNeuron),
+	}
+	// HIDDEN 3 - linear node which folding requires merging with link from INPUT 1 to OUTPUT 4
+	allNodes[2].ActivationType = math.LinearActivation
+	allNodes[2].ConnectFrom(allNodes[0], 2.0)
+	// OUTPUT 4 - max aggregation of parallel links from INPUT 1, i.e., the absolute value of input
+	allNodes[3].ActivationType = math.LinearActivation
+	allNodes[3].AggregationType = math.MaxAggregation
+	allNodes[3].ConnectFrom(allNodes[0], 1.0)
+	allNodes[3].ConnectFrom(allNodes[0], -1.0)
+	allNodes[3].ConnectFrom(allNodes[2], 0.5)
+	net := NewNetwork(allNodes[0:2], allNodes[3:4], allNodes, 0)
+
+	simplified, report := net.Simplify()
+	assert.Empty(t, report.MergedLinks, "links into non-sum node must not be merged")
+	assert.Empty(t, report.FoldedNodes, "node must not be folded into non-sum node")
+	assert.Equal(t, net.LinkCount(), simplified.LinkCount())
+
+	depth, err := net.MaxActivationDepth()
+	require.NoError(t, err)
+	for _, in := range [][]float64{{0.5, 1.0}, {-2.0, 1.0}, {0.0, 1.0}} {
+		expected := activateForSimplifyTest(t, net, in, depth)
+		actual := activateForSimplifyTest(t, simplified, in, depth)
+		assert.InDeltaSlice(t, expected, actual, 1e-12, "wrong outputs for: %v", in)
+	}
+	assert.InDeltaSlice(t, []float64{2.0}, activateForSimplifyTest(t, simplified, []float64{-2.0, 1.0}, depth), 1e-12)
+
+	// the sum aggregation allows simplification
+	allNodes[3].AggregationType = math.SumAggregation
+	_, report = net.Simplify()
+	assert.Len(t, report.MergedLinks, 1)
+	assert.Equal(t, []int{3}, nodeIds(report.FoldedNodes))
+}
+
+func TestNetwork_Simplify_modular(t *testing.T) {
+	net := buildModularNetwork()
+
+	simplified, report := net.Simplify()
+	assert.True(t, report.IsEmpty(), report.String())
+	assert.Equal(t, net.NodeCount(), simplified.NodeCount())
+	assert.Equal(t, net.LinkCount(), simplified.LinkCount())
+	require.Len(t, simplified.ControlNodes(), 1)
+
+	in := []float64{0.5, 1.1}
+	expected := activateForSimplifyTest(t, net, in, 5)
+	actual := activateForSimplifyTest(t, simplified, in, 5)
+	assert.Equal(t, expected, actual)
+}
+
+func TestNetwork_Simplify_modularDead(t *testing.T) {
+	net := buildModularNetwork()
+	// disconnect module output from network outputs
+	hidden := net.allNodes[5]
+	for _, out := range net.Outputs {
+		out.Incoming = nil
+		out.ConnectFrom(net.inputs[0], 1.0)
+	}
+	hidden.Outgoing = nil
+
+	simplified, report := net.Simplify()
+	assert.Equal(t, []int{6}, nodeIds(report.RemovedControlNodes))
+	assert.Equal(t, []int{4, 5, 7}, nodeIds(report.RemovedNodes))
+	assert.Empty(t, simplified.ControlNodes())
+	assert.Equal(t, []int{1, 2, 3, 8, 9}, nodeIds(simplified.AllNodes()))
+}
+
+func TestSimplifyReport_String(t *testing.T) {
+	_, report := buildNetworkWithRedundancy().Simplify()
+	expected := "Simplification report:\n\tremoved nodes: [7 8]\n\tremoved control nodes: []\n\tremoved links: 2\n" +
+		"\tmerged links: 1\n\tfolded linear nodes: [5 6]\n\tunused inputs: []\n"
+	assert.Equal(t, expected, report.String())
+}
@@ -22,6 +22,17 @@ type Solver interface {
 	// false in case of error.
 	Flush() (bool, error)
 
+	// Reset Resets the solver to the initial state as right after creation. In addition to Flush, it clears the
+	// loaded sensors values and all internal memory of the recurrent activation.
+	Reset() error
+
+	// State Returns the snapshot of the current internal activation state of the solver, including the memory of the
+	// recurrent connections.
+	State() *SolverState
+	// SetState Restores the internal activation state of the solver from provided snapshot. The snapshot must be
+	// taken from the solver of the same type and structure, otherwise ErrSolverStateMismatch returned.
+	SetState(state *SolverState) error
+
 	// LoadSensors Set sensors values to the input nodes of the network
 	LoadSensors(inputs []float64) error
 	// ReadOutputs Read output values from the output nodes of the network
@@ -31,4 +42,9 @@ type Solver interface {
 	NodeCount() int
 	// LinkCount Returns the total number of links between nodes in the network
 	LinkCount() int
+
+	// Clone Returns new solver for the same network which has its own activation state, reset as after Flush. The
+	// immutable network structure is shared between solvers if possible. The cloned solvers are safe to be used
+	// concurrently with each other, e.g., to evaluate the same network on many episodes in parallel.
+	Clone() Solver
 }
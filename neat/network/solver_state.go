@@ -0,0 +1,106 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrSolverStateMismatch The error to be raised when solver state can not be applied to the solver because it was
+// taken from the solver of different type or structure.
+var ErrSolverStateMismatch = errors.New("the solver state doesn't match the solver")
+
+// The names of the solver types stored in the SolverState
+const (
+	NetworkSolverName              = "Network"
+	FastModularNetworkSolverName   = "FastModularNetworkSolver"
+	FastModularNetworkSolver32Name = "FastModularNetworkSolver32"
+	SortedNetworkSolverName        = "SortedNetworkSolver"
+)
+
+// SolverState is the snapshot of the internal activation state of the network solver, including the memory of the
+// recurrent connections. It can be taken with Solver.State and applied later to the same solver or to the other
+// solver of the same type and structure, e.g., the clone, with Solver.SetState. The state is serializable to JSON and
+// does not share data with the solver.
+type SolverState struct {
+	// The name of the solver type produced this state
+	Solver string `json:"solver"`
+	// The states of the network nodes followed by the control nodes, used by the Network
+	Nodes []NodeState `json:"nodes,omitempty"`
+	// The current activation values per each neuron, used by the fast and sorted solvers
+	Signals []float64 `json:"signals,omitempty"`
+	// The values of the neurons being processed by the activation step, used by the fast solvers
+	Processing []float64 `json:"processing,omitempty"`
+}
+
+// NodeState is the activation state of the network node
+type NodeState struct {
+	// The ID of the node
+	Id int `json:"id"`
+	// The node's activation value
+	Activation float64 `json:"activation"`
+	// The activation value of node at time t-1
+	LastActivation float64 `json:"last_activation"`
+	// The activation value of node at time t-2
+	LastActivation2 float64 `json:"last_activation2"`
+	// The activation sum
+	ActivationSum float64 `json:"activation_sum"`
+	// The number of activations of the node
+	ActivationsCount int32 `json:"activations_count"`
+	// The flag to indicate whether node is active
+	IsActive bool `json:"is_active"`
+}
+
+// Write is to write this state as JSON
+func (s *SolverState) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	return enc.Encode(s)
+}
+
+// ReadSolverState is to read the solver state written as JSON
+func ReadSolverState(r io.Reader) (*SolverState, error) {
+	var state SolverState
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(&state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// checkSignals checks that this state was produced by the solver with given name and has expected number of signals
+func (s *SolverState) checkSignals(solver string, signals int, withProcessing bool) error {
+	if s == nil {
+		return ErrSolverStateMismatch
+	}
+	if s.Solver != solver {
+		return fmt.Errorf("%w: expected state of: %s, found: %s", ErrSolverStateMismatch, solver, s.Solver)
+	}
+	if len(s.Signals) != signals || (withProcessing && len(s.Processing) != signals) {
+		return fmt.Errorf("%w: expected signals: %d, found: %d", ErrSolverStateMismatch, signals, len(s.Signals))
+	}
+	return nil
+}
+
+// state returns the activation state of this node
+func (n *NNode) state() NodeState {
+	return NodeState{
+		Id:               n.Id,
+		Activation:       n.Activation,
+		LastActivation:   n.lastActivation,
+		LastActivation2:  n.lastActivation2,
+		ActivationSum:    n.ActivationSum,
+		ActivationsCount: n.ActivationsCount,
+		IsActive:         n.isActive,
+	}
+}
+
+// setState sets the activation state of this node
+func (n *NNode) setState(state NodeState) {
+	n.Activation = state.Activation
+	n.lastActivation = state.LastActivation
+	n.lastActivation2 = state.LastActivation2
+	n.ActivationSum = state.ActivationSum
+	n.ActivationsCount = state.ActivationsCount
+	n.isActive = state.IsActive
+}
@@ -0,0 +1,224 @@
+package network
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yaricom/goNEAT/v4/neat/math"
+	"testing"
+)
+
+// buildRecurrentNetwork builds the network with hidden node having recurrent self-connection, which keeps the memory
+// of the previous inputs
+func buildRecurrentNetwork() *Network {
+	allNodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, BiasNeuron),
+		NewNNode(3, HiddenNeuron),
+		NewNNode(4, OutputNeuron),
+	}
+	allNodes[2].ActivationType = math.TanhActivation
+	allNodes[2].ConnectFrom(allNodes[0], 1.5)
+	allNodes[2].ConnectFrom(allNodes[1], -0.2)
+	allNodes[2].ConnectFrom(allNodes[2], 0.8).IsRecurrent = true
+	allNodes[3].ActivationType = math.LinearActivation
+	allNodes[3].ConnectFrom(allNodes[2], 2.0)
+
+	return NewNetwork(allNodes[0:2], allNodes[3:4], allNodes, 0)
+}
+
+func solverStateTestCases(t *testing.T) map[string]Solver {
+	fmm, err := buildRecurrentNetwork().FastNetworkSolver()
+	require.NoError(t, err)
+	fmm32, err := buildRecurrentNetwork().FastNetworkSolver32()
+	require.NoError(t, err)
+	sorted, err := NewSortedNetworkSolver(buildNetwork())
+	require.NoError(t, err)
+	return map[string]Solver{
+		"network":        buildRecurrentNetwork(),
+		"modularNetwork": buildModularNetwork(),
+		"fast":           fmm,
+		"fast32":         fmm32,
+		"sorted":         sorted,
+	}
+}
+
+// runSequence loads each input from sequence into all sensors and activates solver for a few steps. Returns the
+// outputs per input.
+func runSequence(t *testing.T, solver Solver, sequence []float64) [][]float64 {
+	inputsCount := 0
+	switch s := solver.(type) {
+	case *Network:
+		for _, node := range s.InputNodes() {
+			if node.NeuronType == InputNeuron {
+				inputsCount++
+			}
+		}
+	case *FastModularNetworkSolver:
+		inputsCount = s.inputNeuronCount
+	case *FastModularNetworkSolver32:
+		inputsCount = s.inputNeuronCount
+	case *SortedNetworkSolver:
+		inputsCount = s.inputsCount
+	}
+	outputs := make([][]float64, len(sequence))
+	for i, value := range sequence {
+		inputs := make([]float64, inputsCount)
+		for j := range inputs {
+			inputs[j] = value
+		}
+		require.NoError(t, solver.LoadSensors(inputs))
+		_, err := solver.ForwardSteps(4)
+		require.NoError(t, err)
+		outputs[i] = solver.ReadOutputs()
+	}
+	return outputs
+}
+
+func TestSolver_StateSetState(t *testing.T) {
+	warmup, branch := []float64{0.3, -0.7, 0.9}, []float64{0.1, 0.5, -0.2, 0.4}
+	for name, solver := range solverStateTestCases(t) {
+		t.Run(name, func(t *testing.T) {
+			runSequence(t, solver, warmup)
+			checkpoint := solver.State()
+
+			expected := runSequence(t, solver, branch)
+
+			// run other branch to change the state
+			runSequence(t, solver, []float64{-0.9, 0.8})
+
+			// restore and repeat branch
+			err := solver.SetState(checkpoint)
+			require.NoError(t, err)
+			assert.Equal(t, expected, runSequence(t, solver, branch))
+
+			// branch the state into the clone
+			clone := solver.Clone()
+			err = clone.SetState(checkpoint)
+			require.NoError(t, err)
+			assert.Equal(t, expected, runSequence(t, clone, branch))
+		})
+	}
+}
+
+func TestSolver_State_isSnapshot(t *testing.T) {
+	for name, solver := range solverStateTestCases(t) {
+		t.Run(name, func(t *testing.T) {
+			runSequence(t, solver, []float64{0.3, -0.7})
+			state := solver.State()
+			expected := solver.State()
+
+			runSequence(t, solver, []float64{0.9})
+			assert.Equal(t, expected, state, "state must not share data with solver")
+		})
+	}
+}
+
+func TestSolver_Reset(t *testing.T) {
+	for name, solver := range solverStateTestCases(t) {
+		t.Run(name, func(t *testing.T) {
+			initial := solver.Clone().State()
+			runSequence(t, solver, []float64{0.3, -0.7, 0.9})
+			require.NotEqual(t, initial, solver.State())
+
+			err := solver.Reset()
+			require.NoError(t, err)
+			assert.Equal(t, initial, solver.State())
+		})
+	}
+}
+
+func TestNetwork_Reset_differsFromFlush(t *testing.T) {
+	net := buildModularNetwork()
+	runSequence(t, net, []float64{0.3, -0.7})
+
+	// the Flush doesn't affect control nodes and activation sums
+	_, err := net.Flush()
+	require.NoError(t, err)
+	assert.True(t, net.ControlNodes()[0].isActive)
+
+	err = net.Reset()
+	require.NoError(t, err)
+	for _, node := range net.allNodesMIMO {
+		assert.Equal(t, NodeState{Id: node.Id}, node.state())
+	}
+}
+
+func TestSortedNetworkSolver_Reset_differsFromFlush(t *testing.T) {
+	solver, err := NewSortedNetworkSolver(buildNetwork())
+	require.NoError(t, err)
+	runSequence(t, solver, []float64{0.5})
+
+	// the Flush keeps sensors values loaded
+	_, err = solver.Flush()
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, solver.signals[solver.sensorIndexes[0]])
+
+	err = solver.Reset()
+	require.NoError(t, err)
+	for _, signal := range solver.signals {
+		assert.Zero(t, signal)
+	}
+}
+
+func TestSolverState_WriteRead(t *testing.T) {
+	for name, solver := range solverStateTestCases(t) {
+		t.Run(name, func(t *testing.T) {
+			runSequence(t, solver, []float64{0.3, -0.7, 0.9})
+			state := solver.State()
+
+			var buf bytes.Buffer
+			err := state.Write(&buf)
+			require.NoError(t, err)
+
+			restored, err := ReadSolverState(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, state, restored)
+
+			clone := solver.Clone()
+			err = clone.SetState(restored)
+			require.NoError(t, err)
+			assert.Equal(t, solver.ReadOutputs(), clone.ReadOutputs())
+		})
+	}
+}
+
+func TestReadSolverState_error(t *testing.T) {
+	_, err := ReadSolverState(bytes.NewBufferString("{"))
+	assert.Error(t, err)
+}
+
+func TestSolver_SetState_mismatch(t *testing.T) {
+	cases := solverStateTestCases(t)
+	for name, solver := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, solver.SetState(nil), ErrSolverStateMismatch)
+
+			// the state of other solver type
+			for otherName, other := range cases {
+				if otherName == name || (otherName == "network" && name == "modularNetwork") ||
+					(otherName == "modularNetwork" && name == "network") {
+					continue
+				}
+				assert.ErrorIs(t, solver.SetState(other.State()), ErrSolverStateMismatch, otherName)
+			}
+
+			// the state of wrong size
+			state := solver.State()
+			state.Nodes = state.Nodes[:len(state.Nodes)/2]
+			if len(state.Signals) > 0 {
+				state.Signals = state.Signals[1:]
+			}
+			assert.ErrorIs(t, solver.SetState(state), ErrSolverStateMismatch)
+		})
+	}
+
+	// the state of network with different nodes
+	err := buildRecurrentNetwork().SetState(buildModularNetwork().State())
+	assert.ErrorIs(t, err, ErrSolverStateMismatch)
+
+	state := buildNetwork().State()
+	state.Nodes[0].Id = 100
+	err = buildNetwork().SetState(state)
+	assert.ErrorIs(t, err, ErrSolverStateMismatch)
+}
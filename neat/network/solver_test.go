@@ -0,0 +1,230 @@
+package network
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	neatmath "github.com/yaricom/goNEAT/v4/neat/math"
+	"gonum.org/v1/gonum/mat"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func solversForCloneTest(t *testing.T, build func() *Network) map[string]Solver {
+	fastSolver, err := build().FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	sortedSolver, err := NewSortedNetworkSolver(build())
+	require.NoError(t, err, "failed to create sorted network solver")
+	return map[string]Solver{
+		"Network":                  build(),
+		"FastModularNetworkSolver": fastSolver,
+		"SortedNetworkSolver":      sortedSolver,
+	}
+}
+
+func activateSolver(solver Solver, inputs []float64) ([]float64, error) {
+	if _, err := solver.Flush(); err != nil {
+		return nil, err
+	}
+	if err := solver.LoadSensors(inputs); err != nil {
+		return nil, err
+	}
+	if _, err := solver.ForwardSteps(5); err != nil {
+		return nil, err
+	}
+	return solver.ReadOutputs(), nil
+}
+
+func TestSolver_Clone(t *testing.T) {
+	inputs := []float64{0.5, 1.1}
+	for name, solver := range solversForCloneTest(t, buildModularNetwork) {
+		t.Run(name, func(t *testing.T) {
+			expected, err := activateSolver(solver, inputs)
+			require.NoError(t, err)
+
+			clone := solver.Clone()
+			require.NotNil(t, clone)
+			assert.IsType(t, solver, clone)
+			assert.Equal(t, solver.NodeCount(), clone.NodeCount())
+			assert.Equal(t, solver.LinkCount(), clone.LinkCount())
+
+			// the clone has its own state
+			assert.Equal(t, make([]float64, len(expected)), clone.ReadOutputs())
+			outputs, err := activateSolver(clone, inputs)
+			require.NoError(t, err)
+			assert.Equal(t, expected, outputs)
+
+			// flushing clone doesn't affect the original solver
+			_, err = clone.Flush()
+			require.NoError(t, err)
+			assert.Equal(t, expected, solver.ReadOutputs())
+		})
+	}
+}
+
+func TestSolver_Clone_concurrent(t *testing.T) {
+	inputs := [][]float64{{0.5, 1.1}, {0.0, 0.0}, {-1.0, 2.0}, {1.0, 2.0}}
+	for name, solver := range solversForCloneTest(t, buildNetwork) {
+		t.Run(name, func(t *testing.T) {
+			expected := make([][]float64, len(inputs))
+			for i, in := range inputs {
+				outputs, err := activateSolver(solver, in)
+				require.NoError(t, err)
+				expected[i] = outputs
+			}
+
+			workers := 8
+			results := make([][][]float64, workers)
+			errs := make([]error, workers)
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(w int, clone Solver) {
+					defer wg.Done()
+					for i := 0; i < 100; i++ {
+						in := inputs[(w+i)%len(inputs)]
+						outputs, err := activateSolver(clone, in)
+						if err != nil {
+							errs[w] = err
+							return
+						}
+						results[w] = append(results[w], outputs)
+					}
+				}(w, solver.Clone())
+			}
+			wg.Wait()
+
+			for w := 0; w < workers; w++ {
+				require.NoError(t, errs[w])
+				for i, outputs := range results[w] {
+					assert.Equal(t, expected[(w+i)%len(inputs)], outputs, "wrong outputs, worker: %d, step: %d", w, i)
+				}
+			}
+		})
+	}
+}
+
+func TestNetwork_Clone(t *testing.T) {
+	net := buildModularNetwork()
+	net.Name = "modular"
+	clone, ok := net.Clone().(*Network)
+	require.True(t, ok)
+
+	assert.Equal(t, net.Id, clone.Id)
+	assert.Equal(t, net.Name, clone.Name)
+	require.Len(t, clone.AllNodes(), len(net.AllNodes()))
+	require.Len(t, clone.ControlNodes(), len(net.ControlNodes()))
+	require.Len(t, clone.inputs, len(net.inputs))
+	require.Len(t, clone.Outputs, len(net.Outputs))
+
+	// check that nodes are copied and not shared
+	original := make(map[*NNode]bool)
+	for _, node := range net.AllNodes() {
+		original[node] = true
+	}
+	for i, node := range clone.AllNodes() {
+		assert.False(t, original[node], "node is shared: %s", node)
+		expected := net.AllNodes()[i]
+		assert.Equal(t, expected.Id, node.Id)
+		assert.Equal(t, expected.NeuronType, node.NeuronType)
+		assert.Equal(t, expected.ActivationType, node.ActivationType)
+		require.Len(t, node.Incoming, len(expected.Incoming))
+		require.Len(t, node.Outgoing, len(expected.Outgoing))
+		for j, link := range node.Incoming {
+			assert.Equal(t, expected.Incoming[j].ConnectionWeight, link.ConnectionWeight)
+			assert.Equal(t, expected.Incoming[j].InNode.Id, link.InNode.Id)
+			assert.False(t, original[link.InNode], "link source is shared: %s", link)
+			assert.Same(t, node, link.OutNode)
+		}
+	}
+}
+
+func TestSolver_parametricActivations(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	build := func() *Network {
+		rnd = rand.New(rand.NewSource(42))
+		net := buildRandomFeedForwardNetwork(rnd, 4, 8, 3)
+		activations := []neatmath.NodeActivationType{neatmath.SigmoidParametricActivation,
+			neatmath.GaussianParametricActivation, neatmath.LeakyReLUParametricActivation}
+		for i, node := range net.BaseNodes() {
+			if node.IsNeuron() {
+				node.ActivationType = activations[i%len(activations)]
+				node.Params = []float64{rnd.Float64(), rnd.Float64(), rnd.Float64()}
+			}
+		}
+		return net
+	}
+	inputs := randomInputs(rand.New(rand.NewSource(7)), 4)
+	forward := func(s Solver) (bool, error) { return s.ForwardSteps(10) }
+	expected := activateFastSolver(t, build(), inputs, forward)
+
+	solvers := solversForCloneTest(t, build)
+	fast32, err := build().FastNetworkSolver32()
+	require.NoError(t, err)
+	solvers["FastModularNetworkSolver32"] = fast32
+	for name, solver := range solvers {
+		t.Run(name, func(t *testing.T) {
+			assertOutputsWithinTolerance(t, expected, activateFastSolver(t, solver, inputs, forward))
+		})
+	}
+
+	// check batch activation and model persistence
+	fastSolver := solvers["FastModularNetworkSolver"].(*FastModularNetworkSolver)
+	require.NotNil(t, fastSolver.Structure().NeuronParams)
+	outputs, err := fastSolver.ActivateBatch(mat.NewDense(1, len(inputs), inputs))
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, expected, mat.Row(nil, 0, outputs), 1e-12)
+
+	buf := bytes.NewBufferString("")
+	require.NoError(t, fastSolver.WriteModel(buf))
+	restored, err := ReadFMNSModel(buf)
+	require.NoError(t, err)
+	assert.Equal(t, fastSolver.neuronParams, restored.neuronParams)
+	assert.InDeltaSlice(t, expected, activateFastSolver(t, restored, inputs, forward), 1e-12)
+}
+
+func TestSolver_aggregationFunctions(t *testing.T) {
+	build := func() *Network {
+		rnd := rand.New(rand.NewSource(42))
+		net := buildRandomFeedForwardNetwork(rnd, 4, 8, 3)
+		for i, node := range net.BaseNodes() {
+			if node.IsNeuron() {
+				node.AggregationType = neatmath.NodeAggregationType(i % int(neatmath.MaxAbsAggregation+1))
+			}
+		}
+		return net
+	}
+	inputs := randomInputs(rand.New(rand.NewSource(7)), 4)
+	forward := func(s Solver) (bool, error) { return s.ForwardSteps(10) }
+	expected := activateFastSolver(t, build(), inputs, forward)
+
+	solvers := solversForCloneTest(t, build)
+	fast32, err := build().FastNetworkSolver32()
+	require.NoError(t, err)
+	solvers["FastModularNetworkSolver32"] = fast32
+	for name, solver := range solvers {
+		t.Run(name, func(t *testing.T) {
+			assertOutputsWithinTolerance(t, expected, activateFastSolver(t, solver, inputs, forward))
+		})
+	}
+
+	// check recursive and batch activation, and model persistence
+	fastSolver := solvers["FastModularNetworkSolver"].(*FastModularNetworkSolver)
+	require.NotNil(t, fastSolver.Structure().AggregationFunctions)
+	recursive := func(s Solver) (bool, error) { return s.RecursiveSteps() }
+	assert.InDeltaSlice(t, expected, activateFastSolver(t, fastSolver, inputs, recursive), 1e-12)
+	assertOutputsWithinTolerance(t, expected, activateFastSolver(t, fast32, inputs, recursive))
+
+	outputs, err := fastSolver.ActivateBatch(mat.NewDense(1, len(inputs), inputs))
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, expected, mat.Row(nil, 0, outputs), 1e-12)
+
+	buf := bytes.NewBufferString("")
+	require.NoError(t, fastSolver.WriteModel(buf))
+	assert.Contains(t, buf.String(), `"MaxAbsAggregation"`)
+	restored, err := ReadFMNSModel(buf)
+	require.NoError(t, err)
+	assert.Equal(t, fastSolver.aggregationFunctions, restored.aggregationFunctions)
+	assert.InDeltaSlice(t, expected, activateFastSolver(t, restored, inputs, forward), 1e-12)
+}
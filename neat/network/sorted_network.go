@@ -0,0 +1,356 @@
+package network
+
+import (
+	"fmt"
+	neatmath "github.com/yaricom/goNEAT/v4/neat/math"
+)
+
+// sortedNetworkStep is the single step of the SortedNetworkSolver evaluation. It either activates the neuron with
+// incoming connections in the given range or, if moduleInputs is not nil, the control node relaying signals between
+// network modules.
+type sortedNetworkStep struct {
+	// The index of the neuron signal
+	index int
+	// The range of incoming connections of the neuron in the flattened connections arrays
+	start, end int
+	// The activation function of the neuron or control node
+	activationType neatmath.NodeActivationType
+	// The auxiliary parameters of the activation function
+	params []float64
+	// The aggregation function of the neuron input signals
+	aggregationType neatmath.NodeAggregationType
+	// The indexes of the input signals of the control node
+	moduleInputs []int
+	// The indexes of the output signals of the control node
+	moduleOutputs []int
+}
+
+// SortedNetworkSolver is the network solver for acyclic networks which sorts network nodes topologically once
+// at construction and evaluates each node exactly once per activation in order of the flat array. Thus, any call to
+// ForwardSteps, RecursiveSteps, or Relax performs a single pass through the network which produces the same outputs
+// as activation of the network until all outputs are active, regardless of the network depth. The neurons not
+// reachable from the sensors have zero activation value the same as in the network.
+type SortedNetworkSolver struct {
+	// A network id
+	Id int
+	// Is a name of this network
+	Name string
+
+	// The current activation values of the sensors followed by other nodes in topological order
+	signals []float64
+	// The indexes of the input sensors signals in order of the network inputs
+	sensorIndexes []int
+	// The flags to indicate whether corresponding network input is BIAS
+	sensorIsBias []bool
+	// The number of sensors excluding BIAS
+	inputsCount int
+	// The indexes of the output signals
+	outputIndexes []int
+	// The evaluation steps in topological order
+	steps []sortedNetworkStep
+	// The flattened indexes of source signals of incoming connections in order of evaluation steps
+	sources []int
+	// The flattened weights of incoming connections in order of evaluation steps
+	weights []float64
+
+	nodeCount int
+	linkCount int
+}
+
+// NewSortedNetworkSolver creates new solver for the provided acyclic network. Returns ErrNetNotFeedForward if network
+// has recurrent or time delayed connections.
+func NewSortedNetworkSolver(n *Network) (*SortedNetworkSolver, error) {
+	// collect the graph of nodes and control nodes
+	allNodes := n.AllNodes()
+	inDegree := make(map[*NNode]int, len(allNodes))
+	outgoing := make(map[*NNode][]*NNode, len(allNodes))
+	addEdge := func(from, to *NNode) {
+		outgoing[from] = append(outgoing[from], to)
+		inDegree[to]++
+	}
+	moduleOutputs, controlNodes := make(map[*NNode]bool), make(map[*NNode]bool)
+	for _, node := range n.allNodes {
+		if !node.IsNeuron() {
+			continue
+		}
+		for _, link := range node.Incoming {
+			if link.IsTimeDelayed {
+				return nil, ErrNetNotFeedForward
+			}
+			addEdge(link.InNode, node)
+		}
+	}
+	for _, cn := range n.controlNodes {
+		controlNodes[cn] = true
+		for _, link := range cn.Incoming {
+			addEdge(link.InNode, cn)
+		}
+		for _, link := range cn.Outgoing {
+			addEdge(cn, link.OutNode)
+			moduleOutputs[link.OutNode] = true
+		}
+	}
+
+	// the sensors go first in order of network inputs
+	s := &SortedNetworkSolver{
+		Id:            n.Id,
+		Name:          n.Name,
+		sensorIndexes: make([]int, 0, len(n.inputs)),
+		sensorIsBias:  make([]bool, 0, len(n.inputs)),
+		nodeCount:     n.NodeCount(),
+		linkCount:     n.LinkCount(),
+	}
+	indexes := make(map[*NNode]int, len(allNodes))
+	for _, node := range n.inputs {
+		if _, ok := indexes[node]; !ok {
+			indexes[node] = len(indexes)
+		}
+		s.sensorIndexes = append(s.sensorIndexes, indexes[node])
+		s.sensorIsBias = append(s.sensorIsBias, node.NeuronType == BiasNeuron)
+		if node.NeuronType != BiasNeuron {
+			s.inputsCount++
+		}
+	}
+
+	// Kahn's topological sorting, the nodes are activated only if reachable from the sensors
+	active := make(map[*NNode]bool, len(allNodes))
+	queue := make([]*NNode, 0, len(allNodes))
+	for _, node := range allNodes {
+		if inDegree[node] == 0 {
+			queue = append(queue, node)
+		}
+		if node.IsSensor() {
+			active[node] = true
+		}
+	}
+	visited := 0
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		visited++
+		if _, ok := indexes[node]; !ok {
+			indexes[node] = len(indexes)
+		}
+		if !node.IsSensor() && active[node] {
+			s.steps = append(s.steps, sortedNetworkStep{index: indexes[node]})
+		}
+		for _, next := range outgoing[node] {
+			active[next] = active[next] || active[node]
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	if visited != len(allNodes) {
+		return nil, ErrNetNotFeedForward
+	}
+
+	// build evaluation steps
+	nodesByIndex := make([]*NNode, len(indexes))
+	for node, index := range indexes {
+		nodesByIndex[index] = node
+	}
+	steps := make([]sortedNetworkStep, 0, len(s.steps))
+	for _, step := range s.steps {
+		node := nodesByIndex[step.index]
+		step.activationType = node.ActivationType
+		step.params = node.Params
+		step.aggregationType = node.AggregationType
+		if controlNodes[node] {
+			step.moduleInputs = make([]int, len(node.Incoming))
+			for i, link := range node.Incoming {
+				step.moduleInputs[i] = indexes[link.InNode]
+			}
+			step.moduleOutputs = make([]int, len(node.Outgoing))
+			for i, link := range node.Outgoing {
+				step.moduleOutputs[i] = indexes[link.OutNode]
+			}
+		} else {
+			if moduleOutputs[node] {
+				// the signal is set by control node
+				continue
+			}
+			step.start = len(s.sources)
+			for _, link := range node.Incoming {
+				// the inactive sources have zero signal, which doesn't change the sum, but may change the result
+				// of other aggregation functions
+				if active[link.InNode] || node.AggregationType != neatmath.SumAggregation {
+					s.sources = append(s.sources, indexes[link.InNode])
+					s.weights = append(s.weights, link.ConnectionWeight)
+				}
+			}
+			step.end = len(s.sources)
+		}
+		steps = append(steps, step)
+	}
+	s.steps = steps
+	s.signals = make([]float64, len(indexes))
+
+	s.outputIndexes = make([]int, len(n.Outputs))
+	for i, node := range n.Outputs {
+		s.outputIndexes[i] = indexes[node]
+	}
+	return s, nil
+}
+
+// ForwardSteps performs single pass through the network, the number of steps is ignored as far as each node
+// activated exactly once in topological order.
+func (s *SortedNetworkSolver) ForwardSteps(steps int) (bool, error) {
+	if steps <= 0 {
+		return false, ErrZeroActivationStepsRequested
+	}
+	return s.activate()
+}
+
+// RecursiveSteps performs single pass through the network.
+func (s *SortedNetworkSolver) RecursiveSteps() (bool, error) {
+	return s.activate()
+}
+
+// Relax performs single pass through the network. The acyclic network is always relaxed after a single pass.
+func (s *SortedNetworkSolver) Relax(_ int, _ float64) (bool, error) {
+	return s.activate()
+}
+
+// activate is to activate each node exactly once in topological order
+func (s *SortedNetworkSolver) activate() (bool, error) {
+	for i := range s.steps {
+		step := &s.steps[i]
+		if step.moduleInputs != nil {
+			inputs := make([]float64, len(step.moduleInputs))
+			for j, index := range step.moduleInputs {
+				inputs[j] = s.signals[index]
+			}
+			outputs, err := neatmath.NodeActivators.ActivateModuleByType(inputs, step.params, step.activationType)
+			if err != nil {
+				return false, err
+			}
+			if len(outputs) != len(step.moduleOutputs) {
+				return false, fmt.Errorf(
+					"number of output parameters [%d] returned by module activator doesn't match "+
+						"the number of output neurons of the module [%d]", len(outputs), len(step.moduleOutputs))
+			}
+			for j, index := range step.moduleOutputs {
+				s.signals[index] = outputs[j]
+			}
+			continue
+		}
+
+		sum := 0.0
+		if step.aggregationType == neatmath.SumAggregation {
+			for j := step.start; j < step.end; j++ {
+				sum += s.weights[j] * s.signals[s.sources[j]]
+			}
+		} else {
+			inputs := make([]float64, step.end-step.start)
+			for j := step.start; j < step.end; j++ {
+				inputs[j-step.start] = s.weights[j] * s.signals[s.sources[j]]
+			}
+			var err error
+			if sum, err = neatmath.Aggregate(step.aggregationType, inputs); err != nil {
+				return false, err
+			}
+		}
+		out, err := neatmath.NodeActivators.ActivateByType(sum, step.params, step.activationType)
+		if err != nil {
+			return false, err
+		}
+		s.signals[step.index] = out
+	}
+	return true, nil
+}
+
+// Flush resets activation values of all nodes except sensors.
+func (s *SortedNetworkSolver) Flush() (bool, error) {
+	for i := len(s.sensorIndexes); i < len(s.signals); i++ {
+		s.signals[i] = 0
+	}
+	return true, nil
+}
+
+// Reset resets activation values of all nodes including sensors.
+func (s *SortedNetworkSolver) Reset() error {
+	for i := range s.signals {
+		s.signals[i] = 0
+	}
+	return nil
+}
+
+// State returns the snapshot of activation values of all nodes. As far as the solver activates acyclic network, the
+// state has no memory of the previous activations except the loaded sensors values.
+func (s *SortedNetworkSolver) State() *SolverState {
+	state := &SolverState{
+		Solver:  SortedNetworkSolverName,
+		Signals: make([]float64, len(s.signals)),
+	}
+	copy(state.Signals, s.signals)
+	return state
+}
+
+// SetState restores activation values of all nodes from provided snapshot.
+func (s *SortedNetworkSolver) SetState(state *SolverState) error {
+	if err := state.checkSignals(SortedNetworkSolverName, len(s.signals), false); err != nil {
+		return err
+	}
+	copy(s.signals, state.Signals)
+	return nil
+}
+
+// LoadSensors sets values of the sensors. If the number of provided values is equal to the number of network inputs,
+// the values of BIAS sensors are also loaded. Otherwise, only the values of non BIAS sensors should be provided and
+// BIAS sensors are set to 1.0.
+func (s *SortedNetworkSolver) LoadSensors(inputs []float64) error {
+	if len(inputs) == len(s.sensorIndexes) {
+		for i, index := range s.sensorIndexes {
+			s.signals[index] = inputs[i]
+		}
+		return nil
+	}
+	if len(inputs) != s.inputsCount {
+		return ErrNetUnsupportedSensorsArraySize
+	}
+	counter := 0
+	for i, index := range s.sensorIndexes {
+		if s.sensorIsBias[i] {
+			s.signals[index] = 1.0 // default BIAS value
+		} else {
+			s.signals[index] = inputs[counter]
+			counter++
+		}
+	}
+	return nil
+}
+
+// ReadOutputs returns activation values of the output nodes.
+func (s *SortedNetworkSolver) ReadOutputs() []float64 {
+	outs := make([]float64, len(s.outputIndexes))
+	for i, index := range s.outputIndexes {
+		outs[i] = s.signals[index]
+	}
+	return outs
+}
+
+// Clone returns new solver which shares the evaluation steps and connections with this solver, and has its own
+// activation values.
+func (s *SortedNetworkSolver) Clone() Solver {
+	clone := *s
+	clone.signals = make([]float64, len(s.signals))
+	return &clone
+}
+
+// NodeCount returns the total number of nodes in the network including control nodes.
+func (s *SortedNetworkSolver) NodeCount() int {
+	return s.nodeCount
+}
+
+// LinkCount returns the total number of links in the network.
+func (s *SortedNetworkSolver) LinkCount() int {
+	return s.linkCount
+}
+
+// Stringer
+func (s *SortedNetworkSolver) String() string {
+	return fmt.Sprintf("SortedNetwork, id: %d, name: [%s], nodes: %d, links: %d, activation steps: %d",
+		s.Id, s.Name, s.nodeCount, s.linkCount, len(s.steps))
+}
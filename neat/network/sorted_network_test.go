@@ -0,0 +1,153 @@
+package network
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewSortedNetworkSolver(t *testing.T) {
+	inputs := [][]float64{{0.5, 1.1}, {0.0, 0.0}, {-1.0, 2.0}, {1.0, 2.0}}
+	testCases := map[string]func() *Network{
+		"plain":   buildPlainNetwork,
+		"hidden":  buildNetwork,
+		"modular": buildModularNetwork,
+	}
+	for name, build := range testCases {
+		t.Run(name, func(t *testing.T) {
+			net := build()
+			solver, err := NewSortedNetworkSolver(net)
+			require.NoError(t, err, "failed to create sorted network solver")
+			fastSolver, err := build().FastNetworkSolver()
+			require.NoError(t, err, "failed to create fast network solver")
+
+			for _, in := range inputs {
+				_, err = fastSolver.Flush()
+				require.NoError(t, err)
+				err = fastSolver.LoadSensors(in)
+				require.NoError(t, err)
+				_, err = fastSolver.ForwardSteps(5)
+				require.NoError(t, err)
+
+				_, err = solver.Flush()
+				require.NoError(t, err)
+				err = solver.LoadSensors(in)
+				require.NoError(t, err)
+				res, err := solver.ForwardSteps(1)
+				require.NoError(t, err)
+				assert.True(t, res)
+				assert.InDeltaSlice(t, fastSolver.ReadOutputs(), solver.ReadOutputs(), 1e-12, "wrong outputs for: %v", in)
+			}
+		})
+	}
+}
+
+func TestSortedNetworkSolver_sameAsNetwork(t *testing.T) {
+	net := buildNetwork()
+	solver, err := NewSortedNetworkSolver(net)
+	require.NoError(t, err, "failed to create sorted network solver")
+
+	in := []float64{0.5, 1.1, 1.0}
+	err = net.LoadSensors(in)
+	require.NoError(t, err)
+	depth, err := net.MaxActivationDepth()
+	require.NoError(t, err)
+	_, err = net.ForwardSteps(depth)
+	require.NoError(t, err)
+
+	err = solver.LoadSensors(in)
+	require.NoError(t, err)
+	_, err = solver.RecursiveSteps()
+	require.NoError(t, err)
+	assert.Equal(t, net.ReadOutputs(), solver.ReadOutputs())
+
+	// repeated activation produces the same results
+	_, err = solver.Relax(10, 0.1)
+	require.NoError(t, err)
+	assert.Equal(t, net.ReadOutputs(), solver.ReadOutputs())
+}
+
+func TestNewSortedNetworkSolver_recurrent(t *testing.T) {
+	net := buildNetwork()
+	// make recurrent link from OUTPUT 7 to HIDDEN 6
+	net.allNodes[5].ConnectFrom(net.allNodes[6], 1.0)
+
+	solver, err := NewSortedNetworkSolver(net)
+	assert.ErrorIs(t, err, ErrNetNotFeedForward)
+	assert.Nil(t, solver)
+
+	// the fast solver is used as a fallback
+	fallback, err := net.SortedNetworkSolver()
+	require.NoError(t, err)
+	assert.IsType(t, &FastModularNetworkSolver{}, fallback)
+}
+
+func TestNewSortedNetworkSolver_timeDelayed(t *testing.T) {
+	net := buildNetwork()
+	net.allNodes[3].Incoming[0].IsTimeDelayed = true
+
+	_, err := NewSortedNetworkSolver(net)
+	assert.ErrorIs(t, err, ErrNetNotFeedForward)
+}
+
+func TestNetwork_SortedNetworkSolver(t *testing.T) {
+	solver, err := buildNetwork().SortedNetworkSolver()
+	require.NoError(t, err)
+	assert.IsType(t, &SortedNetworkSolver{}, solver)
+}
+
+func TestSortedNetworkSolver_LoadSensors(t *testing.T) {
+	solver, err := NewSortedNetworkSolver(buildNetwork())
+	require.NoError(t, err)
+
+	// without BIAS
+	err = solver.LoadSensors([]float64{0.5, 1.1})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.5, 1.1, 1.0}, solver.signals[:3])
+
+	// with BIAS
+	err = solver.LoadSensors([]float64{0.5, 1.1, 2.0})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0.5, 1.1, 2.0}, solver.signals[:3])
+
+	// wrong size
+	err = solver.LoadSensors([]float64{0.5})
+	assert.ErrorIs(t, err, ErrNetUnsupportedSensorsArraySize)
+}
+
+func TestSortedNetworkSolver_Flush(t *testing.T) {
+	solver, err := NewSortedNetworkSolver(buildNetwork())
+	require.NoError(t, err)
+
+	in := []float64{0.5, 1.1}
+	err = solver.LoadSensors(in)
+	require.NoError(t, err)
+	_, err = solver.ForwardSteps(1)
+	require.NoError(t, err)
+
+	res, err := solver.Flush()
+	require.NoError(t, err)
+	assert.True(t, res)
+	assert.Equal(t, []float64{0.5, 1.1, 1.0}, solver.signals[:3], "sensors should not be flushed")
+	for _, v := range solver.signals[3:] {
+		assert.Zero(t, v)
+	}
+}
+
+func TestSortedNetworkSolver_ForwardSteps_zero(t *testing.T) {
+	solver, err := NewSortedNetworkSolver(buildNetwork())
+	require.NoError(t, err)
+
+	res, err := solver.ForwardSteps(0)
+	assert.ErrorIs(t, err, ErrZeroActivationStepsRequested)
+	assert.False(t, res)
+}
+
+func TestSortedNetworkSolver_NodeCount_LinkCount(t *testing.T) {
+	net := buildModularNetwork()
+	solver, err := NewSortedNetworkSolver(net)
+	require.NoError(t, err)
+
+	assert.Equal(t, net.NodeCount(), solver.NodeCount())
+	assert.Equal(t, net.LinkCount(), solver.LinkCount())
+}
@@ -0,0 +1,327 @@
+package network
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/sbinet/npyio/npz"
+	"gonum.org/v1/gonum/mat"
+	"io"
+	"strconv"
+)
+
+var (
+	// ErrTraceStructureMismatch the error to be raised when activation trace is shared by solvers with different structure
+	ErrTraceStructureMismatch = errors.New("the activation trace was recorded for solver with different structure")
+	// ErrTraceIsEmpty the error to be raised when there is no activation steps recorded by trace
+	ErrTraceIsEmpty = errors.New("the activation trace has no recorded steps")
+)
+
+// TracedLink describes the link which signals are recorded by the ActivationTrace
+type TracedLink struct {
+	// The ID of the source node
+	InId int
+	// The ID of the target node
+	OutId int
+}
+
+// ActivationTrace records the activation sums and outputs of every node, and optionally the signals relayed by links
+// at each activation step of the network solver. The steps of consecutive activations are appended to the trace, which
+// allows recording of the network behavior during the whole evaluation episode. The control nodes of modular network
+// are not recorded, but their effect can be observed at the outputs of the nodes they connect.
+type ActivationTrace struct {
+	// The IDs of the recorded nodes in order of columns of Sums and Outputs
+	NodeIds []int
+	// The recorded links in order of columns of Signals
+	Links []TracedLink
+	// The activation sums per node at each step
+	Sums [][]float64
+	// The activation outputs per node at each step
+	Outputs [][]float64
+	// The signals per link at each step, i.e., the source node output multiplied by the link weight. It is empty if
+	// the recording of links was not requested.
+	Signals [][]float64
+
+	// The flag to indicate whether links signals should be recorded
+	recordLinks bool
+}
+
+// NewActivationTrace creates new empty activation trace. If recordLinks is true the signals relayed by links will be
+// recorded in addition to the nodes activation.
+func NewActivationTrace(recordLinks bool) *ActivationTrace {
+	return &ActivationTrace{recordLinks: recordLinks}
+}
+
+// RecordsLinks returns true if this trace records signals of the links
+func (t *ActivationTrace) RecordsLinks() bool {
+	return t.recordLinks
+}
+
+// Len returns the number of activation steps recorded
+func (t *ActivationTrace) Len() int {
+	return len(t.Outputs)
+}
+
+// NodeIndex returns the index of the column with data of the node with given ID or -1 if node is not recorded
+func (t *ActivationTrace) NodeIndex(id int) int {
+	for i, nid := range t.NodeIds {
+		if nid == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// NodeActivation returns the activation sum and output of the node with given ID at specified step. It returns false
+// if either node is not recorded or step is out of range.
+func (t *ActivationTrace) NodeActivation(step, id int) (sum, output float64, ok bool) {
+	index := t.NodeIndex(id)
+	if index < 0 || step < 0 || step >= t.Len() {
+		return 0, 0, false
+	}
+	return t.Sums[step][index], t.Outputs[step][index], true
+}
+
+// LinkSignal returns the signal relayed by the link between nodes with given IDs at specified step. It returns false
+// if either link is not recorded or step is out of range.
+func (t *ActivationTrace) LinkSignal(step, inId, outId int) (float64, bool) {
+	if step < 0 || step >= len(t.Signals) {
+		return 0, false
+	}
+	for i, l := range t.Links {
+		if l.InId == inId && l.OutId == outId {
+			return t.Signals[step][i], true
+		}
+	}
+	return 0, false
+}
+
+// Reset removes all recorded steps from this trace
+func (t *ActivationTrace) Reset() {
+	t.NodeIds, t.Links = nil, nil
+	t.Sums, t.Outputs, t.Signals = nil, nil, nil
+}
+
+// begin checks that trace is consistent with the structure of the solver and initializes it if needed.
+func (t *ActivationTrace) begin(nodeIds func() []int, links func() []TracedLink) error {
+	if t.Len() == 0 {
+		t.NodeIds = nodeIds()
+		if t.recordLinks {
+			t.Links = links()
+		}
+		return nil
+	}
+	if len(t.NodeIds) != len(nodeIds()) || (t.recordLinks && len(t.Links) != len(links())) {
+		return ErrTraceStructureMismatch
+	}
+	return nil
+}
+
+// WriteCSV writes this trace in the CSV format. Each row holds data of one activation step with columns: step,
+// sum_<node id> and out_<node id> per node, and signal_<in id>-<out id> per link.
+func (t *ActivationTrace) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	header := make([]string, 0, 1+len(t.NodeIds)*2+len(t.Links))
+	header = append(header, "step")
+	for _, id := range t.NodeIds {
+		header = append(header, fmt.Sprintf("sum_%d", id), fmt.Sprintf("out_%d", id))
+	}
+	for _, l := range t.Links {
+		header = append(header, fmt.Sprintf("signal_%d-%d", l.InId, l.OutId))
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	formatFloat := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	for step := 0; step < t.Len(); step++ {
+		record := make([]string, 0, len(header))
+		record = append(record, strconv.Itoa(step))
+		for i := range t.NodeIds {
+			record = append(record, formatFloat(t.Sums[step][i]), formatFloat(t.Outputs[step][i]))
+		}
+		if len(t.Links) > 0 {
+			for _, signal := range t.Signals[step] {
+				record = append(record, formatFloat(signal))
+			}
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteNPZ writes this trace into the NPZ file with the following arrays:
+// - node_ids - the IDs of the recorded nodes
+// - sums - the activation sums matrix (steps x nodes)
+// - outputs - the activation outputs matrix (steps x nodes)
+// - links - the IDs of source and target nodes of the recorded links matrix (links x 2), if links recorded
+// - signals - the signals matrix (steps x links), if links recorded
+func (t *ActivationTrace) WriteNPZ(w io.Writer) error {
+	if t.Len() == 0 || len(t.NodeIds) == 0 {
+		return ErrTraceIsEmpty
+	}
+	out := npz.NewWriter(w)
+	ids := make([]int64, len(t.NodeIds))
+	for i, id := range t.NodeIds {
+		ids[i] = int64(id)
+	}
+	if err := out.Write("node_ids", ids); err != nil {
+		return err
+	}
+	if err := out.Write("sums", denseFromRows(t.Sums)); err != nil {
+		return err
+	}
+	if err := out.Write("outputs", denseFromRows(t.Outputs)); err != nil {
+		return err
+	}
+	if len(t.Links) > 0 {
+		links := mat.NewDense(len(t.Links), 2, nil)
+		for i, l := range t.Links {
+			links.Set(i, 0, float64(l.InId))
+			links.Set(i, 1, float64(l.OutId))
+		}
+		if err := out.Write("links", links); err != nil {
+			return err
+		}
+		if err := out.Write("signals", denseFromRows(t.Signals)); err != nil {
+			return err
+		}
+	}
+	return out.Close()
+}
+
+func denseFromRows(rows [][]float64) *mat.Dense {
+	m := mat.NewDense(len(rows), len(rows[0]), nil)
+	for i, row := range rows {
+		m.SetRow(i, row)
+	}
+	return m
+}
+
+// SetActivationTrace sets the trace to record activation of this network at each activation step. The nil value
+// stops recording.
+func (n *Network) SetActivationTrace(trace *ActivationTrace) {
+	n.trace = trace
+}
+
+// ActivationTrace returns the activation trace attached to this network or nil
+func (n *Network) ActivationTrace() *ActivationTrace {
+	return n.trace
+}
+
+// recordActivationStep appends current activation state of this network and provided signals relayed by links during
+// activation step to the attached trace if any.
+func (n *Network) recordActivationStep(signals []float64) error {
+	if n.trace == nil {
+		return nil
+	}
+	err := n.trace.begin(func() []int {
+		return nodeIds(n.allNodes)
+	}, func() []TracedLink {
+		links := make([]TracedLink, 0)
+		for _, node := range n.allNodes {
+			if node.IsNeuron() {
+				for _, l := range node.Incoming {
+					links = append(links, TracedLink{InId: l.InNode.Id, OutId: l.OutNode.Id})
+				}
+			}
+		}
+		return links
+	})
+	if err != nil {
+		return err
+	}
+
+	sums := make([]float64, len(n.allNodes))
+	outputs := make([]float64, len(n.allNodes))
+	for i, node := range n.allNodes {
+		sums[i] = node.ActivationSum
+		outputs[i] = node.Activation
+	}
+	n.trace.Sums = append(n.trace.Sums, sums)
+	n.trace.Outputs = append(n.trace.Outputs, outputs)
+
+	if n.trace.recordLinks {
+		n.trace.Signals = append(n.trace.Signals, signals)
+	}
+	return nil
+}
+
+// SetActivationTrace sets the trace to record activation of this solver at each activation step. The nil value
+// stops recording. The nodes are recorded with IDs of the network this solver was created from or with their indexes
+// if the solver was created directly.
+func (s *FastModularNetworkSolver) SetActivationTrace(trace *ActivationTrace) {
+	s.trace = trace
+	s.traceSums = nil
+	if trace != nil {
+		s.traceSums = make([]float64, s.totalNeuronCount)
+	}
+}
+
+// ActivationTrace returns the activation trace attached to this solver or nil
+func (s *FastModularNetworkSolver) ActivationTrace() *ActivationTrace {
+	return s.trace
+}
+
+// neuronId returns the ID of neuron at given index
+func (s *FastModularNetworkSolver) neuronId(index int) int {
+	if s.neuronIds != nil {
+		return s.neuronIds[index]
+	}
+	return index
+}
+
+// traceLinkSignals returns the signals to be relayed by links during the next activation step if links are traced
+func (s *FastModularNetworkSolver) traceLinkSignals() []float64 {
+	if s.trace == nil || !s.trace.recordLinks {
+		return nil
+	}
+	signals := make([]float64, len(s.incomingSources))
+	for j, source := range s.incomingSources {
+		signals[j] = s.neuronSignals[source] * s.incomingWeights[j]
+	}
+	return signals
+}
+
+// recordActivationStep appends current activation state of this solver and provided signals relayed by links during
+// activation step to the attached trace if any. The activation sums must be collected into traceSums beforehand.
+func (s *FastModularNetworkSolver) recordActivationStep(signals []float64) error {
+	if s.trace == nil {
+		return nil
+	}
+	err := s.trace.begin(func() []int {
+		ids := make([]int, s.totalNeuronCount)
+		for i := range ids {
+			ids[i] = s.neuronId(i)
+		}
+		return ids
+	}, func() []TracedLink {
+		links := make([]TracedLink, len(s.incomingSources))
+		for i := 0; i < s.totalNeuronCount; i++ {
+			for j := s.incomingOffsets[i]; j < s.incomingOffsets[i+1]; j++ {
+				links[j] = TracedLink{InId: s.neuronId(s.incomingSources[j]), OutId: s.neuronId(i)}
+			}
+		}
+		return links
+	})
+	if err != nil {
+		return err
+	}
+
+	sums := make([]float64, s.totalNeuronCount)
+	copy(sums, s.traceSums)
+	outputs := make([]float64, s.totalNeuronCount)
+	copy(outputs, s.neuronSignals)
+	s.trace.Sums = append(s.trace.Sums, sums)
+	s.trace.Outputs = append(s.trace.Outputs, outputs)
+
+	if s.trace.recordLinks {
+		s.trace.Signals = append(s.trace.Signals, signals)
+	}
+	return nil
+}
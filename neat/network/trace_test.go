@@ -0,0 +1,228 @@
+package network
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/sbinet/npyio/npz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gonum.org/v1/gonum/mat"
+	"testing"
+)
+
+func TestNetwork_SetActivationTrace(t *testing.T) {
+	net := buildNetwork()
+	trace := NewActivationTrace(true)
+	net.SetActivationTrace(trace)
+	assert.Equal(t, trace, net.ActivationTrace())
+
+	err := net.LoadSensors([]float64{0.5, 1.1})
+	require.NoError(t, err)
+	steps := 4
+	_, err = net.ForwardSteps(steps)
+	require.NoError(t, err)
+
+	// each ForwardSteps iteration activates the network at least once
+	require.True(t, trace.Len() >= steps)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, trace.NodeIds)
+	assert.Len(t, trace.Links, 8)
+	assert.Len(t, trace.Sums, trace.Len())
+	assert.Len(t, trace.Signals, trace.Len())
+
+	// the last step holds the current state of the network
+	last := trace.Len() - 1
+	for _, node := range net.allNodes {
+		sum, out, ok := trace.NodeActivation(last, node.Id)
+		require.True(t, ok, "node %d not traced", node.Id)
+		assert.Equal(t, node.ActivationSum, sum, "wrong sum of node %d", node.Id)
+		assert.Equal(t, node.Activation, out, "wrong output of node %d", node.Id)
+	}
+
+	// the signals relayed at the step are the outputs of the source nodes at the previous step multiplied by weight
+	for _, node := range net.allNodes {
+		for _, l := range node.Incoming {
+			signal, ok := trace.LinkSignal(last, l.InNode.Id, l.OutNode.Id)
+			require.True(t, ok)
+			_, prevOut, _ := trace.NodeActivation(last-1, l.InNode.Id)
+			assert.Equal(t, prevOut*l.ConnectionWeight, signal)
+		}
+	}
+
+	// stop tracing
+	net.SetActivationTrace(nil)
+	steps = trace.Len()
+	_, err = net.ForwardSteps(2)
+	require.NoError(t, err)
+	assert.Equal(t, steps, trace.Len())
+}
+
+func TestNetwork_SetActivationTrace_noLinks(t *testing.T) {
+	net := buildModularNetwork()
+	trace := NewActivationTrace(false)
+	net.SetActivationTrace(trace)
+
+	err := net.LoadSensors([]float64{1.0, 2.0})
+	require.NoError(t, err)
+	_, err = net.Activate()
+	require.NoError(t, err)
+
+	require.True(t, trace.Len() > 0)
+	assert.Len(t, trace.NodeIds, len(net.allNodes))
+	assert.Empty(t, trace.Links)
+	assert.Empty(t, trace.Signals)
+	_, ok := trace.LinkSignal(0, 1, 4)
+	assert.False(t, ok)
+	// control node is not traced
+	_, _, ok = trace.NodeActivation(0, net.controlNodes[0].Id)
+	assert.False(t, ok)
+}
+
+func TestFastModularNetworkSolver_SetActivationTrace(t *testing.T) {
+	net := buildNetwork()
+	solver, err := net.FastNetworkSolver()
+	require.NoError(t, err)
+	fmm := solver.(*FastModularNetworkSolver)
+	trace := NewActivationTrace(true)
+	fmm.SetActivationTrace(trace)
+	assert.Equal(t, trace, fmm.ActivationTrace())
+
+	err = fmm.LoadSensors([]float64{0.5, 1.1})
+	require.NoError(t, err)
+	steps := 5
+	_, err = fmm.ForwardSteps(steps)
+	require.NoError(t, err)
+
+	require.Equal(t, steps, trace.Len())
+	// the neurons are ordered as bias, inputs, outputs, hidden and recorded with the IDs of the network nodes
+	assert.Equal(t, []int{3, 1, 2, 7, 8, 4, 5, 6}, trace.NodeIds)
+	// the bias links are stored as biases of the neurons
+	assert.Len(t, trace.Links, 7)
+
+	outputs := fmm.ReadOutputs()
+	for i, node := range net.Outputs {
+		_, out, ok := trace.NodeActivation(steps-1, node.Id)
+		require.True(t, ok)
+		assert.Equal(t, outputs[i], out)
+	}
+
+	// HIDDEN 5 gets the BIAS as neuron bias
+	sum, _, ok := trace.NodeActivation(steps-1, 5)
+	require.True(t, ok)
+	_, in, _ := trace.NodeActivation(steps-2, 2)
+	assert.Equal(t, in*5.0+1.0, sum)
+
+	signal, ok := trace.LinkSignal(steps-1, 5, 6)
+	require.True(t, ok)
+	_, out, _ := trace.NodeActivation(steps-2, 5)
+	assert.Equal(t, out*17.0, signal)
+
+	// clones do not share the trace
+	clone := fmm.Clone().(*FastModularNetworkSolver)
+	assert.Nil(t, clone.ActivationTrace())
+}
+
+func TestFastModularNetworkSolver_SetActivationTrace_recursive(t *testing.T) {
+	solver, err := buildNetwork().FastNetworkSolver()
+	require.NoError(t, err)
+	fmm := solver.(*FastModularNetworkSolver)
+	trace := NewActivationTrace(false)
+	fmm.SetActivationTrace(trace)
+
+	err = fmm.LoadSensors([]float64{0.5, 1.1})
+	require.NoError(t, err)
+	_, err = fmm.RecursiveSteps()
+	require.NoError(t, err)
+
+	require.Equal(t, 1, trace.Len())
+	_, out, ok := trace.NodeActivation(0, 8)
+	require.True(t, ok)
+	assert.Equal(t, fmm.ReadOutputs()[1], out)
+}
+
+func TestActivationTrace_structureMismatch(t *testing.T) {
+	trace := NewActivationTrace(false)
+	net := buildNetwork()
+	net.SetActivationTrace(trace)
+	_, err := net.Activate()
+	require.NoError(t, err)
+
+	plain := buildPlainNetwork()
+	plain.SetActivationTrace(trace)
+	_, err = plain.Activate()
+	assert.ErrorIs(t, err, ErrTraceStructureMismatch)
+
+	// after reset the trace can be reused
+	trace.Reset()
+	_, err = plain.Activate()
+	assert.NoError(t, err)
+	assert.Len(t, trace.NodeIds, len(plain.allNodes))
+}
+
+func TestActivationTrace_WriteCSV(t *testing.T) {
+	trace := &ActivationTrace{
+		NodeIds: []int{1, 2},
+		Links:   []TracedLink{{InId: 1, OutId: 2}},
+		Sums:    [][]float64{{0, 0}, {0, 0.5}},
+		Outputs: [][]float64{{1, 0}, {1, 0.25}},
+		Signals: [][]float64{{0}, {0.5}},
+	}
+	var buf bytes.Buffer
+	err := trace.WriteCSV(&buf)
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	expected := [][]string{
+		{"step", "sum_1", "out_1", "sum_2", "out_2", "signal_1-2"},
+		{"0", "0", "1", "0", "0", "0"},
+		{"1", "0", "1", "0.5", "0.25", "0.5"},
+	}
+	assert.Equal(t, expected, records)
+}
+
+func TestActivationTrace_WriteNPZ(t *testing.T) {
+	net := buildNetwork()
+	trace := NewActivationTrace(true)
+	net.SetActivationTrace(trace)
+	err := net.LoadSensors([]float64{0.5, 1.1})
+	require.NoError(t, err)
+	_, err = net.ForwardSteps(3)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = trace.WriteNPZ(&buf)
+	require.NoError(t, err)
+
+	r, err := npz.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	var ids []int64
+	err = r.Read("node_ids", &ids)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8}, ids)
+
+	outputs := &mat.Dense{}
+	err = r.Read("outputs", outputs)
+	require.NoError(t, err)
+	rows, cols := outputs.Dims()
+	assert.Equal(t, trace.Len(), rows)
+	assert.Equal(t, len(trace.NodeIds), cols)
+	assert.Equal(t, trace.Outputs[rows-1], mat.Row(nil, rows-1, outputs))
+
+	signals := &mat.Dense{}
+	err = r.Read("signals", signals)
+	require.NoError(t, err)
+	_, cols = signals.Dims()
+	assert.Equal(t, len(trace.Links), cols)
+
+	links := &mat.Dense{}
+	err = r.Read("links", links)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 4}, mat.Row(nil, 0, links))
+}
+
+func TestActivationTrace_WriteNPZ_empty(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewActivationTrace(false).WriteNPZ(&buf)
+	assert.ErrorIs(t, err, ErrTraceIsEmpty)
+}